@@ -27,6 +27,7 @@ import OffersPage from "./pages/OffersPage";
 import OfferPage from "./pages/OfferPage";
 import hasRole from "./util/hasRole";
 import ProposalCreationPage from "./pages/entrepreneur/ProposalCreationPage";
+import { clearSession, refreshSession, revokeSession } from "./util/session";
 
 const App: React.FC = () => {
   const [isLoading, setIsLoading] = useState<boolean>(false);
@@ -58,12 +59,12 @@ const App: React.FC = () => {
   };
 
   const handleLogout = () => {
+    revokeSession(apiUrl);
     setIsAuth(false);
     setUser(undefined);
-    localStorage.removeItem("token");
+    clearSession();
     localStorage.removeItem("userID");
     localStorage.removeItem("publicWalletAddress");
-    localStorage.removeItem("expiryDate");
     deactivate();
     navigate("/");
   };
@@ -135,9 +136,47 @@ const App: React.FC = () => {
       setIsLoading(false);
       return;
     }
+
+    // The access token is short-lived, get a fresh one if it already expired
+    const tokenExpiryStr = localStorage.getItem("tokenExpiryDate");
+    if (tokenExpiryStr && Date.now() > new Date(tokenExpiryStr).getTime()) {
+      refreshSession(apiUrl).then((newToken) => {
+        if (!newToken) {
+          handleLogout();
+          return;
+        }
+        handleLogin(newToken);
+      });
+      return;
+    }
     handleLogin(token);
   }, [handleLogin]);
 
+  // Refresh the access token a minute before it expires while logged in
+  useEffect(() => {
+    if (!isAuth) return;
+
+    let timer: ReturnType<typeof setTimeout>;
+    const scheduleRefresh = () => {
+      const tokenExpiryStr = localStorage.getItem("tokenExpiryDate");
+      if (!tokenExpiryStr) return;
+
+      const delay = new Date(tokenExpiryStr).getTime() - Date.now() - 60 * 1000;
+      timer = setTimeout(async () => {
+        const newToken = await refreshSession(apiUrl);
+        if (!newToken) {
+          showFeedback("Your session has ended, please log in again.", false);
+          handleLogout();
+          return;
+        }
+        scheduleRefresh();
+      }, Math.max(delay, 0));
+    };
+
+    scheduleRefresh();
+    return () => clearTimeout(timer);
+  }, [isAuth, apiUrl]);
+
   useEffect(() => {
     window.scrollTo(0, 0);
   }, [location]);
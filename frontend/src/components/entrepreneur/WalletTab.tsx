@@ -77,14 +77,36 @@ const WalletTab: React.FC<WalletTabProps> = ({ publicUserAddress }) => {
           );
         }
       } else {
-        // if not its the first the time save the users wallet
+        // if not its the first the time prove the user controls the wallet, then save it
+        const challengeRes = await fetch(
+          `${apiUrl}/user/wallet/challenge?address=${encodeURIComponent(
+            wallet
+          )}`,
+          {
+            headers: {
+              Authorization: `Bearer ${token}`,
+            },
+          }
+        );
+        const challengeData = await challengeRes.json();
+        if (challengeData.error) throw challengeData.error;
+
+        const signature = await ethereum.request({
+          method: "personal_sign",
+          params: [challengeData.message, wallet],
+        });
+
         const res = await fetch(`${apiUrl}/user/wallet`, {
           method: "PUT",
           headers: {
             "Content-Type": "application/json",
             Authorization: `Bearer ${token}`,
           },
-          body: JSON.stringify({ publicWalletAddress: wallet }),
+          body: JSON.stringify({
+            publicWalletAddress: wallet,
+            nonce: challengeData.nonce,
+            signature,
+          }),
         });
         const resData = await res.json();
         if (resData.error) throw resData.error;
@@ -96,7 +118,7 @@ const WalletTab: React.FC<WalletTabProps> = ({ publicUserAddress }) => {
       revokeAccountsPermission();
       deactivate();
       showFeedback(
-        err.message || "Could not link wallet—connection rolled back",
+        err.message || err.msg || "Could not link wallet—connection rolled back",
         false
       );
     } finally {
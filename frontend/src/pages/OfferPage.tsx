@@ -198,7 +198,7 @@ const OfferPage: React.FC<OfferPageProps> = ({ user }) => {
               overflow: "hidden",
               borderTop: 5,
               borderColor:
-                offer.status === "Open" ? "success.main" : "grey.400",
+                offer.status === "Submission" ? "success.main" : "grey.400",
             }}
           >
             {/* Header section with tender info and status */}
@@ -488,7 +488,7 @@ const OfferPage: React.FC<OfferPageProps> = ({ user }) => {
                           </Typography>
                         </Box>
                         <Button
-                          onClick={() => authFileDownload(doc.documentPath)}
+                          onClick={() => authFileDownload(doc)}
                           variant="outlined"
                           size="small"
                         >
@@ -647,7 +647,7 @@ const OfferPage: React.FC<OfferPageProps> = ({ user }) => {
                 >
                   Back to Offers
                 </Button>
-                {hasRole(user, "entrepreneur") && offer.status === "Open" && (
+                {hasRole(user, "entrepreneur") && offer.status === "Submission" && (
                   <Button
                     variant="contained"
                     color="primary"
@@ -1,9 +1,20 @@
 import React, { useState, useCallback } from "react";
-import { Box, Typography, Link, CardContent, Card } from "@mui/material";
+import {
+  Box,
+  Typography,
+  Link,
+  CardContent,
+  Card,
+  Button,
+  Divider,
+} from "@mui/material";
+import AccountBalanceWalletIcon from "@mui/icons-material/AccountBalanceWallet";
 import LoginForm, { LoginFormProps } from "./LoginForm";
 import { useFeedback } from "../../FeedbackAlertContext";
 import { useNavigate } from "react-router-dom";
 import { ServerFormError } from "../../types";
+import { storeSession } from "../../util/session";
+
 type LoginPageProps = {
   handleLogin: (toke: string) => void;
 };
@@ -104,9 +115,7 @@ const LoginPage: React.FC<LoginPageProps> = ({ handleLogin }) => {
         throw resData.error;
       }
 
-      localStorage.setItem("token", resData.token);
-      const expiryDate = new Date(new Date().getTime() + 60 * 60 * 12 * 1000);
-      localStorage.setItem("expiryDate", expiryDate.toISOString());
+      storeSession(resData);
       localStorage.setItem("publicWalletAddress", resData.publicWalletAddress);
       localStorage.setItem("userID", resData.userID);
 
@@ -122,6 +131,65 @@ const LoginPage: React.FC<LoginPageProps> = ({ handleLogin }) => {
     }
   };
 
+  // Sign-In With Ethereum: sign the server nonce message with the wallet bound to the account
+  const handleWalletLogin = async () => {
+    const { ethereum } = window as any;
+    if (isSending) return;
+    if (!ethereum) {
+      showFeedback(
+        "No browser wallet found, install MetaMask to continue.",
+        false
+      );
+      return;
+    }
+    setIsSending(true);
+
+    try {
+      const [wallet] = await ethereum.request({
+        method: "eth_requestAccounts",
+      });
+      if (!wallet) throw new Error("No account selected");
+
+      const nonceRes = await fetch(
+        `${apiUrl}/siwe/nonce?address=${encodeURIComponent(wallet)}`
+      );
+      const nonceData = await nonceRes.json();
+      if (nonceData.error) throw nonceData.error;
+
+      const signature = await ethereum.request({
+        method: "personal_sign",
+        params: [nonceData.message, wallet],
+      });
+
+      const res = await fetch(`${apiUrl}/siwe/verify`, {
+        method: "POST",
+        body: JSON.stringify({ message: nonceData.message, signature }),
+        headers: {
+          "Content-Type": "application/json",
+        },
+      });
+      const resData = await res.json();
+      if (resData.error) throw resData.error;
+
+      storeSession(resData);
+      localStorage.setItem("publicWalletAddress", resData.publicWalletAddress);
+      localStorage.setItem("userID", resData.userID);
+
+      showFeedback("You are logged in! Redirecting to your dashboard...", true);
+      setTimeout(() => {
+        handleLogin(resData.token);
+        navigate("/");
+      }, 2500);
+    } catch (err: any) {
+      showFeedback(
+        err?.msg || err?.message || "Could not sign in with your wallet.",
+        false
+      );
+    } finally {
+      setIsSending(false);
+    }
+  };
+
   return (
     <Box
       sx={{
@@ -143,6 +211,16 @@ const LoginPage: React.FC<LoginPageProps> = ({ handleLogin }) => {
             inputChangeHandler={inputChangeHandler}
             handleSubmitLogin={handleSubmitLogin}
           />
+          <Divider sx={{ my: 2 }}>or</Divider>
+          <Button
+            fullWidth
+            variant="outlined"
+            startIcon={<AccountBalanceWalletIcon />}
+            disabled={isSending}
+            onClick={handleWalletLogin}
+          >
+            Sign in with your wallet
+          </Button>
         </CardContent>
       </Card>
       <Box sx={{ display: "flex", alignItems: "center", gap: 0.5 }}>
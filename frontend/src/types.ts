@@ -31,7 +31,14 @@ export interface Offer {
   proposalReviewStart: string; // ISO timestamp
   proposalReviewEnd: string; // ISO timestamp
   minQualificationLevel?: string;
-  status: "Open" | "Closed" | string;
+  status:
+    | "NotStarted"
+    | "Submission"
+    | "AwaitingReview"
+    | "Review"
+    | "Ended"
+    | "WinnerDeclared"
+    | "Closed";
   CreatedBy: number;
   CreatedAt: string; // ISO timestamp
   UpdatedAt: string; // ISO timestamp
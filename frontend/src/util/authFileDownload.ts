@@ -1,9 +1,11 @@
-export const authFileDownload = async (filePath: string) => {
+import { Document } from "../types";
+
+export const authFileDownload = async (doc: Document) => {
   try {
     const token = localStorage.getItem("token");
     const apiUrl = import.meta.env.VITE_API_URL;
 
-    const response = await fetch(`${apiUrl.split("/api/")[0]}/${filePath}`, {
+    const response = await fetch(`${apiUrl}/documents/${doc.ID}`, {
       headers: {
         Authorization: `Bearer ${token}`,
       },
@@ -11,7 +13,12 @@ export const authFileDownload = async (filePath: string) => {
 
     if (!response.ok) throw new Error("Download failed");
 
-    const fileName = filePath.split("/")[2];
+    const fileName =
+      response.headers
+        .get("Content-Disposition")
+        ?.match(/filename="?([^"]+)"?/)?.[1] ??
+      doc.documentPath.split("/").pop() ??
+      `document-${doc.ID}`;
 
     const blob = await response.blob();
     const downloadUrl = window.URL.createObjectURL(blob);
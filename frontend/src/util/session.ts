@@ -0,0 +1,65 @@
+export interface SessionTokens {
+  token: string;
+  expiresAt: string;
+  refreshToken: string;
+  refreshExpiresAt: string;
+}
+
+// storeSession keeps the token pair returned by /login and /token/refresh
+export const storeSession = (tokens: SessionTokens) => {
+  localStorage.setItem("token", tokens.token);
+  localStorage.setItem("tokenExpiryDate", tokens.expiresAt);
+  localStorage.setItem("refreshToken", tokens.refreshToken);
+  // the session ends when the refresh token expires
+  localStorage.setItem("expiryDate", tokens.refreshExpiresAt);
+};
+
+export const clearSession = () => {
+  localStorage.removeItem("token");
+  localStorage.removeItem("tokenExpiryDate");
+  localStorage.removeItem("refreshToken");
+  localStorage.removeItem("expiryDate");
+};
+
+// refreshSession rotates the refresh token and returns the new access token,
+// or null when the session can no longer be refreshed
+export const refreshSession = async (
+  apiUrl: string
+): Promise<string | null> => {
+  const refreshToken = localStorage.getItem("refreshToken");
+  if (!refreshToken) return null;
+
+  try {
+    const res = await fetch(`${apiUrl}/token/refresh`, {
+      method: "POST",
+      body: JSON.stringify({ refreshToken }),
+      headers: {
+        "Content-Type": "application/json",
+      },
+    });
+    if (!res.ok) return null;
+
+    const resData: SessionTokens = await res.json();
+    storeSession(resData);
+    return resData.token;
+  } catch (err) {
+    return null;
+  }
+};
+
+// revokeSession tells the server to end the current session, failures are ignored
+export const revokeSession = async (apiUrl: string) => {
+  const token = localStorage.getItem("token");
+  if (!token) return;
+
+  try {
+    await fetch(`${apiUrl}/logout`, {
+      method: "POST",
+      headers: {
+        Authorization: `Bearer ${token}`,
+      },
+    });
+  } catch (err) {
+    // the access token expires on its own
+  }
+};
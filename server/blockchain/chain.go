@@ -0,0 +1,87 @@
+package blockchain
+
+import (
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/store"
+)
+
+// Chain is what the API needs from the blockchain: verifying the transactions users report,
+// reading offer state and comparing on-chain roles with the DB. Handlers depend on it so they can run against a fake.
+type Chain interface {
+	VerifyOfferContract(offerAddress string, tenderAddr string, submitted OfferWindows) error
+	VerifyProposalSubmitted(txHash string, offerAddress string, entrepreneurAddr string) (*ProposalSubmittedEvent, error)
+	VerifyProposalReviewed(txHash string, offerAddress string, entrepreneurAddr string, expertAddr string) (*ProposalReviewedEvent, error)
+	VerifyWinnerDeclared(txHash string, offerAddress string) (*WinnerDeclaredEvent, error)
+	VerifyOfferClosed(txHash string, offerAddress string) (*OfferClosedEvent, error)
+	VerifyRoleGranted(txHash string, roleName string, account string) error
+	VerifyRoleRevoked(txHash string, roleName string, account string) error
+	GetOfferState(offerAddress string) (*OfferState, error)
+	TrackTransaction(ref TxRef) error
+	ReconcileRoles(policy string) (*RoleReconciliation, error)
+	LastRoleReconciliation() *RoleReconciliation
+}
+
+// NodeChain implements Chain against an RPC node reached through its client, tracking transactions in a store
+type NodeChain struct {
+	store       store.Store
+	client      Client
+	reconciler  *RoleReconciler
+	offerStates *offerStateCache
+}
+
+var _ Chain = (*NodeChain)(nil)
+
+// NewNodeChain returns a Chain reading the node and the OfferFactory of client, caching offer states
+// as cfg says. Roles are reconciled by reconciler so the runs triggered through the API and the
+// background ones share their outcome.
+func NewNodeChain(s store.Store, client Client, cfg config.Config, reconciler *RoleReconciler) (*NodeChain, error) {
+	offerStates, err := newOfferStateCache(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &NodeChain{store: s, client: client, reconciler: reconciler, offerStates: offerStates}, nil
+}
+
+func (c *NodeChain) VerifyOfferContract(offerAddress string, tenderAddr string, submitted OfferWindows) error {
+	return VerifyOfferContract(c.client, offerAddress, tenderAddr, submitted)
+}
+
+func (c *NodeChain) VerifyProposalSubmitted(txHash string, offerAddress string, entrepreneurAddr string) (*ProposalSubmittedEvent, error) {
+	return VerifyProposalSubmitted(c.client, txHash, offerAddress, entrepreneurAddr)
+}
+
+func (c *NodeChain) VerifyProposalReviewed(txHash string, offerAddress string, entrepreneurAddr string, expertAddr string) (*ProposalReviewedEvent, error) {
+	return VerifyProposalReviewed(c.client, txHash, offerAddress, entrepreneurAddr, expertAddr)
+}
+
+func (c *NodeChain) VerifyWinnerDeclared(txHash string, offerAddress string) (*WinnerDeclaredEvent, error) {
+	return VerifyWinnerDeclared(c.client, txHash, offerAddress)
+}
+
+func (c *NodeChain) VerifyOfferClosed(txHash string, offerAddress string) (*OfferClosedEvent, error) {
+	return VerifyOfferClosed(c.client, txHash, offerAddress)
+}
+
+func (c *NodeChain) VerifyRoleGranted(txHash string, roleName string, account string) error {
+	return VerifyRoleGranted(c.client, txHash, roleName, account)
+}
+
+func (c *NodeChain) VerifyRoleRevoked(txHash string, roleName string, account string) error {
+	return VerifyRoleRevoked(c.client, txHash, roleName, account)
+}
+
+func (c *NodeChain) GetOfferState(offerAddress string) (*OfferState, error) {
+	return c.offerStates.get(c.client, offerAddress)
+}
+
+func (c *NodeChain) TrackTransaction(ref TxRef) error {
+	return TrackTransaction(c.store.ChainTransactions(), ref)
+}
+
+func (c *NodeChain) ReconcileRoles(policy string) (*RoleReconciliation, error) {
+	return c.reconciler.Reconcile(policy)
+}
+
+func (c *NodeChain) LastRoleReconciliation() *RoleReconciliation {
+	return c.reconciler.Last()
+}
@@ -0,0 +1,146 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/Brondont/trust-api/blockchain/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Client is the node access the package needs: role checks on the OfferFactory,
+// receipt and log fetching, view calls on Offer contracts and sending relayed transactions
+type Client interface {
+	// FactoryAddress is the OfferFactory the client checks roles against
+	FactoryAddress() common.Address
+	HasRole(ctx context.Context, role common.Hash, account common.Address) (bool, error)
+
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+
+	// Factory returns the typed binding of the OfferFactory
+	Factory() *contracts.OfferFactory
+	// Offer returns the typed binding of an Offer contract, for its view calls and event parsing
+	Offer(address common.Address) (*contracts.Offer, error)
+}
+
+// Backend is the part of an Ethereum node API the clients are built on,
+// implemented by ethclient.Client and the simulated backend client
+type Backend interface {
+	bind.ContractBackend
+	ethereum.BlockNumberReader
+	ethereum.TransactionReader
+	ethereum.ChainIDReader
+}
+
+// contractClient implements Client on top of a Backend
+type contractClient struct {
+	backend        Backend
+	factory        common.Address
+	factoryBinding *contracts.OfferFactory
+}
+
+func newContractClient(backend Backend, factory common.Address) (*contractClient, error) {
+	binding, err := contracts.NewOfferFactory(factory, backend)
+	if err != nil {
+		return nil, fmt.Errorf("binding OfferFactory: %w", err)
+	}
+
+	return &contractClient{
+		backend:        backend,
+		factory:        factory,
+		factoryBinding: binding,
+	}, nil
+}
+
+// NewEthClient returns a Client connected to the node at rpcURL
+func NewEthClient(rpcURL string, factory common.Address) (Client, error) {
+	client, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to blockchain: %w", err)
+	}
+	return newContractClient(client, factory)
+}
+
+func (c *contractClient) FactoryAddress() common.Address {
+	return c.factory
+}
+
+func (c *contractClient) HasRole(ctx context.Context, role common.Hash, account common.Address) (bool, error) {
+	hasRole, err := c.factoryBinding.HasRole(&bind.CallOpts{Context: ctx}, role, account)
+	if err != nil {
+		return false, fmt.Errorf("contract call failed: %w", DecodeContractError(err))
+	}
+	return hasRole, nil
+}
+
+func (c *contractClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.backend.BlockNumber(ctx)
+}
+
+func (c *contractClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return c.backend.HeaderByNumber(ctx, number)
+}
+
+func (c *contractClient) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return c.backend.TransactionReceipt(ctx, hash)
+}
+
+func (c *contractClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	return c.backend.TransactionByHash(ctx, hash)
+}
+
+func (c *contractClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return c.backend.FilterLogs(ctx, query)
+}
+
+func (c *contractClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.backend.CallContract(ctx, call, blockNumber)
+}
+
+func (c *contractClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return c.backend.ChainID(ctx)
+}
+
+func (c *contractClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return c.backend.PendingNonceAt(ctx, account)
+}
+
+func (c *contractClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.backend.SuggestGasTipCap(ctx)
+}
+
+func (c *contractClient) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return c.backend.EstimateGas(ctx, call)
+}
+
+func (c *contractClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return c.backend.SendTransaction(ctx, tx)
+}
+
+func (c *contractClient) Factory() *contracts.OfferFactory {
+	return c.factoryBinding
+}
+
+func (c *contractClient) Offer(address common.Address) (*contracts.Offer, error) {
+	offer, err := contracts.NewOffer(address, c.backend)
+	if err != nil {
+		return nil, fmt.Errorf("binding Offer %s: %w", address.Hex(), err)
+	}
+	return offer, nil
+}
@@ -0,0 +1,126 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Errors of the Offer and OfferFactory contracts, a *ContractError wraps one of them so callers
+// can test for it with errors.Is
+var (
+	ErrUnauthorized             = errors.New("your account is not allowed to do this on the offer")
+	ErrInvalidStage             = errors.New("the offer is not at the right stage for this action")
+	ErrInvalidScore             = errors.New("scores go from 0 to 10")
+	ErrAlreadyScored            = errors.New("this expert already reviewed the proposal")
+	ErrNoProposal               = errors.New("the entrepreneur has no proposal on this offer")
+	ErrProposalAlreadySubmitted = errors.New("a proposal was already submitted from this wallet")
+	ErrOfferClosed              = errors.New("the offer is closed")
+	ErrInvalidProposal          = errors.New("a proposal needs a description and a price above zero")
+	ErrNoProposalsSubmitted     = errors.New("no proposal was submitted to the offer")
+	ErrProposalNotFound         = errors.New("proposal not found on chain")
+	ErrMissingRole              = errors.New("the wallet does not hold the role required for this action")
+	ErrContractReverted         = errors.New("the contract rejected the call")
+)
+
+// Stages passed to InvalidStage, the stage the action requires
+const (
+	stageSubmission  = 1
+	stageReview      = 2
+	stageAfterReview = 3
+)
+
+// ContractError is a decoded revert of the platform contracts
+type ContractError struct {
+	// Name is the custom error, e.g. InvalidStage, empty for require messages
+	Name string
+	// Code identifies the failure in API responses, e.g. submission_period_closed
+	Code string
+	// Args are the arguments of the custom error by name
+	Args map[string]interface{}
+	// Reason is the decoded revert as the contract raised it, e.g. InvalidStage(currentStage=1)
+	Reason string
+
+	message string
+	kind    error
+}
+
+func (e *ContractError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the Err* value of the custom error
+func (e *ContractError) Unwrap() error {
+	return e.kind
+}
+
+// ErrorCode is written along the message by utils.WriteError
+func (e *ContractError) ErrorCode() string {
+	return e.Code
+}
+
+// newContractError describes the named custom error, require messages and unknown errors
+// keep reason as their message
+func newContractError(name string, args map[string]interface{}, reason string) *ContractError {
+	e := &ContractError{Name: name, Args: args, Reason: reason}
+
+	switch name {
+	case "Unauthorized":
+		e.Code, e.kind = "unauthorized", ErrUnauthorized
+	case "InvalidStage":
+		e.Code, e.kind = "invalid_stage", ErrInvalidStage
+		stage, _ := args["currentStage"].(uint8)
+		switch stage {
+		case stageSubmission:
+			e.Code, e.message = "submission_period_closed", "the proposal submission period is not open"
+		case stageReview:
+			e.Code, e.message = "review_period_closed", "the review period is not open"
+		case stageAfterReview:
+			e.Code, e.message = "review_period_not_ended", "the review period has not ended yet"
+		}
+	case "InvalidScore":
+		e.Code, e.kind = "invalid_score", ErrInvalidScore
+		if score, ok := args["provided"].(uint8); ok {
+			e.message = fmt.Sprintf("score %d is out of range, %s", score, ErrInvalidScore)
+		}
+	case "AlreadyScored":
+		e.Code, e.kind = "already_scored", ErrAlreadyScored
+	case "NoProposal":
+		e.Code, e.kind = "no_proposal", ErrNoProposal
+	case "ProposalAlreadySubmitted":
+		e.Code, e.kind = "proposal_already_submitted", ErrProposalAlreadySubmitted
+	case "OfferClosed":
+		e.Code, e.kind = "offer_closed", ErrOfferClosed
+	case "InvalidProposal":
+		e.Code, e.kind = "invalid_proposal", ErrInvalidProposal
+	case "NoProposalsSubmitted":
+		e.Code, e.kind = "no_proposals_submitted", ErrNoProposalsSubmitted
+	case "ProposalNotFound":
+		e.Code, e.kind = "proposal_not_found", ErrProposalNotFound
+	case "AccessControlUnauthorizedAccount":
+		e.Code, e.kind = "missing_role", ErrMissingRole
+		account, _ := args["account"].(common.Address)
+		role, _ := args["neededRole"].(common.Hash)
+		if name := roleNameForHash(role); name != "" {
+			e.message = fmt.Sprintf("wallet %s does not hold the %s role", account.Hex(), name)
+		}
+	default:
+		e.Code, e.kind, e.message = "contract_reverted", ErrContractReverted, reason
+	}
+
+	if e.message == "" {
+		e.message = e.kind.Error()
+	}
+	return e
+}
+
+// roleNameForHash returns the DB role name of an AccessControl role identifier, empty if unknown
+func roleNameForHash(role common.Hash) string {
+	for _, name := range []string{"admin", "tender", "entrepreneur", "expert"} {
+		if RoleHash(name) == role {
+			return name
+		}
+	}
+	return ""
+}
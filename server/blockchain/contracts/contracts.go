@@ -0,0 +1,20 @@
+// Package contracts holds the typed bindings of the Offer and OfferFactory contracts.
+// The ABIs and the OfferFactory bytecode are built from hardhat/contracts and embedded,
+// regenerate the bindings with go generate when the contracts change.
+package contracts
+
+import _ "embed"
+
+//go:generate abigen --abi Offer.json --pkg contracts --type Offer --out offer.go
+//go:generate abigen --abi OfferFactory.json --bin OfferFactory.bin --pkg contracts --type OfferFactory --out offerFactory.go
+//go:generate sed -i -e "s|^\tABI: \".*\",$|\tABI: offerABI,|" offer.go
+//go:generate sed -i -e "s|^\tABI: \".*\",$|\tABI: offerFactoryABI,|" -e "s|^\tBin: \".*\",$|\tBin: offerFactoryBin,|" offerFactory.go
+
+//go:embed Offer.json
+var offerABI string
+
+//go:embed OfferFactory.json
+var offerFactoryABI string
+
+//go:embed OfferFactory.bin
+var offerFactoryBin string
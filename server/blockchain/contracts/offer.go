@@ -0,0 +1,1553 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// OfferMetaData contains all meta data concerning the Offer contract.
+var OfferMetaData = &bind.MetaData{
+	ABI: offerABI,
+}
+
+// OfferABI is the input ABI used to generate the binding from.
+// Deprecated: Use OfferMetaData.ABI instead.
+var OfferABI = OfferMetaData.ABI
+
+// Offer is an auto generated Go binding around an Ethereum contract.
+type Offer struct {
+	OfferCaller     // Read-only binding to the contract
+	OfferTransactor // Write-only binding to the contract
+	OfferFilterer   // Log filterer for contract events
+}
+
+// OfferCaller is an auto generated read-only Go binding around an Ethereum contract.
+type OfferCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OfferTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type OfferTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OfferFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type OfferFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OfferSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type OfferSession struct {
+	Contract     *Offer            // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// OfferCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type OfferCallerSession struct {
+	Contract *OfferCaller  // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts // Call options to use throughout this session
+}
+
+// OfferTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type OfferTransactorSession struct {
+	Contract     *OfferTransactor  // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// OfferRaw is an auto generated low-level Go binding around an Ethereum contract.
+type OfferRaw struct {
+	Contract *Offer // Generic contract binding to access the raw methods on
+}
+
+// OfferCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type OfferCallerRaw struct {
+	Contract *OfferCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// OfferTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type OfferTransactorRaw struct {
+	Contract *OfferTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewOffer creates a new instance of Offer, bound to a specific deployed contract.
+func NewOffer(address common.Address, backend bind.ContractBackend) (*Offer, error) {
+	contract, err := bindOffer(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Offer{OfferCaller: OfferCaller{contract: contract}, OfferTransactor: OfferTransactor{contract: contract}, OfferFilterer: OfferFilterer{contract: contract}}, nil
+}
+
+// NewOfferCaller creates a new read-only instance of Offer, bound to a specific deployed contract.
+func NewOfferCaller(address common.Address, caller bind.ContractCaller) (*OfferCaller, error) {
+	contract, err := bindOffer(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferCaller{contract: contract}, nil
+}
+
+// NewOfferTransactor creates a new write-only instance of Offer, bound to a specific deployed contract.
+func NewOfferTransactor(address common.Address, transactor bind.ContractTransactor) (*OfferTransactor, error) {
+	contract, err := bindOffer(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferTransactor{contract: contract}, nil
+}
+
+// NewOfferFilterer creates a new log filterer instance of Offer, bound to a specific deployed contract.
+func NewOfferFilterer(address common.Address, filterer bind.ContractFilterer) (*OfferFilterer, error) {
+	contract, err := bindOffer(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFilterer{contract: contract}, nil
+}
+
+// bindOffer binds a generic wrapper to an already deployed contract.
+func bindOffer(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := OfferMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Offer *OfferRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Offer.Contract.OfferCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Offer *OfferRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Offer.Contract.OfferTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Offer *OfferRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Offer.Contract.OfferTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Offer *OfferCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Offer.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Offer *OfferTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Offer.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Offer *OfferTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Offer.Contract.contract.Transact(opts, method, params...)
+}
+
+// ENTREPRENEURROLE is a free data retrieval call binding the contract method 0x681eab8a.
+//
+// Solidity: function ENTREPRENEUR_ROLE() view returns(bytes32)
+func (_Offer *OfferCaller) ENTREPRENEURROLE(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "ENTREPRENEUR_ROLE")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ENTREPRENEURROLE is a free data retrieval call binding the contract method 0x681eab8a.
+//
+// Solidity: function ENTREPRENEUR_ROLE() view returns(bytes32)
+func (_Offer *OfferSession) ENTREPRENEURROLE() ([32]byte, error) {
+	return _Offer.Contract.ENTREPRENEURROLE(&_Offer.CallOpts)
+}
+
+// ENTREPRENEURROLE is a free data retrieval call binding the contract method 0x681eab8a.
+//
+// Solidity: function ENTREPRENEUR_ROLE() view returns(bytes32)
+func (_Offer *OfferCallerSession) ENTREPRENEURROLE() ([32]byte, error) {
+	return _Offer.Contract.ENTREPRENEURROLE(&_Offer.CallOpts)
+}
+
+// EXPERTROLE is a free data retrieval call binding the contract method 0xee23c3ee.
+//
+// Solidity: function EXPERT_ROLE() view returns(bytes32)
+func (_Offer *OfferCaller) EXPERTROLE(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "EXPERT_ROLE")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// EXPERTROLE is a free data retrieval call binding the contract method 0xee23c3ee.
+//
+// Solidity: function EXPERT_ROLE() view returns(bytes32)
+func (_Offer *OfferSession) EXPERTROLE() ([32]byte, error) {
+	return _Offer.Contract.EXPERTROLE(&_Offer.CallOpts)
+}
+
+// EXPERTROLE is a free data retrieval call binding the contract method 0xee23c3ee.
+//
+// Solidity: function EXPERT_ROLE() view returns(bytes32)
+func (_Offer *OfferCallerSession) EXPERTROLE() ([32]byte, error) {
+	return _Offer.Contract.EXPERTROLE(&_Offer.CallOpts)
+}
+
+// EntrepreneurReviewers is a free data retrieval call binding the contract method 0x58f5fb33.
+//
+// Solidity: function entrepreneurReviewers(address , uint256 ) view returns(address)
+func (_Offer *OfferCaller) EntrepreneurReviewers(opts *bind.CallOpts, arg0 common.Address, arg1 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "entrepreneurReviewers", arg0, arg1)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// EntrepreneurReviewers is a free data retrieval call binding the contract method 0x58f5fb33.
+//
+// Solidity: function entrepreneurReviewers(address , uint256 ) view returns(address)
+func (_Offer *OfferSession) EntrepreneurReviewers(arg0 common.Address, arg1 *big.Int) (common.Address, error) {
+	return _Offer.Contract.EntrepreneurReviewers(&_Offer.CallOpts, arg0, arg1)
+}
+
+// EntrepreneurReviewers is a free data retrieval call binding the contract method 0x58f5fb33.
+//
+// Solidity: function entrepreneurReviewers(address , uint256 ) view returns(address)
+func (_Offer *OfferCallerSession) EntrepreneurReviewers(arg0 common.Address, arg1 *big.Int) (common.Address, error) {
+	return _Offer.Contract.EntrepreneurReviewers(&_Offer.CallOpts, arg0, arg1)
+}
+
+// Entrepreneurs is a free data retrieval call binding the contract method 0x0d2f647b.
+//
+// Solidity: function entrepreneurs(uint256 ) view returns(address)
+func (_Offer *OfferCaller) Entrepreneurs(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "entrepreneurs", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Entrepreneurs is a free data retrieval call binding the contract method 0x0d2f647b.
+//
+// Solidity: function entrepreneurs(uint256 ) view returns(address)
+func (_Offer *OfferSession) Entrepreneurs(arg0 *big.Int) (common.Address, error) {
+	return _Offer.Contract.Entrepreneurs(&_Offer.CallOpts, arg0)
+}
+
+// Entrepreneurs is a free data retrieval call binding the contract method 0x0d2f647b.
+//
+// Solidity: function entrepreneurs(uint256 ) view returns(address)
+func (_Offer *OfferCallerSession) Entrepreneurs(arg0 *big.Int) (common.Address, error) {
+	return _Offer.Contract.Entrepreneurs(&_Offer.CallOpts, arg0)
+}
+
+// Factory is a free data retrieval call binding the contract method 0xc45a0155.
+//
+// Solidity: function factory() view returns(address)
+func (_Offer *OfferCaller) Factory(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "factory")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Factory is a free data retrieval call binding the contract method 0xc45a0155.
+//
+// Solidity: function factory() view returns(address)
+func (_Offer *OfferSession) Factory() (common.Address, error) {
+	return _Offer.Contract.Factory(&_Offer.CallOpts)
+}
+
+// Factory is a free data retrieval call binding the contract method 0xc45a0155.
+//
+// Solidity: function factory() view returns(address)
+func (_Offer *OfferCallerSession) Factory() (common.Address, error) {
+	return _Offer.Contract.Factory(&_Offer.CallOpts)
+}
+
+// GetEntrepreneurByIndex is a free data retrieval call binding the contract method 0xb29c8758.
+//
+// Solidity: function getEntrepreneurByIndex(uint256 index) view returns(address)
+func (_Offer *OfferCaller) GetEntrepreneurByIndex(opts *bind.CallOpts, index *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "getEntrepreneurByIndex", index)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// GetEntrepreneurByIndex is a free data retrieval call binding the contract method 0xb29c8758.
+//
+// Solidity: function getEntrepreneurByIndex(uint256 index) view returns(address)
+func (_Offer *OfferSession) GetEntrepreneurByIndex(index *big.Int) (common.Address, error) {
+	return _Offer.Contract.GetEntrepreneurByIndex(&_Offer.CallOpts, index)
+}
+
+// GetEntrepreneurByIndex is a free data retrieval call binding the contract method 0xb29c8758.
+//
+// Solidity: function getEntrepreneurByIndex(uint256 index) view returns(address)
+func (_Offer *OfferCallerSession) GetEntrepreneurByIndex(index *big.Int) (common.Address, error) {
+	return _Offer.Contract.GetEntrepreneurByIndex(&_Offer.CallOpts, index)
+}
+
+// GetProposal is a free data retrieval call binding the contract method 0xeb8b9811.
+//
+// Solidity: function getProposal(address entrepreneur) view returns(address, string, uint256, uint256, uint256)
+func (_Offer *OfferCaller) GetProposal(opts *bind.CallOpts, entrepreneur common.Address) (common.Address, string, *big.Int, *big.Int, *big.Int, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "getProposal", entrepreneur)
+
+	if err != nil {
+		return *new(common.Address), *new(string), *new(*big.Int), *new(*big.Int), *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	out1 := *abi.ConvertType(out[1], new(string)).(*string)
+	out2 := *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	out3 := *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	out4 := *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+
+	return out0, out1, out2, out3, out4, err
+
+}
+
+// GetProposal is a free data retrieval call binding the contract method 0xeb8b9811.
+//
+// Solidity: function getProposal(address entrepreneur) view returns(address, string, uint256, uint256, uint256)
+func (_Offer *OfferSession) GetProposal(entrepreneur common.Address) (common.Address, string, *big.Int, *big.Int, *big.Int, error) {
+	return _Offer.Contract.GetProposal(&_Offer.CallOpts, entrepreneur)
+}
+
+// GetProposal is a free data retrieval call binding the contract method 0xeb8b9811.
+//
+// Solidity: function getProposal(address entrepreneur) view returns(address, string, uint256, uint256, uint256)
+func (_Offer *OfferCallerSession) GetProposal(entrepreneur common.Address) (common.Address, string, *big.Int, *big.Int, *big.Int, error) {
+	return _Offer.Contract.GetProposal(&_Offer.CallOpts, entrepreneur)
+}
+
+// GetProposalCount is a free data retrieval call binding the contract method 0xc08cc02d.
+//
+// Solidity: function getProposalCount() view returns(uint256)
+func (_Offer *OfferCaller) GetProposalCount(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "getProposalCount")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetProposalCount is a free data retrieval call binding the contract method 0xc08cc02d.
+//
+// Solidity: function getProposalCount() view returns(uint256)
+func (_Offer *OfferSession) GetProposalCount() (*big.Int, error) {
+	return _Offer.Contract.GetProposalCount(&_Offer.CallOpts)
+}
+
+// GetProposalCount is a free data retrieval call binding the contract method 0xc08cc02d.
+//
+// Solidity: function getProposalCount() view returns(uint256)
+func (_Offer *OfferCallerSession) GetProposalCount() (*big.Int, error) {
+	return _Offer.Contract.GetProposalCount(&_Offer.CallOpts)
+}
+
+// GetReviewByExpert is a free data retrieval call binding the contract method 0x991009fe.
+//
+// Solidity: function getReviewByExpert(address entrepreneur, address expert) view returns(address, uint8)
+func (_Offer *OfferCaller) GetReviewByExpert(opts *bind.CallOpts, entrepreneur common.Address, expert common.Address) (common.Address, uint8, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "getReviewByExpert", entrepreneur, expert)
+
+	if err != nil {
+		return *new(common.Address), *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	out1 := *abi.ConvertType(out[1], new(uint8)).(*uint8)
+
+	return out0, out1, err
+
+}
+
+// GetReviewByExpert is a free data retrieval call binding the contract method 0x991009fe.
+//
+// Solidity: function getReviewByExpert(address entrepreneur, address expert) view returns(address, uint8)
+func (_Offer *OfferSession) GetReviewByExpert(entrepreneur common.Address, expert common.Address) (common.Address, uint8, error) {
+	return _Offer.Contract.GetReviewByExpert(&_Offer.CallOpts, entrepreneur, expert)
+}
+
+// GetReviewByExpert is a free data retrieval call binding the contract method 0x991009fe.
+//
+// Solidity: function getReviewByExpert(address entrepreneur, address expert) view returns(address, uint8)
+func (_Offer *OfferCallerSession) GetReviewByExpert(entrepreneur common.Address, expert common.Address) (common.Address, uint8, error) {
+	return _Offer.Contract.GetReviewByExpert(&_Offer.CallOpts, entrepreneur, expert)
+}
+
+// GetReviewersCount is a free data retrieval call binding the contract method 0x153c0bbb.
+//
+// Solidity: function getReviewersCount(address entrepreneur) view returns(uint256)
+func (_Offer *OfferCaller) GetReviewersCount(opts *bind.CallOpts, entrepreneur common.Address) (*big.Int, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "getReviewersCount", entrepreneur)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// GetReviewersCount is a free data retrieval call binding the contract method 0x153c0bbb.
+//
+// Solidity: function getReviewersCount(address entrepreneur) view returns(uint256)
+func (_Offer *OfferSession) GetReviewersCount(entrepreneur common.Address) (*big.Int, error) {
+	return _Offer.Contract.GetReviewersCount(&_Offer.CallOpts, entrepreneur)
+}
+
+// GetReviewersCount is a free data retrieval call binding the contract method 0x153c0bbb.
+//
+// Solidity: function getReviewersCount(address entrepreneur) view returns(uint256)
+func (_Offer *OfferCallerSession) GetReviewersCount(entrepreneur common.Address) (*big.Int, error) {
+	return _Offer.Contract.GetReviewersCount(&_Offer.CallOpts, entrepreneur)
+}
+
+// GetStage is a free data retrieval call binding the contract method 0xfcaa7664.
+//
+// Solidity: function getStage() view returns(uint8)
+func (_Offer *OfferCaller) GetStage(opts *bind.CallOpts) (uint8, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "getStage")
+
+	if err != nil {
+		return *new(uint8), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(uint8)).(*uint8)
+
+	return out0, err
+
+}
+
+// GetStage is a free data retrieval call binding the contract method 0xfcaa7664.
+//
+// Solidity: function getStage() view returns(uint8)
+func (_Offer *OfferSession) GetStage() (uint8, error) {
+	return _Offer.Contract.GetStage(&_Offer.CallOpts)
+}
+
+// GetStage is a free data retrieval call binding the contract method 0xfcaa7664.
+//
+// Solidity: function getStage() view returns(uint8)
+func (_Offer *OfferCallerSession) GetStage() (uint8, error) {
+	return _Offer.Contract.GetStage(&_Offer.CallOpts)
+}
+
+// IsClosed is a free data retrieval call binding the contract method 0xc2b6b58c.
+//
+// Solidity: function isClosed() view returns(bool)
+func (_Offer *OfferCaller) IsClosed(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "isClosed")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// IsClosed is a free data retrieval call binding the contract method 0xc2b6b58c.
+//
+// Solidity: function isClosed() view returns(bool)
+func (_Offer *OfferSession) IsClosed() (bool, error) {
+	return _Offer.Contract.IsClosed(&_Offer.CallOpts)
+}
+
+// IsClosed is a free data retrieval call binding the contract method 0xc2b6b58c.
+//
+// Solidity: function isClosed() view returns(bool)
+func (_Offer *OfferCallerSession) IsClosed() (bool, error) {
+	return _Offer.Contract.IsClosed(&_Offer.CallOpts)
+}
+
+// Proposals is a free data retrieval call binding the contract method 0x3341b445.
+//
+// Solidity: function proposals(address ) view returns(address entrepreneur, string description, uint256 price, uint256 totalScore, uint256 reviewCount, bool exists)
+func (_Offer *OfferCaller) Proposals(opts *bind.CallOpts, arg0 common.Address) (struct {
+	Entrepreneur common.Address
+	Description  string
+	Price        *big.Int
+	TotalScore   *big.Int
+	ReviewCount  *big.Int
+	Exists       bool
+}, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "proposals", arg0)
+
+	outstruct := new(struct {
+		Entrepreneur common.Address
+		Description  string
+		Price        *big.Int
+		TotalScore   *big.Int
+		ReviewCount  *big.Int
+		Exists       bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Entrepreneur = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Description = *abi.ConvertType(out[1], new(string)).(*string)
+	outstruct.Price = *abi.ConvertType(out[2], new(*big.Int)).(**big.Int)
+	outstruct.TotalScore = *abi.ConvertType(out[3], new(*big.Int)).(**big.Int)
+	outstruct.ReviewCount = *abi.ConvertType(out[4], new(*big.Int)).(**big.Int)
+	outstruct.Exists = *abi.ConvertType(out[5], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Proposals is a free data retrieval call binding the contract method 0x3341b445.
+//
+// Solidity: function proposals(address ) view returns(address entrepreneur, string description, uint256 price, uint256 totalScore, uint256 reviewCount, bool exists)
+func (_Offer *OfferSession) Proposals(arg0 common.Address) (struct {
+	Entrepreneur common.Address
+	Description  string
+	Price        *big.Int
+	TotalScore   *big.Int
+	ReviewCount  *big.Int
+	Exists       bool
+}, error) {
+	return _Offer.Contract.Proposals(&_Offer.CallOpts, arg0)
+}
+
+// Proposals is a free data retrieval call binding the contract method 0x3341b445.
+//
+// Solidity: function proposals(address ) view returns(address entrepreneur, string description, uint256 price, uint256 totalScore, uint256 reviewCount, bool exists)
+func (_Offer *OfferCallerSession) Proposals(arg0 common.Address) (struct {
+	Entrepreneur common.Address
+	Description  string
+	Price        *big.Int
+	TotalScore   *big.Int
+	ReviewCount  *big.Int
+	Exists       bool
+}, error) {
+	return _Offer.Contract.Proposals(&_Offer.CallOpts, arg0)
+}
+
+// ReviewEnd is a free data retrieval call binding the contract method 0xf6513a7c.
+//
+// Solidity: function reviewEnd() view returns(uint256)
+func (_Offer *OfferCaller) ReviewEnd(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "reviewEnd")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ReviewEnd is a free data retrieval call binding the contract method 0xf6513a7c.
+//
+// Solidity: function reviewEnd() view returns(uint256)
+func (_Offer *OfferSession) ReviewEnd() (*big.Int, error) {
+	return _Offer.Contract.ReviewEnd(&_Offer.CallOpts)
+}
+
+// ReviewEnd is a free data retrieval call binding the contract method 0xf6513a7c.
+//
+// Solidity: function reviewEnd() view returns(uint256)
+func (_Offer *OfferCallerSession) ReviewEnd() (*big.Int, error) {
+	return _Offer.Contract.ReviewEnd(&_Offer.CallOpts)
+}
+
+// ReviewStart is a free data retrieval call binding the contract method 0x6024a501.
+//
+// Solidity: function reviewStart() view returns(uint256)
+func (_Offer *OfferCaller) ReviewStart(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "reviewStart")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// ReviewStart is a free data retrieval call binding the contract method 0x6024a501.
+//
+// Solidity: function reviewStart() view returns(uint256)
+func (_Offer *OfferSession) ReviewStart() (*big.Int, error) {
+	return _Offer.Contract.ReviewStart(&_Offer.CallOpts)
+}
+
+// ReviewStart is a free data retrieval call binding the contract method 0x6024a501.
+//
+// Solidity: function reviewStart() view returns(uint256)
+func (_Offer *OfferCallerSession) ReviewStart() (*big.Int, error) {
+	return _Offer.Contract.ReviewStart(&_Offer.CallOpts)
+}
+
+// Reviews is a free data retrieval call binding the contract method 0xd07015f2.
+//
+// Solidity: function reviews(address , address ) view returns(address expert, uint8 score, bool exists)
+func (_Offer *OfferCaller) Reviews(opts *bind.CallOpts, arg0 common.Address, arg1 common.Address) (struct {
+	Expert common.Address
+	Score  uint8
+	Exists bool
+}, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "reviews", arg0, arg1)
+
+	outstruct := new(struct {
+		Expert common.Address
+		Score  uint8
+		Exists bool
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.Expert = *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+	outstruct.Score = *abi.ConvertType(out[1], new(uint8)).(*uint8)
+	outstruct.Exists = *abi.ConvertType(out[2], new(bool)).(*bool)
+
+	return *outstruct, err
+
+}
+
+// Reviews is a free data retrieval call binding the contract method 0xd07015f2.
+//
+// Solidity: function reviews(address , address ) view returns(address expert, uint8 score, bool exists)
+func (_Offer *OfferSession) Reviews(arg0 common.Address, arg1 common.Address) (struct {
+	Expert common.Address
+	Score  uint8
+	Exists bool
+}, error) {
+	return _Offer.Contract.Reviews(&_Offer.CallOpts, arg0, arg1)
+}
+
+// Reviews is a free data retrieval call binding the contract method 0xd07015f2.
+//
+// Solidity: function reviews(address , address ) view returns(address expert, uint8 score, bool exists)
+func (_Offer *OfferCallerSession) Reviews(arg0 common.Address, arg1 common.Address) (struct {
+	Expert common.Address
+	Score  uint8
+	Exists bool
+}, error) {
+	return _Offer.Contract.Reviews(&_Offer.CallOpts, arg0, arg1)
+}
+
+// SubmissionEnd is a free data retrieval call binding the contract method 0x9513b7de.
+//
+// Solidity: function submissionEnd() view returns(uint256)
+func (_Offer *OfferCaller) SubmissionEnd(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "submissionEnd")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// SubmissionEnd is a free data retrieval call binding the contract method 0x9513b7de.
+//
+// Solidity: function submissionEnd() view returns(uint256)
+func (_Offer *OfferSession) SubmissionEnd() (*big.Int, error) {
+	return _Offer.Contract.SubmissionEnd(&_Offer.CallOpts)
+}
+
+// SubmissionEnd is a free data retrieval call binding the contract method 0x9513b7de.
+//
+// Solidity: function submissionEnd() view returns(uint256)
+func (_Offer *OfferCallerSession) SubmissionEnd() (*big.Int, error) {
+	return _Offer.Contract.SubmissionEnd(&_Offer.CallOpts)
+}
+
+// SubmissionStart is a free data retrieval call binding the contract method 0x6023e674.
+//
+// Solidity: function submissionStart() view returns(uint256)
+func (_Offer *OfferCaller) SubmissionStart(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "submissionStart")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// SubmissionStart is a free data retrieval call binding the contract method 0x6023e674.
+//
+// Solidity: function submissionStart() view returns(uint256)
+func (_Offer *OfferSession) SubmissionStart() (*big.Int, error) {
+	return _Offer.Contract.SubmissionStart(&_Offer.CallOpts)
+}
+
+// SubmissionStart is a free data retrieval call binding the contract method 0x6023e674.
+//
+// Solidity: function submissionStart() view returns(uint256)
+func (_Offer *OfferCallerSession) SubmissionStart() (*big.Int, error) {
+	return _Offer.Contract.SubmissionStart(&_Offer.CallOpts)
+}
+
+// Tender is a free data retrieval call binding the contract method 0x6cb55d1a.
+//
+// Solidity: function tender() view returns(address)
+func (_Offer *OfferCaller) Tender(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "tender")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Tender is a free data retrieval call binding the contract method 0x6cb55d1a.
+//
+// Solidity: function tender() view returns(address)
+func (_Offer *OfferSession) Tender() (common.Address, error) {
+	return _Offer.Contract.Tender(&_Offer.CallOpts)
+}
+
+// Tender is a free data retrieval call binding the contract method 0x6cb55d1a.
+//
+// Solidity: function tender() view returns(address)
+func (_Offer *OfferCallerSession) Tender() (common.Address, error) {
+	return _Offer.Contract.Tender(&_Offer.CallOpts)
+}
+
+// WinnerDeclared is a free data retrieval call binding the contract method 0xff2d4812.
+//
+// Solidity: function winnerDeclared() view returns(bool)
+func (_Offer *OfferCaller) WinnerDeclared(opts *bind.CallOpts) (bool, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "winnerDeclared")
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// WinnerDeclared is a free data retrieval call binding the contract method 0xff2d4812.
+//
+// Solidity: function winnerDeclared() view returns(bool)
+func (_Offer *OfferSession) WinnerDeclared() (bool, error) {
+	return _Offer.Contract.WinnerDeclared(&_Offer.CallOpts)
+}
+
+// WinnerDeclared is a free data retrieval call binding the contract method 0xff2d4812.
+//
+// Solidity: function winnerDeclared() view returns(bool)
+func (_Offer *OfferCallerSession) WinnerDeclared() (bool, error) {
+	return _Offer.Contract.WinnerDeclared(&_Offer.CallOpts)
+}
+
+// WinningEntrepreneur is a free data retrieval call binding the contract method 0x7712a1fd.
+//
+// Solidity: function winningEntrepreneur() view returns(address)
+func (_Offer *OfferCaller) WinningEntrepreneur(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _Offer.contract.Call(opts, &out, "winningEntrepreneur")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// WinningEntrepreneur is a free data retrieval call binding the contract method 0x7712a1fd.
+//
+// Solidity: function winningEntrepreneur() view returns(address)
+func (_Offer *OfferSession) WinningEntrepreneur() (common.Address, error) {
+	return _Offer.Contract.WinningEntrepreneur(&_Offer.CallOpts)
+}
+
+// WinningEntrepreneur is a free data retrieval call binding the contract method 0x7712a1fd.
+//
+// Solidity: function winningEntrepreneur() view returns(address)
+func (_Offer *OfferCallerSession) WinningEntrepreneur() (common.Address, error) {
+	return _Offer.Contract.WinningEntrepreneur(&_Offer.CallOpts)
+}
+
+// CloseOffer is a paid mutator transaction binding the contract method 0x6219b06d.
+//
+// Solidity: function closeOffer() returns()
+func (_Offer *OfferTransactor) CloseOffer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Offer.contract.Transact(opts, "closeOffer")
+}
+
+// CloseOffer is a paid mutator transaction binding the contract method 0x6219b06d.
+//
+// Solidity: function closeOffer() returns()
+func (_Offer *OfferSession) CloseOffer() (*types.Transaction, error) {
+	return _Offer.Contract.CloseOffer(&_Offer.TransactOpts)
+}
+
+// CloseOffer is a paid mutator transaction binding the contract method 0x6219b06d.
+//
+// Solidity: function closeOffer() returns()
+func (_Offer *OfferTransactorSession) CloseOffer() (*types.Transaction, error) {
+	return _Offer.Contract.CloseOffer(&_Offer.TransactOpts)
+}
+
+// DeclareWinner is a paid mutator transaction binding the contract method 0x14034bd2.
+//
+// Solidity: function declareWinner() returns(address)
+func (_Offer *OfferTransactor) DeclareWinner(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Offer.contract.Transact(opts, "declareWinner")
+}
+
+// DeclareWinner is a paid mutator transaction binding the contract method 0x14034bd2.
+//
+// Solidity: function declareWinner() returns(address)
+func (_Offer *OfferSession) DeclareWinner() (*types.Transaction, error) {
+	return _Offer.Contract.DeclareWinner(&_Offer.TransactOpts)
+}
+
+// DeclareWinner is a paid mutator transaction binding the contract method 0x14034bd2.
+//
+// Solidity: function declareWinner() returns(address)
+func (_Offer *OfferTransactorSession) DeclareWinner() (*types.Transaction, error) {
+	return _Offer.Contract.DeclareWinner(&_Offer.TransactOpts)
+}
+
+// ReviewProposal is a paid mutator transaction binding the contract method 0xa87974f0.
+//
+// Solidity: function reviewProposal(address entrepreneur, uint8 score) returns()
+func (_Offer *OfferTransactor) ReviewProposal(opts *bind.TransactOpts, entrepreneur common.Address, score uint8) (*types.Transaction, error) {
+	return _Offer.contract.Transact(opts, "reviewProposal", entrepreneur, score)
+}
+
+// ReviewProposal is a paid mutator transaction binding the contract method 0xa87974f0.
+//
+// Solidity: function reviewProposal(address entrepreneur, uint8 score) returns()
+func (_Offer *OfferSession) ReviewProposal(entrepreneur common.Address, score uint8) (*types.Transaction, error) {
+	return _Offer.Contract.ReviewProposal(&_Offer.TransactOpts, entrepreneur, score)
+}
+
+// ReviewProposal is a paid mutator transaction binding the contract method 0xa87974f0.
+//
+// Solidity: function reviewProposal(address entrepreneur, uint8 score) returns()
+func (_Offer *OfferTransactorSession) ReviewProposal(entrepreneur common.Address, score uint8) (*types.Transaction, error) {
+	return _Offer.Contract.ReviewProposal(&_Offer.TransactOpts, entrepreneur, score)
+}
+
+// SubmitProposal is a paid mutator transaction binding the contract method 0x6f1684de.
+//
+// Solidity: function submitProposal(string description, uint256 price) returns()
+func (_Offer *OfferTransactor) SubmitProposal(opts *bind.TransactOpts, description string, price *big.Int) (*types.Transaction, error) {
+	return _Offer.contract.Transact(opts, "submitProposal", description, price)
+}
+
+// SubmitProposal is a paid mutator transaction binding the contract method 0x6f1684de.
+//
+// Solidity: function submitProposal(string description, uint256 price) returns()
+func (_Offer *OfferSession) SubmitProposal(description string, price *big.Int) (*types.Transaction, error) {
+	return _Offer.Contract.SubmitProposal(&_Offer.TransactOpts, description, price)
+}
+
+// SubmitProposal is a paid mutator transaction binding the contract method 0x6f1684de.
+//
+// Solidity: function submitProposal(string description, uint256 price) returns()
+func (_Offer *OfferTransactorSession) SubmitProposal(description string, price *big.Int) (*types.Transaction, error) {
+	return _Offer.Contract.SubmitProposal(&_Offer.TransactOpts, description, price)
+}
+
+// OfferOfferClosedEventIterator is returned from FilterOfferClosedEvent and is used to iterate over the raw logs and unpacked data for OfferClosedEvent events raised by the Offer contract.
+type OfferOfferClosedEventIterator struct {
+	Event *OfferOfferClosedEvent // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferOfferClosedEventIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferOfferClosedEvent)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferOfferClosedEvent)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferOfferClosedEventIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferOfferClosedEventIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferOfferClosedEvent represents a OfferClosedEvent event raised by the Offer contract.
+type OfferOfferClosedEvent struct {
+	Raw types.Log // Blockchain specific contextual infos
+}
+
+// FilterOfferClosedEvent is a free log retrieval operation binding the contract event 0xdbf554242fa37cd7ef75dd134a3677c69a1cbfb66449b8c1a288c0b6d5e4990a.
+//
+// Solidity: event OfferClosedEvent()
+func (_Offer *OfferFilterer) FilterOfferClosedEvent(opts *bind.FilterOpts) (*OfferOfferClosedEventIterator, error) {
+
+	logs, sub, err := _Offer.contract.FilterLogs(opts, "OfferClosedEvent")
+	if err != nil {
+		return nil, err
+	}
+	return &OfferOfferClosedEventIterator{contract: _Offer.contract, event: "OfferClosedEvent", logs: logs, sub: sub}, nil
+}
+
+// WatchOfferClosedEvent is a free log subscription operation binding the contract event 0xdbf554242fa37cd7ef75dd134a3677c69a1cbfb66449b8c1a288c0b6d5e4990a.
+//
+// Solidity: event OfferClosedEvent()
+func (_Offer *OfferFilterer) WatchOfferClosedEvent(opts *bind.WatchOpts, sink chan<- *OfferOfferClosedEvent) (event.Subscription, error) {
+
+	logs, sub, err := _Offer.contract.WatchLogs(opts, "OfferClosedEvent")
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferOfferClosedEvent)
+				if err := _Offer.contract.UnpackLog(event, "OfferClosedEvent", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOfferClosedEvent is a log parse operation binding the contract event 0xdbf554242fa37cd7ef75dd134a3677c69a1cbfb66449b8c1a288c0b6d5e4990a.
+//
+// Solidity: event OfferClosedEvent()
+func (_Offer *OfferFilterer) ParseOfferClosedEvent(log types.Log) (*OfferOfferClosedEvent, error) {
+	event := new(OfferOfferClosedEvent)
+	if err := _Offer.contract.UnpackLog(event, "OfferClosedEvent", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// OfferProposalReviewedIterator is returned from FilterProposalReviewed and is used to iterate over the raw logs and unpacked data for ProposalReviewed events raised by the Offer contract.
+type OfferProposalReviewedIterator struct {
+	Event *OfferProposalReviewed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferProposalReviewedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferProposalReviewed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferProposalReviewed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferProposalReviewedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferProposalReviewedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferProposalReviewed represents a ProposalReviewed event raised by the Offer contract.
+type OfferProposalReviewed struct {
+	Entrepreneur common.Address
+	Expert       common.Address
+	Score        uint8
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterProposalReviewed is a free log retrieval operation binding the contract event 0x7be22635d9286fafed67d2f2135c10c3dc88aaf211620184ca51742a00a5d637.
+//
+// Solidity: event ProposalReviewed(address indexed entrepreneur, address indexed expert, uint8 score)
+func (_Offer *OfferFilterer) FilterProposalReviewed(opts *bind.FilterOpts, entrepreneur []common.Address, expert []common.Address) (*OfferProposalReviewedIterator, error) {
+
+	var entrepreneurRule []interface{}
+	for _, entrepreneurItem := range entrepreneur {
+		entrepreneurRule = append(entrepreneurRule, entrepreneurItem)
+	}
+	var expertRule []interface{}
+	for _, expertItem := range expert {
+		expertRule = append(expertRule, expertItem)
+	}
+
+	logs, sub, err := _Offer.contract.FilterLogs(opts, "ProposalReviewed", entrepreneurRule, expertRule)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferProposalReviewedIterator{contract: _Offer.contract, event: "ProposalReviewed", logs: logs, sub: sub}, nil
+}
+
+// WatchProposalReviewed is a free log subscription operation binding the contract event 0x7be22635d9286fafed67d2f2135c10c3dc88aaf211620184ca51742a00a5d637.
+//
+// Solidity: event ProposalReviewed(address indexed entrepreneur, address indexed expert, uint8 score)
+func (_Offer *OfferFilterer) WatchProposalReviewed(opts *bind.WatchOpts, sink chan<- *OfferProposalReviewed, entrepreneur []common.Address, expert []common.Address) (event.Subscription, error) {
+
+	var entrepreneurRule []interface{}
+	for _, entrepreneurItem := range entrepreneur {
+		entrepreneurRule = append(entrepreneurRule, entrepreneurItem)
+	}
+	var expertRule []interface{}
+	for _, expertItem := range expert {
+		expertRule = append(expertRule, expertItem)
+	}
+
+	logs, sub, err := _Offer.contract.WatchLogs(opts, "ProposalReviewed", entrepreneurRule, expertRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferProposalReviewed)
+				if err := _Offer.contract.UnpackLog(event, "ProposalReviewed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseProposalReviewed is a log parse operation binding the contract event 0x7be22635d9286fafed67d2f2135c10c3dc88aaf211620184ca51742a00a5d637.
+//
+// Solidity: event ProposalReviewed(address indexed entrepreneur, address indexed expert, uint8 score)
+func (_Offer *OfferFilterer) ParseProposalReviewed(log types.Log) (*OfferProposalReviewed, error) {
+	event := new(OfferProposalReviewed)
+	if err := _Offer.contract.UnpackLog(event, "ProposalReviewed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// OfferProposalSubmittedIterator is returned from FilterProposalSubmitted and is used to iterate over the raw logs and unpacked data for ProposalSubmitted events raised by the Offer contract.
+type OfferProposalSubmittedIterator struct {
+	Event *OfferProposalSubmitted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferProposalSubmittedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferProposalSubmitted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferProposalSubmitted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferProposalSubmittedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferProposalSubmittedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferProposalSubmitted represents a ProposalSubmitted event raised by the Offer contract.
+type OfferProposalSubmitted struct {
+	Entrepreneur common.Address
+	Description  string
+	Price        *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterProposalSubmitted is a free log retrieval operation binding the contract event 0x9b8fe1cd55a87b8f5eaa48960ac278de3e998ca69ab086083cae48e7e464500e.
+//
+// Solidity: event ProposalSubmitted(address indexed entrepreneur, string description, uint256 price)
+func (_Offer *OfferFilterer) FilterProposalSubmitted(opts *bind.FilterOpts, entrepreneur []common.Address) (*OfferProposalSubmittedIterator, error) {
+
+	var entrepreneurRule []interface{}
+	for _, entrepreneurItem := range entrepreneur {
+		entrepreneurRule = append(entrepreneurRule, entrepreneurItem)
+	}
+
+	logs, sub, err := _Offer.contract.FilterLogs(opts, "ProposalSubmitted", entrepreneurRule)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferProposalSubmittedIterator{contract: _Offer.contract, event: "ProposalSubmitted", logs: logs, sub: sub}, nil
+}
+
+// WatchProposalSubmitted is a free log subscription operation binding the contract event 0x9b8fe1cd55a87b8f5eaa48960ac278de3e998ca69ab086083cae48e7e464500e.
+//
+// Solidity: event ProposalSubmitted(address indexed entrepreneur, string description, uint256 price)
+func (_Offer *OfferFilterer) WatchProposalSubmitted(opts *bind.WatchOpts, sink chan<- *OfferProposalSubmitted, entrepreneur []common.Address) (event.Subscription, error) {
+
+	var entrepreneurRule []interface{}
+	for _, entrepreneurItem := range entrepreneur {
+		entrepreneurRule = append(entrepreneurRule, entrepreneurItem)
+	}
+
+	logs, sub, err := _Offer.contract.WatchLogs(opts, "ProposalSubmitted", entrepreneurRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferProposalSubmitted)
+				if err := _Offer.contract.UnpackLog(event, "ProposalSubmitted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseProposalSubmitted is a log parse operation binding the contract event 0x9b8fe1cd55a87b8f5eaa48960ac278de3e998ca69ab086083cae48e7e464500e.
+//
+// Solidity: event ProposalSubmitted(address indexed entrepreneur, string description, uint256 price)
+func (_Offer *OfferFilterer) ParseProposalSubmitted(log types.Log) (*OfferProposalSubmitted, error) {
+	event := new(OfferProposalSubmitted)
+	if err := _Offer.contract.UnpackLog(event, "ProposalSubmitted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// OfferWinnerDeclaredIterator is returned from FilterWinnerDeclared and is used to iterate over the raw logs and unpacked data for WinnerDeclared events raised by the Offer contract.
+type OfferWinnerDeclaredIterator struct {
+	Event *OfferWinnerDeclared // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferWinnerDeclaredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferWinnerDeclared)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferWinnerDeclared)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferWinnerDeclaredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferWinnerDeclaredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferWinnerDeclared represents a WinnerDeclared event raised by the Offer contract.
+type OfferWinnerDeclared struct {
+	Entrepreneur common.Address
+	TotalScore   *big.Int
+	Price        *big.Int
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterWinnerDeclared is a free log retrieval operation binding the contract event 0xe12cfd13c68d66cef1fd6a911a8c71b2ebf3fcd260eadba4e1580ab7e654b0d7.
+//
+// Solidity: event WinnerDeclared(address indexed entrepreneur, uint256 totalScore, uint256 price)
+func (_Offer *OfferFilterer) FilterWinnerDeclared(opts *bind.FilterOpts, entrepreneur []common.Address) (*OfferWinnerDeclaredIterator, error) {
+
+	var entrepreneurRule []interface{}
+	for _, entrepreneurItem := range entrepreneur {
+		entrepreneurRule = append(entrepreneurRule, entrepreneurItem)
+	}
+
+	logs, sub, err := _Offer.contract.FilterLogs(opts, "WinnerDeclared", entrepreneurRule)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferWinnerDeclaredIterator{contract: _Offer.contract, event: "WinnerDeclared", logs: logs, sub: sub}, nil
+}
+
+// WatchWinnerDeclared is a free log subscription operation binding the contract event 0xe12cfd13c68d66cef1fd6a911a8c71b2ebf3fcd260eadba4e1580ab7e654b0d7.
+//
+// Solidity: event WinnerDeclared(address indexed entrepreneur, uint256 totalScore, uint256 price)
+func (_Offer *OfferFilterer) WatchWinnerDeclared(opts *bind.WatchOpts, sink chan<- *OfferWinnerDeclared, entrepreneur []common.Address) (event.Subscription, error) {
+
+	var entrepreneurRule []interface{}
+	for _, entrepreneurItem := range entrepreneur {
+		entrepreneurRule = append(entrepreneurRule, entrepreneurItem)
+	}
+
+	logs, sub, err := _Offer.contract.WatchLogs(opts, "WinnerDeclared", entrepreneurRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferWinnerDeclared)
+				if err := _Offer.contract.UnpackLog(event, "WinnerDeclared", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseWinnerDeclared is a log parse operation binding the contract event 0xe12cfd13c68d66cef1fd6a911a8c71b2ebf3fcd260eadba4e1580ab7e654b0d7.
+//
+// Solidity: event WinnerDeclared(address indexed entrepreneur, uint256 totalScore, uint256 price)
+func (_Offer *OfferFilterer) ParseWinnerDeclared(log types.Log) (*OfferWinnerDeclared, error) {
+	event := new(OfferWinnerDeclared)
+	if err := _Offer.contract.UnpackLog(event, "WinnerDeclared", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
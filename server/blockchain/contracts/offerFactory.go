@@ -0,0 +1,1174 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// OfferFactoryMetaData contains all meta data concerning the OfferFactory contract.
+var OfferFactoryMetaData = &bind.MetaData{
+	ABI: offerFactoryABI,
+	Bin: offerFactoryBin,
+}
+
+// OfferFactoryABI is the input ABI used to generate the binding from.
+// Deprecated: Use OfferFactoryMetaData.ABI instead.
+var OfferFactoryABI = OfferFactoryMetaData.ABI
+
+// OfferFactoryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use OfferFactoryMetaData.Bin instead.
+var OfferFactoryBin = OfferFactoryMetaData.Bin
+
+// DeployOfferFactory deploys a new Ethereum contract, binding an instance of OfferFactory to it.
+func DeployOfferFactory(auth *bind.TransactOpts, backend bind.ContractBackend, initialAdmin common.Address) (common.Address, *types.Transaction, *OfferFactory, error) {
+	parsed, err := OfferFactoryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(OfferFactoryBin), backend, initialAdmin)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &OfferFactory{OfferFactoryCaller: OfferFactoryCaller{contract: contract}, OfferFactoryTransactor: OfferFactoryTransactor{contract: contract}, OfferFactoryFilterer: OfferFactoryFilterer{contract: contract}}, nil
+}
+
+// OfferFactory is an auto generated Go binding around an Ethereum contract.
+type OfferFactory struct {
+	OfferFactoryCaller     // Read-only binding to the contract
+	OfferFactoryTransactor // Write-only binding to the contract
+	OfferFactoryFilterer   // Log filterer for contract events
+}
+
+// OfferFactoryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type OfferFactoryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OfferFactoryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type OfferFactoryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OfferFactoryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type OfferFactoryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// OfferFactorySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type OfferFactorySession struct {
+	Contract     *OfferFactory     // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// OfferFactoryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type OfferFactoryCallerSession struct {
+	Contract *OfferFactoryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts       // Call options to use throughout this session
+}
+
+// OfferFactoryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type OfferFactoryTransactorSession struct {
+	Contract     *OfferFactoryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts       // Transaction auth options to use throughout this session
+}
+
+// OfferFactoryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type OfferFactoryRaw struct {
+	Contract *OfferFactory // Generic contract binding to access the raw methods on
+}
+
+// OfferFactoryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type OfferFactoryCallerRaw struct {
+	Contract *OfferFactoryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// OfferFactoryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type OfferFactoryTransactorRaw struct {
+	Contract *OfferFactoryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewOfferFactory creates a new instance of OfferFactory, bound to a specific deployed contract.
+func NewOfferFactory(address common.Address, backend bind.ContractBackend) (*OfferFactory, error) {
+	contract, err := bindOfferFactory(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactory{OfferFactoryCaller: OfferFactoryCaller{contract: contract}, OfferFactoryTransactor: OfferFactoryTransactor{contract: contract}, OfferFactoryFilterer: OfferFactoryFilterer{contract: contract}}, nil
+}
+
+// NewOfferFactoryCaller creates a new read-only instance of OfferFactory, bound to a specific deployed contract.
+func NewOfferFactoryCaller(address common.Address, caller bind.ContractCaller) (*OfferFactoryCaller, error) {
+	contract, err := bindOfferFactory(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactoryCaller{contract: contract}, nil
+}
+
+// NewOfferFactoryTransactor creates a new write-only instance of OfferFactory, bound to a specific deployed contract.
+func NewOfferFactoryTransactor(address common.Address, transactor bind.ContractTransactor) (*OfferFactoryTransactor, error) {
+	contract, err := bindOfferFactory(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactoryTransactor{contract: contract}, nil
+}
+
+// NewOfferFactoryFilterer creates a new log filterer instance of OfferFactory, bound to a specific deployed contract.
+func NewOfferFactoryFilterer(address common.Address, filterer bind.ContractFilterer) (*OfferFactoryFilterer, error) {
+	contract, err := bindOfferFactory(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactoryFilterer{contract: contract}, nil
+}
+
+// bindOfferFactory binds a generic wrapper to an already deployed contract.
+func bindOfferFactory(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := OfferFactoryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_OfferFactory *OfferFactoryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _OfferFactory.Contract.OfferFactoryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_OfferFactory *OfferFactoryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _OfferFactory.Contract.OfferFactoryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_OfferFactory *OfferFactoryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _OfferFactory.Contract.OfferFactoryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_OfferFactory *OfferFactoryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _OfferFactory.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_OfferFactory *OfferFactoryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _OfferFactory.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_OfferFactory *OfferFactoryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _OfferFactory.Contract.contract.Transact(opts, method, params...)
+}
+
+// DEFAULTADMINROLE is a free data retrieval call binding the contract method 0xa217fddf.
+//
+// Solidity: function DEFAULT_ADMIN_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCaller) DEFAULTADMINROLE(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "DEFAULT_ADMIN_ROLE")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// DEFAULTADMINROLE is a free data retrieval call binding the contract method 0xa217fddf.
+//
+// Solidity: function DEFAULT_ADMIN_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactorySession) DEFAULTADMINROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.DEFAULTADMINROLE(&_OfferFactory.CallOpts)
+}
+
+// DEFAULTADMINROLE is a free data retrieval call binding the contract method 0xa217fddf.
+//
+// Solidity: function DEFAULT_ADMIN_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCallerSession) DEFAULTADMINROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.DEFAULTADMINROLE(&_OfferFactory.CallOpts)
+}
+
+// ENTREPRENEURROLE is a free data retrieval call binding the contract method 0x681eab8a.
+//
+// Solidity: function ENTREPRENEUR_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCaller) ENTREPRENEURROLE(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "ENTREPRENEUR_ROLE")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// ENTREPRENEURROLE is a free data retrieval call binding the contract method 0x681eab8a.
+//
+// Solidity: function ENTREPRENEUR_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactorySession) ENTREPRENEURROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.ENTREPRENEURROLE(&_OfferFactory.CallOpts)
+}
+
+// ENTREPRENEURROLE is a free data retrieval call binding the contract method 0x681eab8a.
+//
+// Solidity: function ENTREPRENEUR_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCallerSession) ENTREPRENEURROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.ENTREPRENEURROLE(&_OfferFactory.CallOpts)
+}
+
+// EXPERTROLE is a free data retrieval call binding the contract method 0xee23c3ee.
+//
+// Solidity: function EXPERT_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCaller) EXPERTROLE(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "EXPERT_ROLE")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// EXPERTROLE is a free data retrieval call binding the contract method 0xee23c3ee.
+//
+// Solidity: function EXPERT_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactorySession) EXPERTROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.EXPERTROLE(&_OfferFactory.CallOpts)
+}
+
+// EXPERTROLE is a free data retrieval call binding the contract method 0xee23c3ee.
+//
+// Solidity: function EXPERT_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCallerSession) EXPERTROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.EXPERTROLE(&_OfferFactory.CallOpts)
+}
+
+// TENDERROLE is a free data retrieval call binding the contract method 0x904288cb.
+//
+// Solidity: function TENDER_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCaller) TENDERROLE(opts *bind.CallOpts) ([32]byte, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "TENDER_ROLE")
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// TENDERROLE is a free data retrieval call binding the contract method 0x904288cb.
+//
+// Solidity: function TENDER_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactorySession) TENDERROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.TENDERROLE(&_OfferFactory.CallOpts)
+}
+
+// TENDERROLE is a free data retrieval call binding the contract method 0x904288cb.
+//
+// Solidity: function TENDER_ROLE() view returns(bytes32)
+func (_OfferFactory *OfferFactoryCallerSession) TENDERROLE() ([32]byte, error) {
+	return _OfferFactory.Contract.TENDERROLE(&_OfferFactory.CallOpts)
+}
+
+// AllOffers is a free data retrieval call binding the contract method 0x06272a0b.
+//
+// Solidity: function allOffers(uint256 ) view returns(address)
+func (_OfferFactory *OfferFactoryCaller) AllOffers(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "allOffers", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// AllOffers is a free data retrieval call binding the contract method 0x06272a0b.
+//
+// Solidity: function allOffers(uint256 ) view returns(address)
+func (_OfferFactory *OfferFactorySession) AllOffers(arg0 *big.Int) (common.Address, error) {
+	return _OfferFactory.Contract.AllOffers(&_OfferFactory.CallOpts, arg0)
+}
+
+// AllOffers is a free data retrieval call binding the contract method 0x06272a0b.
+//
+// Solidity: function allOffers(uint256 ) view returns(address)
+func (_OfferFactory *OfferFactoryCallerSession) AllOffers(arg0 *big.Int) (common.Address, error) {
+	return _OfferFactory.Contract.AllOffers(&_OfferFactory.CallOpts, arg0)
+}
+
+// GetRoleAdmin is a free data retrieval call binding the contract method 0x248a9ca3.
+//
+// Solidity: function getRoleAdmin(bytes32 role) view returns(bytes32)
+func (_OfferFactory *OfferFactoryCaller) GetRoleAdmin(opts *bind.CallOpts, role [32]byte) ([32]byte, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "getRoleAdmin", role)
+
+	if err != nil {
+		return *new([32]byte), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+
+	return out0, err
+
+}
+
+// GetRoleAdmin is a free data retrieval call binding the contract method 0x248a9ca3.
+//
+// Solidity: function getRoleAdmin(bytes32 role) view returns(bytes32)
+func (_OfferFactory *OfferFactorySession) GetRoleAdmin(role [32]byte) ([32]byte, error) {
+	return _OfferFactory.Contract.GetRoleAdmin(&_OfferFactory.CallOpts, role)
+}
+
+// GetRoleAdmin is a free data retrieval call binding the contract method 0x248a9ca3.
+//
+// Solidity: function getRoleAdmin(bytes32 role) view returns(bytes32)
+func (_OfferFactory *OfferFactoryCallerSession) GetRoleAdmin(role [32]byte) ([32]byte, error) {
+	return _OfferFactory.Contract.GetRoleAdmin(&_OfferFactory.CallOpts, role)
+}
+
+// HasRole is a free data retrieval call binding the contract method 0x91d14854.
+//
+// Solidity: function hasRole(bytes32 role, address account) view returns(bool)
+func (_OfferFactory *OfferFactoryCaller) HasRole(opts *bind.CallOpts, role [32]byte, account common.Address) (bool, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "hasRole", role, account)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// HasRole is a free data retrieval call binding the contract method 0x91d14854.
+//
+// Solidity: function hasRole(bytes32 role, address account) view returns(bool)
+func (_OfferFactory *OfferFactorySession) HasRole(role [32]byte, account common.Address) (bool, error) {
+	return _OfferFactory.Contract.HasRole(&_OfferFactory.CallOpts, role, account)
+}
+
+// HasRole is a free data retrieval call binding the contract method 0x91d14854.
+//
+// Solidity: function hasRole(bytes32 role, address account) view returns(bool)
+func (_OfferFactory *OfferFactoryCallerSession) HasRole(role [32]byte, account common.Address) (bool, error) {
+	return _OfferFactory.Contract.HasRole(&_OfferFactory.CallOpts, role, account)
+}
+
+// SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
+//
+// Solidity: function supportsInterface(bytes4 interfaceId) view returns(bool)
+func (_OfferFactory *OfferFactoryCaller) SupportsInterface(opts *bind.CallOpts, interfaceId [4]byte) (bool, error) {
+	var out []interface{}
+	err := _OfferFactory.contract.Call(opts, &out, "supportsInterface", interfaceId)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
+//
+// Solidity: function supportsInterface(bytes4 interfaceId) view returns(bool)
+func (_OfferFactory *OfferFactorySession) SupportsInterface(interfaceId [4]byte) (bool, error) {
+	return _OfferFactory.Contract.SupportsInterface(&_OfferFactory.CallOpts, interfaceId)
+}
+
+// SupportsInterface is a free data retrieval call binding the contract method 0x01ffc9a7.
+//
+// Solidity: function supportsInterface(bytes4 interfaceId) view returns(bool)
+func (_OfferFactory *OfferFactoryCallerSession) SupportsInterface(interfaceId [4]byte) (bool, error) {
+	return _OfferFactory.Contract.SupportsInterface(&_OfferFactory.CallOpts, interfaceId)
+}
+
+// CreateOffer is a paid mutator transaction binding the contract method 0x3a97f852.
+//
+// Solidity: function createOffer(uint256 submissionStart, uint256 submissionEnd, uint256 reviewStart, uint256 reviewEnd) returns()
+func (_OfferFactory *OfferFactoryTransactor) CreateOffer(opts *bind.TransactOpts, submissionStart *big.Int, submissionEnd *big.Int, reviewStart *big.Int, reviewEnd *big.Int) (*types.Transaction, error) {
+	return _OfferFactory.contract.Transact(opts, "createOffer", submissionStart, submissionEnd, reviewStart, reviewEnd)
+}
+
+// CreateOffer is a paid mutator transaction binding the contract method 0x3a97f852.
+//
+// Solidity: function createOffer(uint256 submissionStart, uint256 submissionEnd, uint256 reviewStart, uint256 reviewEnd) returns()
+func (_OfferFactory *OfferFactorySession) CreateOffer(submissionStart *big.Int, submissionEnd *big.Int, reviewStart *big.Int, reviewEnd *big.Int) (*types.Transaction, error) {
+	return _OfferFactory.Contract.CreateOffer(&_OfferFactory.TransactOpts, submissionStart, submissionEnd, reviewStart, reviewEnd)
+}
+
+// CreateOffer is a paid mutator transaction binding the contract method 0x3a97f852.
+//
+// Solidity: function createOffer(uint256 submissionStart, uint256 submissionEnd, uint256 reviewStart, uint256 reviewEnd) returns()
+func (_OfferFactory *OfferFactoryTransactorSession) CreateOffer(submissionStart *big.Int, submissionEnd *big.Int, reviewStart *big.Int, reviewEnd *big.Int) (*types.Transaction, error) {
+	return _OfferFactory.Contract.CreateOffer(&_OfferFactory.TransactOpts, submissionStart, submissionEnd, reviewStart, reviewEnd)
+}
+
+// GrantRole is a paid mutator transaction binding the contract method 0x2f2ff15d.
+//
+// Solidity: function grantRole(bytes32 role, address account) returns()
+func (_OfferFactory *OfferFactoryTransactor) GrantRole(opts *bind.TransactOpts, role [32]byte, account common.Address) (*types.Transaction, error) {
+	return _OfferFactory.contract.Transact(opts, "grantRole", role, account)
+}
+
+// GrantRole is a paid mutator transaction binding the contract method 0x2f2ff15d.
+//
+// Solidity: function grantRole(bytes32 role, address account) returns()
+func (_OfferFactory *OfferFactorySession) GrantRole(role [32]byte, account common.Address) (*types.Transaction, error) {
+	return _OfferFactory.Contract.GrantRole(&_OfferFactory.TransactOpts, role, account)
+}
+
+// GrantRole is a paid mutator transaction binding the contract method 0x2f2ff15d.
+//
+// Solidity: function grantRole(bytes32 role, address account) returns()
+func (_OfferFactory *OfferFactoryTransactorSession) GrantRole(role [32]byte, account common.Address) (*types.Transaction, error) {
+	return _OfferFactory.Contract.GrantRole(&_OfferFactory.TransactOpts, role, account)
+}
+
+// RenounceRole is a paid mutator transaction binding the contract method 0x36568abe.
+//
+// Solidity: function renounceRole(bytes32 role, address callerConfirmation) returns()
+func (_OfferFactory *OfferFactoryTransactor) RenounceRole(opts *bind.TransactOpts, role [32]byte, callerConfirmation common.Address) (*types.Transaction, error) {
+	return _OfferFactory.contract.Transact(opts, "renounceRole", role, callerConfirmation)
+}
+
+// RenounceRole is a paid mutator transaction binding the contract method 0x36568abe.
+//
+// Solidity: function renounceRole(bytes32 role, address callerConfirmation) returns()
+func (_OfferFactory *OfferFactorySession) RenounceRole(role [32]byte, callerConfirmation common.Address) (*types.Transaction, error) {
+	return _OfferFactory.Contract.RenounceRole(&_OfferFactory.TransactOpts, role, callerConfirmation)
+}
+
+// RenounceRole is a paid mutator transaction binding the contract method 0x36568abe.
+//
+// Solidity: function renounceRole(bytes32 role, address callerConfirmation) returns()
+func (_OfferFactory *OfferFactoryTransactorSession) RenounceRole(role [32]byte, callerConfirmation common.Address) (*types.Transaction, error) {
+	return _OfferFactory.Contract.RenounceRole(&_OfferFactory.TransactOpts, role, callerConfirmation)
+}
+
+// RevokeRole is a paid mutator transaction binding the contract method 0xd547741f.
+//
+// Solidity: function revokeRole(bytes32 role, address account) returns()
+func (_OfferFactory *OfferFactoryTransactor) RevokeRole(opts *bind.TransactOpts, role [32]byte, account common.Address) (*types.Transaction, error) {
+	return _OfferFactory.contract.Transact(opts, "revokeRole", role, account)
+}
+
+// RevokeRole is a paid mutator transaction binding the contract method 0xd547741f.
+//
+// Solidity: function revokeRole(bytes32 role, address account) returns()
+func (_OfferFactory *OfferFactorySession) RevokeRole(role [32]byte, account common.Address) (*types.Transaction, error) {
+	return _OfferFactory.Contract.RevokeRole(&_OfferFactory.TransactOpts, role, account)
+}
+
+// RevokeRole is a paid mutator transaction binding the contract method 0xd547741f.
+//
+// Solidity: function revokeRole(bytes32 role, address account) returns()
+func (_OfferFactory *OfferFactoryTransactorSession) RevokeRole(role [32]byte, account common.Address) (*types.Transaction, error) {
+	return _OfferFactory.Contract.RevokeRole(&_OfferFactory.TransactOpts, role, account)
+}
+
+// OfferFactoryOfferCreatedIterator is returned from FilterOfferCreated and is used to iterate over the raw logs and unpacked data for OfferCreated events raised by the OfferFactory contract.
+type OfferFactoryOfferCreatedIterator struct {
+	Event *OfferFactoryOfferCreated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferFactoryOfferCreatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferFactoryOfferCreated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferFactoryOfferCreated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferFactoryOfferCreatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferFactoryOfferCreatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferFactoryOfferCreated represents a OfferCreated event raised by the OfferFactory contract.
+type OfferFactoryOfferCreated struct {
+	OfferAddress common.Address
+	Tender       common.Address
+	Raw          types.Log // Blockchain specific contextual infos
+}
+
+// FilterOfferCreated is a free log retrieval operation binding the contract event 0x3200a1a41b9bafe7f1ef24604e6ed0243414cb19d09eb6b16bd15a553df125b8.
+//
+// Solidity: event OfferCreated(address indexed offerAddress, address indexed tender)
+func (_OfferFactory *OfferFactoryFilterer) FilterOfferCreated(opts *bind.FilterOpts, offerAddress []common.Address, tender []common.Address) (*OfferFactoryOfferCreatedIterator, error) {
+
+	var offerAddressRule []interface{}
+	for _, offerAddressItem := range offerAddress {
+		offerAddressRule = append(offerAddressRule, offerAddressItem)
+	}
+	var tenderRule []interface{}
+	for _, tenderItem := range tender {
+		tenderRule = append(tenderRule, tenderItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.FilterLogs(opts, "OfferCreated", offerAddressRule, tenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactoryOfferCreatedIterator{contract: _OfferFactory.contract, event: "OfferCreated", logs: logs, sub: sub}, nil
+}
+
+// WatchOfferCreated is a free log subscription operation binding the contract event 0x3200a1a41b9bafe7f1ef24604e6ed0243414cb19d09eb6b16bd15a553df125b8.
+//
+// Solidity: event OfferCreated(address indexed offerAddress, address indexed tender)
+func (_OfferFactory *OfferFactoryFilterer) WatchOfferCreated(opts *bind.WatchOpts, sink chan<- *OfferFactoryOfferCreated, offerAddress []common.Address, tender []common.Address) (event.Subscription, error) {
+
+	var offerAddressRule []interface{}
+	for _, offerAddressItem := range offerAddress {
+		offerAddressRule = append(offerAddressRule, offerAddressItem)
+	}
+	var tenderRule []interface{}
+	for _, tenderItem := range tender {
+		tenderRule = append(tenderRule, tenderItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.WatchLogs(opts, "OfferCreated", offerAddressRule, tenderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferFactoryOfferCreated)
+				if err := _OfferFactory.contract.UnpackLog(event, "OfferCreated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOfferCreated is a log parse operation binding the contract event 0x3200a1a41b9bafe7f1ef24604e6ed0243414cb19d09eb6b16bd15a553df125b8.
+//
+// Solidity: event OfferCreated(address indexed offerAddress, address indexed tender)
+func (_OfferFactory *OfferFactoryFilterer) ParseOfferCreated(log types.Log) (*OfferFactoryOfferCreated, error) {
+	event := new(OfferFactoryOfferCreated)
+	if err := _OfferFactory.contract.UnpackLog(event, "OfferCreated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// OfferFactoryRoleAdminChangedIterator is returned from FilterRoleAdminChanged and is used to iterate over the raw logs and unpacked data for RoleAdminChanged events raised by the OfferFactory contract.
+type OfferFactoryRoleAdminChangedIterator struct {
+	Event *OfferFactoryRoleAdminChanged // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferFactoryRoleAdminChangedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferFactoryRoleAdminChanged)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferFactoryRoleAdminChanged)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferFactoryRoleAdminChangedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferFactoryRoleAdminChangedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferFactoryRoleAdminChanged represents a RoleAdminChanged event raised by the OfferFactory contract.
+type OfferFactoryRoleAdminChanged struct {
+	Role              [32]byte
+	PreviousAdminRole [32]byte
+	NewAdminRole      [32]byte
+	Raw               types.Log // Blockchain specific contextual infos
+}
+
+// FilterRoleAdminChanged is a free log retrieval operation binding the contract event 0xbd79b86ffe0ab8e8776151514217cd7cacd52c909f66475c3af44e129f0b00ff.
+//
+// Solidity: event RoleAdminChanged(bytes32 indexed role, bytes32 indexed previousAdminRole, bytes32 indexed newAdminRole)
+func (_OfferFactory *OfferFactoryFilterer) FilterRoleAdminChanged(opts *bind.FilterOpts, role [][32]byte, previousAdminRole [][32]byte, newAdminRole [][32]byte) (*OfferFactoryRoleAdminChangedIterator, error) {
+
+	var roleRule []interface{}
+	for _, roleItem := range role {
+		roleRule = append(roleRule, roleItem)
+	}
+	var previousAdminRoleRule []interface{}
+	for _, previousAdminRoleItem := range previousAdminRole {
+		previousAdminRoleRule = append(previousAdminRoleRule, previousAdminRoleItem)
+	}
+	var newAdminRoleRule []interface{}
+	for _, newAdminRoleItem := range newAdminRole {
+		newAdminRoleRule = append(newAdminRoleRule, newAdminRoleItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.FilterLogs(opts, "RoleAdminChanged", roleRule, previousAdminRoleRule, newAdminRoleRule)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactoryRoleAdminChangedIterator{contract: _OfferFactory.contract, event: "RoleAdminChanged", logs: logs, sub: sub}, nil
+}
+
+// WatchRoleAdminChanged is a free log subscription operation binding the contract event 0xbd79b86ffe0ab8e8776151514217cd7cacd52c909f66475c3af44e129f0b00ff.
+//
+// Solidity: event RoleAdminChanged(bytes32 indexed role, bytes32 indexed previousAdminRole, bytes32 indexed newAdminRole)
+func (_OfferFactory *OfferFactoryFilterer) WatchRoleAdminChanged(opts *bind.WatchOpts, sink chan<- *OfferFactoryRoleAdminChanged, role [][32]byte, previousAdminRole [][32]byte, newAdminRole [][32]byte) (event.Subscription, error) {
+
+	var roleRule []interface{}
+	for _, roleItem := range role {
+		roleRule = append(roleRule, roleItem)
+	}
+	var previousAdminRoleRule []interface{}
+	for _, previousAdminRoleItem := range previousAdminRole {
+		previousAdminRoleRule = append(previousAdminRoleRule, previousAdminRoleItem)
+	}
+	var newAdminRoleRule []interface{}
+	for _, newAdminRoleItem := range newAdminRole {
+		newAdminRoleRule = append(newAdminRoleRule, newAdminRoleItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.WatchLogs(opts, "RoleAdminChanged", roleRule, previousAdminRoleRule, newAdminRoleRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferFactoryRoleAdminChanged)
+				if err := _OfferFactory.contract.UnpackLog(event, "RoleAdminChanged", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRoleAdminChanged is a log parse operation binding the contract event 0xbd79b86ffe0ab8e8776151514217cd7cacd52c909f66475c3af44e129f0b00ff.
+//
+// Solidity: event RoleAdminChanged(bytes32 indexed role, bytes32 indexed previousAdminRole, bytes32 indexed newAdminRole)
+func (_OfferFactory *OfferFactoryFilterer) ParseRoleAdminChanged(log types.Log) (*OfferFactoryRoleAdminChanged, error) {
+	event := new(OfferFactoryRoleAdminChanged)
+	if err := _OfferFactory.contract.UnpackLog(event, "RoleAdminChanged", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// OfferFactoryRoleGrantedIterator is returned from FilterRoleGranted and is used to iterate over the raw logs and unpacked data for RoleGranted events raised by the OfferFactory contract.
+type OfferFactoryRoleGrantedIterator struct {
+	Event *OfferFactoryRoleGranted // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferFactoryRoleGrantedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferFactoryRoleGranted)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferFactoryRoleGranted)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferFactoryRoleGrantedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferFactoryRoleGrantedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferFactoryRoleGranted represents a RoleGranted event raised by the OfferFactory contract.
+type OfferFactoryRoleGranted struct {
+	Role    [32]byte
+	Account common.Address
+	Sender  common.Address
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterRoleGranted is a free log retrieval operation binding the contract event 0x2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d.
+//
+// Solidity: event RoleGranted(bytes32 indexed role, address indexed account, address indexed sender)
+func (_OfferFactory *OfferFactoryFilterer) FilterRoleGranted(opts *bind.FilterOpts, role [][32]byte, account []common.Address, sender []common.Address) (*OfferFactoryRoleGrantedIterator, error) {
+
+	var roleRule []interface{}
+	for _, roleItem := range role {
+		roleRule = append(roleRule, roleItem)
+	}
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.FilterLogs(opts, "RoleGranted", roleRule, accountRule, senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactoryRoleGrantedIterator{contract: _OfferFactory.contract, event: "RoleGranted", logs: logs, sub: sub}, nil
+}
+
+// WatchRoleGranted is a free log subscription operation binding the contract event 0x2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d.
+//
+// Solidity: event RoleGranted(bytes32 indexed role, address indexed account, address indexed sender)
+func (_OfferFactory *OfferFactoryFilterer) WatchRoleGranted(opts *bind.WatchOpts, sink chan<- *OfferFactoryRoleGranted, role [][32]byte, account []common.Address, sender []common.Address) (event.Subscription, error) {
+
+	var roleRule []interface{}
+	for _, roleItem := range role {
+		roleRule = append(roleRule, roleItem)
+	}
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.WatchLogs(opts, "RoleGranted", roleRule, accountRule, senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferFactoryRoleGranted)
+				if err := _OfferFactory.contract.UnpackLog(event, "RoleGranted", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRoleGranted is a log parse operation binding the contract event 0x2f8788117e7eff1d82e926ec794901d17c78024a50270940304540a733656f0d.
+//
+// Solidity: event RoleGranted(bytes32 indexed role, address indexed account, address indexed sender)
+func (_OfferFactory *OfferFactoryFilterer) ParseRoleGranted(log types.Log) (*OfferFactoryRoleGranted, error) {
+	event := new(OfferFactoryRoleGranted)
+	if err := _OfferFactory.contract.UnpackLog(event, "RoleGranted", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// OfferFactoryRoleRevokedIterator is returned from FilterRoleRevoked and is used to iterate over the raw logs and unpacked data for RoleRevoked events raised by the OfferFactory contract.
+type OfferFactoryRoleRevokedIterator struct {
+	Event *OfferFactoryRoleRevoked // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *OfferFactoryRoleRevokedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(OfferFactoryRoleRevoked)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(OfferFactoryRoleRevoked)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *OfferFactoryRoleRevokedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *OfferFactoryRoleRevokedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// OfferFactoryRoleRevoked represents a RoleRevoked event raised by the OfferFactory contract.
+type OfferFactoryRoleRevoked struct {
+	Role    [32]byte
+	Account common.Address
+	Sender  common.Address
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterRoleRevoked is a free log retrieval operation binding the contract event 0xf6391f5c32d9c69d2a47ea670b442974b53935d1edc7fd64eb21e047a839171b.
+//
+// Solidity: event RoleRevoked(bytes32 indexed role, address indexed account, address indexed sender)
+func (_OfferFactory *OfferFactoryFilterer) FilterRoleRevoked(opts *bind.FilterOpts, role [][32]byte, account []common.Address, sender []common.Address) (*OfferFactoryRoleRevokedIterator, error) {
+
+	var roleRule []interface{}
+	for _, roleItem := range role {
+		roleRule = append(roleRule, roleItem)
+	}
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.FilterLogs(opts, "RoleRevoked", roleRule, accountRule, senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return &OfferFactoryRoleRevokedIterator{contract: _OfferFactory.contract, event: "RoleRevoked", logs: logs, sub: sub}, nil
+}
+
+// WatchRoleRevoked is a free log subscription operation binding the contract event 0xf6391f5c32d9c69d2a47ea670b442974b53935d1edc7fd64eb21e047a839171b.
+//
+// Solidity: event RoleRevoked(bytes32 indexed role, address indexed account, address indexed sender)
+func (_OfferFactory *OfferFactoryFilterer) WatchRoleRevoked(opts *bind.WatchOpts, sink chan<- *OfferFactoryRoleRevoked, role [][32]byte, account []common.Address, sender []common.Address) (event.Subscription, error) {
+
+	var roleRule []interface{}
+	for _, roleItem := range role {
+		roleRule = append(roleRule, roleItem)
+	}
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+	var senderRule []interface{}
+	for _, senderItem := range sender {
+		senderRule = append(senderRule, senderItem)
+	}
+
+	logs, sub, err := _OfferFactory.contract.WatchLogs(opts, "RoleRevoked", roleRule, accountRule, senderRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(OfferFactoryRoleRevoked)
+				if err := _OfferFactory.contract.UnpackLog(event, "RoleRevoked", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRoleRevoked is a log parse operation binding the contract event 0xf6391f5c32d9c69d2a47ea670b442974b53935d1edc7fd64eb21e047a839171b.
+//
+// Solidity: event RoleRevoked(bytes32 indexed role, address indexed account, address indexed sender)
+func (_OfferFactory *OfferFactoryFilterer) ParseRoleRevoked(log types.Log) (*OfferFactoryRoleRevoked, error) {
+	event := new(OfferFactoryRoleRevoked)
+	if err := _OfferFactory.contract.UnpackLog(event, "RoleRevoked", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -1,86 +1,40 @@
 package blockchain
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
+	"math/big"
 	"strings"
 	"time"
 
-	"github.com/Brondont/trust-api/config"
-	"github.com/Brondont/trust-api/db"
+	"github.com/Brondont/trust-api/blockchain/contracts"
 	"github.com/Brondont/trust-api/models"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-// Global ABI and client cache
-var (
-	factoryABI abi.ABI
-	ethClient  *ethclient.Client
-)
-
-// getClient returns a shared eth client
-func getClient() (*ethclient.Client, error) {
-	if ethClient != nil {
-		return ethClient, nil
-	}
-
-	var err error
-	ethClient, err = ethclient.Dial(config.Envs.BlockchainRPCURL)
-	if err != nil {
-		return nil, fmt.Errorf("connecting to blockchain: %w", err)
-	}
-	return ethClient, nil
-}
-
-// getABI loads the ABI from file or returns the cached version
+// getABI returns the parsed OfferFactory ABI
 func getABI() (abi.ABI, error) {
-	// If ABI is already loaded, return it
-	if factoryABI.Methods != nil {
-		return factoryABI, nil
-	}
-
-	// Otherwise load it from file
-	path := filepath.Join("blockchain", "OfferFactory.json")
-	raw, err := os.ReadFile(path)
+	parsed, err := contracts.OfferFactoryMetaData.GetAbi()
 	if err != nil {
-		return abi.ABI{}, fmt.Errorf("reading ABI file: %w", err)
+		return abi.ABI{}, fmt.Errorf("parsing OfferFactory ABI: %w", err)
 	}
-
-	// Parse the ABI
-	factoryABI, err = abi.JSON(bytes.NewReader(raw))
-	if err != nil {
-		return abi.ABI{}, fmt.Errorf("parsing ABI: %w", err)
-	}
-
-	return factoryABI, nil
+	return *parsed, nil
 }
 
-// UserHasRole checks if a user has a specific role on the blockchain
-func UserHasRole(userAddr string, roleName string) (bool, error) {
-	// Connect to Ethereum node
-	client, err := getClient()
-	if err != nil {
-		return false, err
-	}
-
-	// Get the ABI
-	parsedABI, err := getABI()
+// getOfferABI returns the parsed Offer ABI
+func getOfferABI() (abi.ABI, error) {
+	parsed, err := contracts.OfferMetaData.GetAbi()
 	if err != nil {
-		return false, err
+		return abi.ABI{}, fmt.Errorf("parsing Offer ABI: %w", err)
 	}
+	return *parsed, nil
+}
 
-	// Convert addresses
-	contractAddr := common.HexToAddress(config.Envs.OfferFactoryAddress)
-	userAddress := common.HexToAddress(userAddr)
-
+// RoleHash returns the AccessControl role identifier for a DB role name
+func RoleHash(roleName string) common.Hash {
 	var roleIdentifier string
 	switch strings.ToLower(roleName) {
 	case "admin":
@@ -95,77 +49,85 @@ func UserHasRole(userAddr string, roleName string) (bool, error) {
 		roleIdentifier = strings.ToUpper(roleName) + "_ROLE"
 	}
 
-	var roleHash common.Hash
 	if roleIdentifier == "DEFAULT_ADMIN_ROLE" {
-		roleHash = common.Hash{} // DEFAULT_ADMIN_ROLE is 0x00 in AccessControl
-	} else {
-		// For other roles, calculate the hash
-		roleHash = crypto.Keccak256Hash([]byte(roleIdentifier))
+		return common.Hash{} // DEFAULT_ADMIN_ROLE is 0x00 in AccessControl
 	}
 
+	// For other roles, calculate the hash
+	return crypto.Keccak256Hash([]byte(roleIdentifier))
+}
+
+// UserHasRole checks if a user has a specific role on the blockchain
+func UserHasRole(client Client, userAddr string, roleName string) (bool, error) {
 	// Set up call with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-
 	defer cancel()
-	callOpts := &bind.CallOpts{Context: ctx}
 
-	// Create caller interface
-	caller := bind.NewBoundContract(contractAddr, parsedABI, client, nil, nil)
+	return client.HasRole(ctx, RoleHash(roleName), common.HexToAddress(userAddr))
+}
 
-	// Make the actual call
-	var result []interface{}
-	err = caller.Call(callOpts, &result, "hasRole", roleHash, userAddress)
-	if err != nil {
-		return false, fmt.Errorf("contract call failed: %w", err)
-	}
+// OfferWindows holds the submission and review periods stored in an Offer contract
+type OfferWindows struct {
+	SubmissionStart time.Time
+	SubmissionEnd   time.Time
+	ReviewStart     time.Time
+	ReviewEnd       time.Time
+}
+
+// GetOfferWindows reads the time windows of an Offer contract
+func GetOfferWindows(client Client, offerAddress string) (OfferWindows, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// Check if we got a result and it's a boolean
-	if len(result) == 0 {
-		return false, fmt.Errorf("empty result from contract")
+	offer, err := client.Offer(common.HexToAddress(offerAddress))
+	if err != nil {
+		return OfferWindows{}, err
 	}
 
-	hasRole, ok := result[0].(bool)
-	if !ok {
-		return false, fmt.Errorf("unexpected return type: %T", result[0])
+	opts := &bind.CallOpts{Context: ctx}
+	var unix [4]int64
+	for i, call := range []func(*bind.CallOpts) (*big.Int, error){
+		offer.SubmissionStart, offer.SubmissionEnd, offer.ReviewStart, offer.ReviewEnd,
+	} {
+		value, err := call(opts)
+		if err != nil {
+			return OfferWindows{}, fmt.Errorf("reading offer windows: %w", err)
+		}
+		unix[i] = value.Int64()
 	}
 
-	return hasRole, nil
+	return OfferWindows{
+		SubmissionStart: time.Unix(unix[0], 0),
+		SubmissionEnd:   time.Unix(unix[1], 0),
+		ReviewStart:     time.Unix(unix[2], 0),
+		ReviewEnd:       time.Unix(unix[3], 0),
+	}, nil
 }
 
-// ChainSyncDB synchronizes user roles with blockchain data
-func ChainSyncDB() {
-	// Get all users with their roles
-	var users []models.User
-	if err := db.DB.DB.Preload("Roles").Find(&users).Error; err != nil {
-		log.Printf("Failed to load users: %v", err)
-		return
-	}
+// GetOfferStatus reads getStage, winnerDeclared and isClosed of an Offer contract
+// and maps them to a lifecycle status
+func GetOfferStatus(client Client, offerAddress string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	// Check each role against blockchain
-	for _, u := range users {
-		// Skip users without wallet address
-		if u.PublicWalletAddress == "" {
-			continue
-		}
+	offer, err := client.Offer(common.HexToAddress(offerAddress))
+	if err != nil {
+		return "", err
+	}
 
-		for _, role := range u.Roles {
-			// Check if role exists on blockchain
-			hasRole, err := UserHasRole(u.PublicWalletAddress, role.Name)
-			if err != nil {
-				log.Printf("User %d (%s): Error checking role %s: %v",
-					u.ID, u.PublicWalletAddress, role.Name, err)
-				continue
-			}
-
-			// Remove role if not on blockchain
-			if !hasRole {
-				if err := db.DB.DB.Model(&u).Association("Roles").Delete(&role); err != nil {
-					log.Printf("User %d: Failed to remove role %s: %v",
-						u.ID, role.Name, err)
-				} else {
-					log.Printf("User %d: Removed stale role %s", u.ID, role.Name)
-				}
-			}
-		}
+	opts := &bind.CallOpts{Context: ctx}
+	stage, err := offer.GetStage(opts)
+	if err != nil {
+		return "", fmt.Errorf("contract call getStage failed: %w", DecodeContractError(err))
 	}
+	winnerDeclared, err := offer.WinnerDeclared(opts)
+	if err != nil {
+		return "", fmt.Errorf("contract call winnerDeclared failed: %w", DecodeContractError(err))
+	}
+	isClosed, err := offer.IsClosed(opts)
+	if err != nil {
+		return "", fmt.Errorf("contract call isClosed failed: %w", DecodeContractError(err))
+	}
+
+	return models.OfferStatusFromStage(models.OfferStage(stage, isClosed), winnerDeclared), nil
 }
@@ -0,0 +1,535 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// indexerBatchSize is the maximum block range requested per eth_getLogs call
+	indexerBatchSize = 2000
+	// indexerReorgRewind is how many blocks the cursor is moved back when a reorg is detected
+	indexerReorgRewind = 64
+
+	indexerSource = "indexer"
+
+	cursorTypeFactory = "OfferFactory"
+	cursorTypeOffer   = "Offer"
+)
+
+// Indexer mirrors OfferFactory and Offer events into the database.
+// Every handler is an idempotent upsert so replaying a block range after a
+// restart or a reorg never duplicates rows.
+type Indexer struct {
+	store         store.Store
+	client        Client
+	seal          AuditSeal
+	confirmations uint64
+	startBlock    uint64
+	interval      time.Duration
+
+	factoryABI abi.ABI
+	offerABI   abi.ABI
+
+	// blockTimes caches block timestamps during a single sync pass
+	blockTimes map[uint64]time.Time
+}
+
+// NewIndexer builds an indexer from the configuration, reading the chain through client. The role
+// changes it mirrors are audited with seal.
+func NewIndexer(s store.Store, client Client, cfg config.Config, seal AuditSeal) (*Indexer, error) {
+	factory, err := getABI()
+	if err != nil {
+		return nil, err
+	}
+	offer, err := getOfferABI()
+	if err != nil {
+		return nil, err
+	}
+
+	interval, err := time.ParseDuration(cfg.IndexerPollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid INDEXER_POLL_INTERVAL: %w", err)
+	}
+	confirmations, err := strconv.ParseUint(cfg.IndexerConfirmations, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid INDEXER_CONFIRMATIONS: %w", err)
+	}
+	startBlock, err := strconv.ParseUint(cfg.IndexerStartBlock, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid INDEXER_START_BLOCK: %w", err)
+	}
+
+	return &Indexer{
+		store:         s,
+		client:        client,
+		seal:          seal,
+		confirmations: confirmations,
+		startBlock:    startBlock,
+		interval:      interval,
+		factoryABI:    factory,
+		offerABI:      offer,
+	}, nil
+}
+
+// Run polls the chain until ctx is cancelled
+func (idx *Indexer) Run(ctx context.Context) {
+	ticker := time.NewTicker(idx.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := idx.Sync(ctx); err != nil {
+			log.Printf("Indexer: sync failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync processes every tracked contract up to the latest confirmed block
+func (idx *Indexer) Sync(ctx context.Context) error {
+	client := idx.client
+
+	head, err := client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching block number: %w", err)
+	}
+	if head < idx.confirmations {
+		return nil
+	}
+	target := head - idx.confirmations
+
+	idx.blockTimes = make(map[uint64]time.Time)
+
+	// Factory first so newly created offers get a cursor in the same pass
+	factoryCursor, err := idx.ensureCursor(client.FactoryAddress(), cursorTypeFactory, idx.startBlock)
+	if err != nil {
+		return err
+	}
+	// Role changes of a reorged block are corrected by the replay and the role reconciler
+	if err := idx.syncContract(ctx, factoryCursor, target, idx.handleFactoryLog, nil); err != nil {
+		return fmt.Errorf("syncing factory: %w", err)
+	}
+
+	// Offers registered through the API may predate their OfferCreated log being indexed
+	addresses, err := idx.store.Offers().ContractAddresses()
+	if err != nil {
+		return fmt.Errorf("loading offers: %w", err)
+	}
+	for _, address := range addresses {
+		if !common.IsHexAddress(address) {
+			continue
+		}
+		if _, err := idx.ensureCursor(common.HexToAddress(address), cursorTypeOffer, idx.startBlock); err != nil {
+			return err
+		}
+	}
+
+	cursors, err := idx.store.ChainCursors().ListActive(cursorTypeOffer)
+	if err != nil {
+		return fmt.Errorf("loading cursors: %w", err)
+	}
+
+	for i := range cursors {
+		cursor := &cursors[i]
+
+		// Events can only be attached once the offer is registered in the database
+		offer, err := idx.store.Offers().GetByContract(cursor.ContractAddress)
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				log.Printf("Indexer: failed to load offer %s: %v", cursor.ContractAddress, err)
+			}
+			continue
+		}
+
+		handle := func(l types.Log) error {
+			return idx.handleOfferLog(cursor, offer, l)
+		}
+		rewind := func(ctx context.Context, from uint64) error {
+			return idx.recheckOfferRecords(ctx, offer, from)
+		}
+		if err := idx.syncContract(ctx, cursor, target, handle, rewind); err != nil {
+			log.Printf("Indexer: syncing offer %s failed: %v", cursor.ContractAddress, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureCursor returns the cursor of a contract, creating it at startBlock if missing
+func (idx *Indexer) ensureCursor(addr common.Address, contractType string, startBlock uint64) (*models.ChainCursor, error) {
+	cursor, err := idx.store.ChainCursors().Ensure(addr.Hex(), contractType, startBlock)
+	if err != nil {
+		return nil, fmt.Errorf("loading cursor for %s: %w", addr.Hex(), err)
+	}
+	return cursor, nil
+}
+
+// syncContract reads the logs of one contract from its cursor up to target. After a reorg the
+// cursor is moved back and rewind, if set, is called with the first block read again.
+func (idx *Indexer) syncContract(ctx context.Context, cursor *models.ChainCursor, target uint64,
+	handle func(types.Log) error, rewind func(ctx context.Context, from uint64) error) error {
+	client := idx.client
+
+	// Detect a reorg below the cursor by comparing the stored block hash
+	if cursor.NextBlock > 0 && cursor.LastBlockHash != "" {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(cursor.NextBlock-1))
+		if err != nil {
+			return fmt.Errorf("fetching header %d: %w", cursor.NextBlock-1, err)
+		}
+		if header.Hash().Hex() != cursor.LastBlockHash {
+			rewindTo := uint64(0)
+			if cursor.NextBlock > indexerReorgRewind {
+				rewindTo = cursor.NextBlock - indexerReorgRewind
+			}
+			log.Printf("Indexer: reorg detected for %s at block %d, rewinding to %d",
+				cursor.ContractAddress, cursor.NextBlock-1, rewindTo)
+			if rewind != nil {
+				if err := rewind(ctx, rewindTo); err != nil {
+					return fmt.Errorf("re-checking records from block %d: %w", rewindTo, err)
+				}
+			}
+			cursor.NextBlock = rewindTo
+			cursor.LastBlockHash = ""
+		}
+	}
+
+	address := common.HexToAddress(cursor.ContractAddress)
+	for from := cursor.NextBlock; from <= target && !cursor.Finished; {
+		to := from + indexerBatchSize - 1
+		if to > target {
+			to = target
+		}
+
+		logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{address},
+		})
+		if err != nil {
+			return fmt.Errorf("filtering logs %d-%d: %w", from, to, err)
+		}
+
+		for _, l := range logs {
+			if l.Removed {
+				continue
+			}
+			if err := handle(l); err != nil {
+				return fmt.Errorf("handling log %s#%d: %w", l.TxHash.Hex(), l.Index, err)
+			}
+		}
+
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return fmt.Errorf("fetching header %d: %w", to, err)
+		}
+
+		cursor.NextBlock = to + 1
+		cursor.LastBlockHash = header.Hash().Hex()
+		if err := idx.store.ChainCursors().Save(cursor); err != nil {
+			return fmt.Errorf("saving cursor: %w", err)
+		}
+
+		from = to + 1
+	}
+
+	return nil
+}
+
+// blockTime returns the timestamp of a block, cached for the current pass
+func (idx *Indexer) blockTime(blockNumber uint64) (time.Time, error) {
+	if t, ok := idx.blockTimes[blockNumber]; ok {
+		return t, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	header, err := idx.client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fetching header %d: %w", blockNumber, err)
+	}
+
+	t := time.Unix(int64(header.Time), 0)
+	idx.blockTimes[blockNumber] = t
+	return t, nil
+}
+
+// handleFactoryLog mirrors OfferCreated and AccessControl role events
+func (idx *Indexer) handleFactoryLog(l types.Log) error {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+
+	switch l.Topics[0] {
+	case idx.factoryABI.Events["OfferCreated"].ID:
+		if len(l.Topics) < 3 {
+			return nil
+		}
+		offerAddr := common.BytesToAddress(l.Topics[1].Bytes())
+
+		// Start tracking the new offer from the block it was deployed in
+		if _, err := idx.ensureCursor(offerAddr, cursorTypeOffer, l.BlockNumber); err != nil {
+			return err
+		}
+
+		offer, err := idx.store.Offers().GetByContract(offerAddr.Hex())
+		if errors.Is(err, store.ErrNotFound) {
+			// Not registered through the API yet, its events are picked up once it is
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// The contract is the source of truth for the time windows
+		windows, err := GetOfferWindows(idx.client, offerAddr.Hex())
+		if err != nil {
+			return err
+		}
+		return idx.store.Offers().SetWindows(offer.ID, windows.SubmissionStart, windows.SubmissionEnd, windows.ReviewStart, windows.ReviewEnd)
+
+	case idx.factoryABI.Events["RoleGranted"].ID, idx.factoryABI.Events["RoleRevoked"].ID:
+		if len(l.Topics) < 3 {
+			return nil
+		}
+		granted := l.Topics[0] == idx.factoryABI.Events["RoleGranted"].ID
+		return idx.syncUserRole(l.Topics[1], common.BytesToAddress(l.Topics[2].Bytes()), l.TxHash, granted)
+	}
+
+	return nil
+}
+
+// handleOfferLog mirrors proposal, review and closing events of a single offer
+func (idx *Indexer) handleOfferLog(cursor *models.ChainCursor, offer *models.Offer, l types.Log) error {
+	if len(l.Topics) == 0 {
+		return nil
+	}
+
+	switch l.Topics[0] {
+	case idx.offerABI.Events["ProposalSubmitted"].ID:
+		if len(l.Topics) < 2 {
+			return nil
+		}
+		var event ProposalSubmittedEvent
+		if err := idx.offerABI.UnpackIntoInterface(&event, "ProposalSubmitted", l.Data); err != nil {
+			return err
+		}
+		proposer, err := idx.userByWallet(common.BytesToAddress(l.Topics[1].Bytes()))
+		if err != nil || proposer == nil {
+			return err
+		}
+		submittedAt, err := idx.blockTime(l.BlockNumber)
+		if err != nil {
+			return err
+		}
+
+		return idx.store.Proposals().UpsertIndexed(&models.Proposal{
+			ContractID:     offer.ID,
+			ProposerID:     proposer.ID,
+			Details:        event.Description,
+			Price:          event.Price.String(),
+			Status:         models.ProposalStatusPending,
+			SubmittedAt:    submittedAt,
+			ProposalTxHash: l.TxHash.Hex(),
+			BlockNumber:    l.BlockNumber,
+			BlockHash:      l.BlockHash.Hex(),
+		})
+
+	case idx.offerABI.Events["ProposalReviewed"].ID:
+		if len(l.Topics) < 3 {
+			return nil
+		}
+		var event struct{ Score uint8 }
+		if err := idx.offerABI.UnpackIntoInterface(&event, "ProposalReviewed", l.Data); err != nil {
+			return err
+		}
+		proposal, err := idx.proposalByWallet(offer.ID, common.BytesToAddress(l.Topics[1].Bytes()))
+		if err != nil || proposal == nil {
+			return err
+		}
+		expert, err := idx.userByWallet(common.BytesToAddress(l.Topics[2].Bytes()))
+		if err != nil || expert == nil {
+			return err
+		}
+
+		return idx.store.Evaluations().UpsertIndexed(&models.ExpertEvaluation{
+			ProposalID:   proposal.ID,
+			ExpertID:     expert.ID,
+			Score:        float64(event.Score),
+			ReviewTxHash: l.TxHash.Hex(),
+			BlockNumber:  l.BlockNumber,
+			BlockHash:    l.BlockHash.Hex(),
+		})
+
+	case idx.offerABI.Events["WinnerDeclared"].ID:
+		if len(l.Topics) < 2 {
+			return nil
+		}
+		_, err := idx.store.Offers().DeclareWinner(offer, common.BytesToAddress(l.Topics[1].Bytes()).Hex(), l.TxHash.Hex())
+		return err
+
+	case idx.offerABI.Events["OfferClosedEvent"].ID:
+		// The WinnerDeclared log of the same transaction, if any, was handled before this one
+		if _, err := idx.store.Offers().Close(offer, "", l.TxHash.Hex()); err != nil {
+			return err
+		}
+		// A closed offer rejects every further call, stop polling it
+		cursor.Finished = true
+	}
+
+	return nil
+}
+
+// recheckOfferRecords re-checks the proposals and evaluations of the offer mined from block from on,
+// after a reorg their transaction may sit in another block or have left the chain. Records whose
+// transaction is no longer mined successfully are deleted, the replay records them again if it returns.
+func (idx *Indexer) recheckOfferRecords(ctx context.Context, offer *models.Offer, from uint64) error {
+	evaluations, err := idx.store.Evaluations().ListFromBlock(offer.ID, from)
+	if err != nil {
+		return fmt.Errorf("loading evaluations: %w", err)
+	}
+	for _, evaluation := range evaluations {
+		receipt, err := idx.minedReceipt(ctx, evaluation.ReviewTxHash)
+		if err != nil {
+			return err
+		}
+		if receipt == nil {
+			log.Printf("Indexer: review %s of evaluation %d left the chain, deleting it", evaluation.ReviewTxHash, evaluation.ID)
+			err = idx.store.Evaluations().Delete(evaluation.ID)
+		} else {
+			err = idx.store.Evaluations().SetBlock(evaluation.ID, receipt.BlockNumber.Uint64(), receipt.BlockHash.Hex())
+		}
+		if err != nil {
+			return fmt.Errorf("updating evaluation %d: %w", evaluation.ID, err)
+		}
+	}
+
+	proposals, err := idx.store.Proposals().ListFromBlock(offer.ID, from)
+	if err != nil {
+		return fmt.Errorf("loading proposals: %w", err)
+	}
+	for _, proposal := range proposals {
+		receipt, err := idx.minedReceipt(ctx, proposal.ProposalTxHash)
+		if err != nil {
+			return err
+		}
+		if receipt == nil {
+			log.Printf("Indexer: submission %s of proposal %d left the chain, deleting it", proposal.ProposalTxHash, proposal.ID)
+			err = idx.store.Proposals().Delete(proposal.ID)
+		} else {
+			err = idx.store.Proposals().SetBlock(proposal.ID, receipt.BlockNumber.Uint64(), receipt.BlockHash.Hex())
+		}
+		if err != nil {
+			return fmt.Errorf("updating proposal %d: %w", proposal.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// minedReceipt returns the receipt of a transaction successfully mined on the canonical chain, nil if it is not
+func (idx *Indexer) minedReceipt(ctx context.Context, txHash string) (*types.Receipt, error) {
+	receipt, err := idx.client.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching receipt of %s: %w", txHash, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, nil
+	}
+	return receipt, nil
+}
+
+// syncUserRole mirrors a RoleGranted/RoleRevoked event into user_roles and records the change in the audit events
+func (idx *Indexer) syncUserRole(roleHash common.Hash, account common.Address, txHash common.Hash, granted bool) error {
+	user, err := idx.userByWallet(account)
+	if err != nil || user == nil {
+		return err
+	}
+
+	roles, err := idx.store.Roles().List()
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if RoleHash(role.Name) != roleHash {
+			continue
+		}
+
+		action, reason := "granted", fmt.Sprintf("RoleGranted to wallet %s", account.Hex())
+		if !granted {
+			action, reason = "revoked", fmt.Sprintf("RoleRevoked from wallet %s", account.Hex())
+		}
+		changed, err := idx.store.Users().ApplyRoleChange(&models.RoleAuditLog{
+			UserID:   user.ID,
+			RoleID:   role.ID,
+			RoleName: role.Name,
+			Action:   action,
+			Source:   indexerSource,
+			Reason:   reason,
+		}, txHash.Hex())
+		if err != nil {
+			return err
+		}
+		// Replayed blocks find the role already mirrored, only an actual change is audited
+		if !changed {
+			return nil
+		}
+		log.Printf("Indexer: user %d role %s granted=%t", user.ID, role.Name, granted)
+
+		if err := recordRoleChange(idx.store.AuditEvents(), idx.seal, indexerSource, *user, role, action, reason, txHash.Hex()); err != nil {
+			log.Printf("Indexer: failed to record audit event for user %d role %s: %v", user.ID, role.Name, err)
+		}
+		return nil
+	}
+
+	log.Printf("Indexer: no DB role matches role hash %s", roleHash.Hex())
+	return nil
+}
+
+// userByWallet finds the user bound to a wallet, returning nil if there is none
+func (idx *Indexer) userByWallet(addr common.Address) (*models.User, error) {
+	user, err := idx.store.Users().GetByWallet(addr.Hex())
+	if errors.Is(err, store.ErrNotFound) {
+		log.Printf("Indexer: no user bound to wallet %s", addr.Hex())
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// proposalByWallet finds the proposal of an entrepreneur wallet on an offer, returning nil if there is none
+func (idx *Indexer) proposalByWallet(offerID uint, addr common.Address) (*models.Proposal, error) {
+	proposal, err := idx.store.Proposals().GetByWallet(offerID, addr.Hex())
+	if errors.Is(err, store.ErrNotFound) {
+		log.Printf("Indexer: no proposal from %s on offer %d", addr.Hex(), offerID)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
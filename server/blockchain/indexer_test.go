@@ -0,0 +1,258 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newTestIndexer(t *testing.T, st store.Store, client Client) *Indexer {
+	t.Helper()
+	idx, err := NewIndexer(st, client, config.Config{
+		IndexerPollInterval:  "1m",
+		IndexerConfirmations: "0",
+		IndexerStartBlock:    "0",
+	}, testSeal)
+	if err != nil {
+		t.Fatalf("creating indexer: %v", err)
+	}
+	return idx
+}
+
+// indexedOffer is a test offer registered in a memory store the indexer syncs into
+type indexedOffer struct {
+	*testOffer
+	store *store.MemoryStore
+	users *testUsers
+	offer models.Offer
+	idx   *Indexer
+}
+
+func newIndexedOffer(t *testing.T) *indexedOffer {
+	t.Helper()
+
+	offer := newTestOffer(t)
+	st, users := newTestStore(t, offer)
+	registered := models.Offer{Title: "Road works", ContractAddress: offer.address.Hex(), CreatedBy: users.tender.ID}
+	if err := st.Offers().Create(&registered); err != nil {
+		t.Fatalf("creating offer: %v", err)
+	}
+
+	return &indexedOffer{
+		testOffer: offer,
+		store:     st,
+		users:     users,
+		offer:     registered,
+		idx:       newTestIndexer(t, st, offer.sim),
+	}
+}
+
+func (o *indexedOffer) sync(t *testing.T) {
+	t.Helper()
+	if err := o.idx.Sync(context.Background()); err != nil {
+		t.Fatalf("syncing: %v", err)
+	}
+}
+
+// proposalOf returns the indexed proposal of user, nil if there is none
+func (o *indexedOffer) proposalOf(t *testing.T, user models.User) *models.Proposal {
+	t.Helper()
+	proposal, err := o.store.Proposals().GetByWallet(o.offer.ID, user.PublicWalletAddress)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		t.Fatalf("loading proposal of user %d: %v", user.ID, err)
+	}
+	return proposal
+}
+
+func (o *indexedOffer) cursor(t *testing.T) models.ChainCursor {
+	t.Helper()
+	cursors, err := o.store.ChainCursors().ListActive(cursorTypeOffer)
+	if err != nil {
+		t.Fatalf("listing cursors: %v", err)
+	}
+	for _, cursor := range cursors {
+		if cursor.ContractAddress == o.address.Hex() {
+			return cursor
+		}
+	}
+	t.Fatalf("no cursor for offer %s", o.address.Hex())
+	return models.ChainCursor{}
+}
+
+func TestIndexerSync(t *testing.T) {
+	o := newIndexedOffer(t)
+	o.advanceTo(t, o.windows.SubmissionStart)
+	submitted := o.transact(t, o.entrepreneur, "submitProposal", "road works", big.NewInt(125000))
+	o.advanceTo(t, o.windows.ReviewStart)
+	reviewed := o.transact(t, o.expert, "reviewProposal", crypto.PubkeyToAddress(o.entrepreneur.PublicKey), uint8(8))
+
+	o.sync(t)
+
+	offer, err := o.store.Offers().Get(o.offer.ID)
+	if err != nil {
+		t.Fatalf("loading offer: %v", err)
+	}
+	if offer.ProposalStart.Unix() != o.windows.SubmissionStart.Unix() || offer.ReviewEnd.Unix() != o.windows.ReviewEnd.Unix() {
+		t.Errorf("got windows %s - %s, want %s - %s", offer.ProposalStart, offer.ReviewEnd, o.windows.SubmissionStart, o.windows.ReviewEnd)
+	}
+
+	proposal := o.proposalOf(t, o.users.entrepreneur)
+	if proposal == nil {
+		t.Fatal("the submitted proposal is not indexed")
+	}
+	if proposal.ProposalTxHash != submitted.Hex() || proposal.Price != "125000" || !proposal.IndexedOnly {
+		t.Errorf("got proposal %s at %s (indexed only %t), want %s at 125000", proposal.ProposalTxHash, proposal.Price, proposal.IndexedOnly, submitted.Hex())
+	}
+
+	evaluations, err := o.store.Evaluations().ListFromBlock(o.offer.ID, 0)
+	if err != nil {
+		t.Fatalf("listing evaluations: %v", err)
+	}
+	if len(evaluations) != 1 || evaluations[0].ReviewTxHash != reviewed.Hex() || evaluations[0].Score != 8 {
+		t.Errorf("got evaluations %+v, want the review %s scoring 8", evaluations, reviewed.Hex())
+	}
+
+	// Every role granted by the factory is mirrored on the user of its wallet
+	for _, tt := range []struct {
+		user models.User
+		role string
+	}{
+		{o.users.tender, "tender"},
+		{o.users.entrepreneur, "entrepreneur"},
+		{o.users.competitor, "entrepreneur"},
+		{o.users.expert, "expert"},
+	} {
+		user, err := o.store.Users().Get(tt.user.ID)
+		if err != nil {
+			t.Fatalf("loading user %d: %v", tt.user.ID, err)
+		}
+		if names := roleNames(user); len(names) != 1 || names[0] != tt.role {
+			t.Errorf("user %s holds %v, want [%s]", tt.user.Email, names, tt.role)
+		}
+	}
+
+	// Replaying the same blocks changes nothing
+	head, err := o.sim.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatalf("reading head: %v", err)
+	}
+	cursor := o.cursor(t)
+	if cursor.NextBlock != head+1 {
+		t.Errorf("cursor at block %d, want %d", cursor.NextBlock, head+1)
+	}
+	cursor.NextBlock, cursor.LastBlockHash = 0, ""
+	if err := o.store.ChainCursors().Save(&cursor); err != nil {
+		t.Fatalf("resetting cursor: %v", err)
+	}
+	o.sync(t)
+
+	if _, count, err := o.store.RoleAuditLogs().List(nil, store.Page{Number: 1, Size: 50}); err != nil || count != 4 {
+		t.Errorf("got %d role audit logs (%v), want the 4 grants once", count, err)
+	}
+	if again := o.proposalOf(t, o.users.entrepreneur); again == nil || again.ID != proposal.ID {
+		t.Errorf("replay replaced proposal %d with %+v", proposal.ID, again)
+	}
+}
+
+func TestIndexerSyncReorg(t *testing.T) {
+	o := newIndexedOffer(t)
+	o.advanceTo(t, o.windows.SubmissionStart)
+
+	ctx := context.Background()
+	fork, err := o.sim.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatalf("reading head: %v", err)
+	}
+	submitted := o.transact(t, o.entrepreneur, "submitProposal", "road works", big.NewInt(125000))
+	o.sync(t)
+
+	if o.proposalOf(t, o.users.entrepreneur) == nil {
+		t.Fatal("the submitted proposal is not indexed")
+	}
+	indexed := o.cursor(t)
+	original, _, err := o.sim.Backend.Client().TransactionByHash(ctx, submitted)
+	if err != nil {
+		t.Fatalf("loading submission: %v", err)
+	}
+
+	// The side chain spends the nonce of the submission on a transfer, so the submission can never
+	// be mined again, and grows past the block the cursor stopped at
+	if err := o.sim.Backend.Fork(fork.Hash()); err != nil {
+		t.Fatalf("forking: %v", err)
+	}
+	o.replaceTransaction(t, o.entrepreneur, original)
+	o.sim.Backend.Commit()
+	o.sim.Backend.Commit()
+
+	head, err := o.sim.HeaderByNumber(ctx, nil)
+	if err != nil {
+		t.Fatalf("reading head: %v", err)
+	}
+	replaced, err := o.sim.HeaderByNumber(ctx, new(big.Int).SetUint64(indexed.NextBlock-1))
+	if err != nil {
+		t.Fatalf("reading header %d: %v", indexed.NextBlock-1, err)
+	}
+	if replaced.Hash().Hex() == indexed.LastBlockHash {
+		t.Fatal("the fork did not replace the block the cursor stopped at")
+	}
+
+	o.sync(t)
+
+	if proposal := o.proposalOf(t, o.users.entrepreneur); proposal != nil {
+		t.Errorf("proposal %d of a submission that left the chain is still indexed", proposal.ID)
+	}
+	cursor := o.cursor(t)
+	if cursor.NextBlock != head.Number.Uint64()+1 || cursor.LastBlockHash != head.Hash().Hex() {
+		t.Errorf("cursor at block %d (%s), want %d (%s)",
+			cursor.NextBlock, cursor.LastBlockHash, head.Number.Uint64()+1, head.Hash().Hex())
+	}
+}
+
+// replaceTransaction sends a transfer from key to itself with the nonce of original and a higher tip,
+// whether the pool still holds original or not only the transfer can be mined. The pool moves to
+// the forked head in the background, the transfer is retried until it stops seeing the old nonce.
+func (o *indexedOffer) replaceTransaction(t *testing.T, key *ecdsa.PrivateKey, original *types.Transaction) {
+	t.Helper()
+	ctx := context.Background()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	tip := new(big.Int).Mul(original.GasTipCap(), big.NewInt(10))
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   o.sim.chainID,
+		Nonce:     original.Nonce(),
+		GasTipCap: tip,
+		GasFeeCap: new(big.Int).Add(new(big.Int).Mul(original.GasFeeCap(), big.NewInt(10)), tip),
+		Gas:       21000,
+		To:        &from,
+		Value:     big.NewInt(0),
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(o.sim.chainID), key)
+	if err != nil {
+		t.Fatalf("signing transfer: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := o.sim.Backend.Client().SendTransaction(ctx, signed)
+		if err == nil {
+			return
+		}
+		if !strings.Contains(err.Error(), "nonce too low") || time.Now().After(deadline) {
+			t.Fatalf("sending transfer: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,173 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OfferState is the state of an Offer contract read at a single block
+type OfferState struct {
+	Address             string               `json:"address"`
+	BlockNumber         uint64               `json:"blockNumber"`
+	Stage               uint8                `json:"stage"` // models.OfferStage*, closed is told apart from ended
+	Status              string               `json:"status"`
+	ProposalCount       uint64               `json:"proposalCount"`
+	Proposals           []OfferProposalState `json:"proposals"`
+	WinnerDeclared      bool                 `json:"winnerDeclared"`
+	WinningEntrepreneur *string              `json:"winningEntrepreneur"` // nil until a winner is declared
+	IsClosed            bool                 `json:"isClosed"`
+	FetchedAt           time.Time            `json:"fetchedAt"`
+}
+
+// OfferProposalState is a proposal as stored in an Offer contract
+type OfferProposalState struct {
+	Entrepreneur   string `json:"entrepreneur"`
+	Description    string `json:"description"`
+	Price          string `json:"price"` // uint256 as a decimal string
+	TotalScore     string `json:"totalScore"`
+	ReviewCount    uint64 `json:"reviewCount"`
+	ReviewersCount uint64 `json:"reviewersCount"`
+}
+
+type offerStateEntry struct {
+	state     *OfferState
+	expiresAt time.Time
+}
+
+// offerStateCache keeps the offer states read from the chain for ttl, so repeated page loads do
+// not each hit the node
+type offerStateCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]offerStateEntry
+}
+
+// newOfferStateCache builds the cache from OFFER_CHAIN_CACHE_TTL
+func newOfferStateCache(cfg config.Config) (*offerStateCache, error) {
+	ttl, err := time.ParseDuration(cfg.OfferChainCacheTTL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OFFER_CHAIN_CACHE_TTL: %w", err)
+	}
+	return &offerStateCache{ttl: ttl, entries: map[string]offerStateEntry{}}, nil
+}
+
+// get returns the on-chain state of an Offer contract, reading it through client once the cached one expired
+func (c *offerStateCache) get(client Client, offerAddress string) (*OfferState, error) {
+	if !common.IsHexAddress(offerAddress) {
+		return nil, ErrInvalidAddress
+	}
+
+	key := strings.ToLower(offerAddress)
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.state, nil
+	}
+
+	state, err := readOfferState(client, common.HexToAddress(offerAddress))
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	// drop expired entries while we hold the lock, the cache only grows with the number of offers viewed
+	for address, entry := range c.entries {
+		if !state.FetchedAt.Before(entry.expiresAt) {
+			delete(c.entries, address)
+		}
+	}
+	c.entries[key] = offerStateEntry{state: state, expiresAt: state.FetchedAt.Add(c.ttl)}
+	c.mu.Unlock()
+
+	return state, nil
+}
+
+// readOfferState reads every view of an Offer contract at the latest block, so the values are consistent
+func readOfferState(client Client, address common.Address) (*OfferState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	offer, err := client.Offer(address)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNumber, err := client.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading block number: %w", err)
+	}
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNumber)}
+
+	state := &OfferState{
+		Address:     address.Hex(),
+		BlockNumber: blockNumber,
+		Proposals:   []OfferProposalState{},
+		FetchedAt:   time.Now(),
+	}
+
+	stage, err := offer.GetStage(opts)
+	if err != nil {
+		return nil, fmt.Errorf("contract call getStage failed: %w", DecodeContractError(err))
+	}
+	if state.WinnerDeclared, err = offer.WinnerDeclared(opts); err != nil {
+		return nil, fmt.Errorf("contract call winnerDeclared failed: %w", DecodeContractError(err))
+	}
+	if state.IsClosed, err = offer.IsClosed(opts); err != nil {
+		return nil, fmt.Errorf("contract call isClosed failed: %w", DecodeContractError(err))
+	}
+	state.Stage = models.OfferStage(stage, state.IsClosed)
+	state.Status = models.OfferStatusFromStage(state.Stage, state.WinnerDeclared)
+
+	if state.WinnerDeclared {
+		winner, err := offer.WinningEntrepreneur(opts)
+		if err != nil {
+			return nil, fmt.Errorf("contract call winningEntrepreneur failed: %w", DecodeContractError(err))
+		}
+		hex := winner.Hex()
+		state.WinningEntrepreneur = &hex
+	}
+
+	count, err := offer.GetProposalCount(opts)
+	if err != nil {
+		return nil, fmt.Errorf("contract call getProposalCount failed: %w", DecodeContractError(err))
+	}
+	state.ProposalCount = count.Uint64()
+
+	for i := uint64(0); i < state.ProposalCount; i++ {
+		entrepreneur, err := offer.GetEntrepreneurByIndex(opts, new(big.Int).SetUint64(i))
+		if err != nil {
+			return nil, fmt.Errorf("contract call getEntrepreneurByIndex(%d) failed: %w", i, DecodeContractError(err))
+		}
+
+		_, description, price, totalScore, reviewCount, err := offer.GetProposal(opts, entrepreneur)
+		if err != nil {
+			return nil, fmt.Errorf("contract call getProposal(%s) failed: %w", entrepreneur.Hex(), DecodeContractError(err))
+		}
+
+		reviewers, err := offer.GetReviewersCount(opts, entrepreneur)
+		if err != nil {
+			return nil, fmt.Errorf("contract call getReviewersCount(%s) failed: %w", entrepreneur.Hex(), DecodeContractError(err))
+		}
+
+		state.Proposals = append(state.Proposals, OfferProposalState{
+			Entrepreneur:   entrepreneur.Hex(),
+			Description:    description,
+			Price:          price.String(),
+			TotalScore:     totalScore.String(),
+			ReviewCount:    reviewCount.Uint64(),
+			ReviewersCount: reviewers.Uint64(),
+		})
+	}
+
+	return state, nil
+}
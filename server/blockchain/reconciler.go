@@ -0,0 +1,332 @@
+package blockchain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+)
+
+// Role reconciliation policies
+const (
+	// RolePolicyChain makes the DB follow the chain: DB-only roles are removed, chain-only roles added
+	RolePolicyChain = "chain-authoritative"
+	// RolePolicyDB keeps the DB as is and lists the grant/revoke transactions the chain needs
+	RolePolicyDB = "db-authoritative"
+	// RolePolicyReport only reports the differences
+	RolePolicyReport = "report-only"
+)
+
+const roleReconcilerSource = "role-reconciler"
+
+// AuditSeal seals an audit event before it is appended, it is audit.Seal. The workers take it as
+// a function since the audit package already depends on this one.
+type AuditSeal func(event *models.AuditEvent, prevHash string)
+
+// recordRoleChange appends a role change applied by source to the audit events, next to the
+// changes admins make through user.role.grant and user.role.revoke. No request made it, so it has
+// no actor, method or status and its path names the worker.
+func recordRoleChange(events store.AuditEventRepository, seal AuditSeal, source string, user models.User, role models.Role, action, reason, txHash string) error {
+	change, err := json.Marshal(map[string]interface{}{
+		"role":   role.Name,
+		"wallet": user.PublicWalletAddress,
+		"reason": reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	targetID := user.ID
+	event := &models.AuditEvent{
+		Action:     "user.role.grant",
+		Path:       source,
+		TargetType: "User",
+		TargetID:   &targetID,
+		After:      change,
+		TxHash:     txHash,
+	}
+	if action == "revoked" {
+		event.Action = "user.role.revoke"
+		event.Before, event.After = change, nil
+	}
+	return events.Append(event, seal)
+}
+
+// RoleDiff compares the roles of a user in the DB with the roles of their wallet on chain
+type RoleDiff struct {
+	UserID    uint     `json:"userID"`
+	Email     string   `json:"email"`
+	Wallet    string   `json:"wallet"`
+	Matching  []string `json:"matching"`
+	DBOnly    []string `json:"dbOnly"`
+	ChainOnly []string `json:"chainOnly"`
+	Error     string   `json:"error,omitempty"` // the user could not be checked, nothing is applied
+}
+
+// InSync reports whether the DB and the chain agree for the user
+func (d RoleDiff) InSync() bool {
+	return d.Error == "" && len(d.DBOnly) == 0 && len(d.ChainOnly) == 0
+}
+
+// RoleChange is a single role change, applied to the DB or required on chain
+type RoleChange struct {
+	UserID uint   `json:"userID"`
+	Wallet string `json:"wallet"`
+	Role   string `json:"role"`
+	Action string `json:"action"` // granted or revoked
+	Target string `json:"target"` // db or chain
+}
+
+// RoleReconciliation is the outcome of a reconciliation run
+type RoleReconciliation struct {
+	Policy      string       `json:"policy"`
+	GeneratedAt time.Time    `json:"generatedAt"`
+	Users       []RoleDiff   `json:"users"`
+	Conflicts   int          `json:"conflicts"`
+	Applied     []RoleChange `json:"applied"` // changes written to the DB
+	Required    []RoleChange `json:"required"`
+}
+
+// IsValidRolePolicy reports whether policy is a known reconciliation policy
+func IsValidRolePolicy(policy string) bool {
+	switch policy {
+	case RolePolicyChain, RolePolicyDB, RolePolicyReport:
+		return true
+	}
+	return false
+}
+
+// RoleReconciler periodically compares DB roles with on-chain roles and applies its policy
+type RoleReconciler struct {
+	store    store.Store
+	client   Client
+	seal     AuditSeal
+	interval time.Duration
+	policy   string
+
+	mu   sync.RWMutex
+	last *RoleReconciliation
+}
+
+// NewRoleReconciler builds a reconciler from the configuration, checking the roles through client
+// and sealing the audit events of the changes it applies with seal
+func NewRoleReconciler(s store.Store, client Client, cfg config.Config, seal AuditSeal) (*RoleReconciler, error) {
+	interval, err := time.ParseDuration(cfg.RoleReconcileInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ROLE_RECONCILE_INTERVAL: %w", err)
+	}
+
+	if !IsValidRolePolicy(cfg.RoleReconcilePolicy) {
+		return nil, fmt.Errorf("invalid ROLE_RECONCILE_POLICY %q", cfg.RoleReconcilePolicy)
+	}
+
+	return &RoleReconciler{store: s, client: client, seal: seal, interval: interval, policy: cfg.RoleReconcilePolicy}, nil
+}
+
+// Policy returns the configured reconciliation policy
+func (rr *RoleReconciler) Policy() string {
+	return rr.policy
+}
+
+// Run reconciles roles until ctx is cancelled
+func (rr *RoleReconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(rr.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := rr.Reconcile(rr.policy); err != nil {
+			log.Printf("Role reconciler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Last returns the outcome of the latest run, nil if none ran yet
+func (rr *RoleReconciler) Last() *RoleReconciliation {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+	return rr.last
+}
+
+// Reconcile computes the role diff of every user against the roles read on chain and applies policy to it.
+// The changes it applies are appended to the audit events.
+func (rr *RoleReconciler) Reconcile(policy string) (*RoleReconciliation, error) {
+	if !IsValidRolePolicy(policy) {
+		return nil, fmt.Errorf("invalid role reconciliation policy %q", policy)
+	}
+
+	roles, err := rr.store.Roles().List()
+	if err != nil {
+		return nil, fmt.Errorf("loading roles: %w", err)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+
+	users, err := rr.store.Users().ListWithRoles()
+	if err != nil {
+		return nil, fmt.Errorf("loading users: %w", err)
+	}
+
+	report := &RoleReconciliation{
+		Policy:      policy,
+		GeneratedAt: time.Now(),
+		Users:       make([]RoleDiff, 0, len(users)),
+		Applied:     []RoleChange{},
+		Required:    []RoleChange{},
+	}
+
+	for _, user := range users {
+		diff := diffUserRoles(rr.client, user, roles)
+		report.Users = append(report.Users, diff)
+		if diff.InSync() {
+			continue
+		}
+
+		report.Conflicts++
+		if diff.Error != "" {
+			continue
+		}
+
+		switch policy {
+		case RolePolicyChain:
+			applied, err := rr.applyChainRoles(user, roles, diff)
+			report.Applied = append(report.Applied, applied...)
+			if err != nil {
+				log.Printf("Role reconciler: user %d: %v", user.ID, err)
+			}
+		case RolePolicyDB:
+			for _, role := range diff.DBOnly {
+				report.Required = append(report.Required, RoleChange{UserID: user.ID, Wallet: diff.Wallet, Role: role, Action: "granted", Target: "chain"})
+			}
+			for _, role := range diff.ChainOnly {
+				report.Required = append(report.Required, RoleChange{UserID: user.ID, Wallet: diff.Wallet, Role: role, Action: "revoked", Target: "chain"})
+			}
+		}
+	}
+
+	if report.Conflicts > 0 {
+		log.Printf("Role reconciler: %d user(s) out of sync, policy %s, %d change(s) applied",
+			report.Conflicts, policy, len(report.Applied))
+	}
+
+	rr.mu.Lock()
+	rr.last = report
+	rr.mu.Unlock()
+
+	return report, nil
+}
+
+// diffUserRoles checks every known role of the user wallet on chain against the DB roles
+func diffUserRoles(client Client, user models.User, roles []models.Role) RoleDiff {
+	diff := RoleDiff{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Wallet:    user.PublicWalletAddress,
+		Matching:  []string{},
+		DBOnly:    []string{},
+		ChainOnly: []string{},
+	}
+
+	dbRoles := make(map[string]bool, len(user.Roles))
+	for _, role := range user.Roles {
+		dbRoles[role.Name] = true
+	}
+
+	if user.PublicWalletAddress == "" {
+		if len(user.Roles) > 0 {
+			diff.Error = "no wallet bound, roles cannot be checked on chain"
+			for name := range dbRoles {
+				diff.DBOnly = append(diff.DBOnly, name)
+			}
+			sort.Strings(diff.DBOnly)
+		}
+		return diff
+	}
+
+	for _, role := range roles {
+		onChain, err := UserHasRole(client, user.PublicWalletAddress, role.Name)
+		if err != nil {
+			diff.Error = fmt.Sprintf("checking role %s: %v", role.Name, err)
+			return diff
+		}
+
+		switch {
+		case onChain && dbRoles[role.Name]:
+			diff.Matching = append(diff.Matching, role.Name)
+		case dbRoles[role.Name]:
+			diff.DBOnly = append(diff.DBOnly, role.Name)
+		case onChain:
+			diff.ChainOnly = append(diff.ChainOnly, role.Name)
+		}
+	}
+
+	return diff
+}
+
+// applyChainRoles makes the DB roles of the user match the chain, logging every change in the role
+// audit log and the audit events
+func (rr *RoleReconciler) applyChainRoles(user models.User, roles []models.Role, diff RoleDiff) ([]RoleChange, error) {
+	byName := make(map[string]models.Role, len(roles))
+	for _, role := range roles {
+		byName[role.Name] = role
+	}
+
+	var applied []RoleChange
+	apply := func(roleName, action string) error {
+		role := byName[roleName]
+		reason := fmt.Sprintf("wallet %s holds the role on chain", user.PublicWalletAddress)
+		if action == "revoked" {
+			reason = fmt.Sprintf("wallet %s does not hold the role on chain", user.PublicWalletAddress)
+		}
+
+		changed, err := rr.store.Users().ApplyRoleChange(&models.RoleAuditLog{
+			UserID:   user.ID,
+			RoleID:   role.ID,
+			RoleName: role.Name,
+			Action:   action,
+			Source:   roleReconcilerSource,
+			Policy:   RolePolicyChain,
+			Reason:   reason,
+		}, "")
+		if err != nil {
+			return fmt.Errorf("applying %s %s: %w", action, roleName, err)
+		}
+		// Another writer applied the same change since the users were loaded
+		if !changed {
+			return nil
+		}
+
+		log.Printf("Role reconciler: user %d role %s %s", user.ID, roleName, action)
+		applied = append(applied, RoleChange{UserID: user.ID, Wallet: user.PublicWalletAddress, Role: roleName, Action: action, Target: "db"})
+
+		// The change is applied, a failed append is logged rather than reported as a failed change
+		if err := recordRoleChange(rr.store.AuditEvents(), rr.seal, roleReconcilerSource, user, role, action, reason, ""); err != nil {
+			log.Printf("Role reconciler: failed to record audit event for user %d role %s: %v", user.ID, roleName, err)
+		}
+		return nil
+	}
+
+	for _, roleName := range diff.DBOnly {
+		if err := apply(roleName, "revoked"); err != nil {
+			return applied, err
+		}
+	}
+	for _, roleName := range diff.ChainOnly {
+		if err := apply(roleName, "granted"); err != nil {
+			return applied, err
+		}
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,233 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+)
+
+// testSeal chains the audit events like audit.Seal without depending on the audit package
+func testSeal(event *models.AuditEvent, prevHash string) {
+	sum := sha256.Sum256([]byte(prevHash + event.Action))
+	event.PrevHash = prevHash
+	event.Hash = hex.EncodeToString(sum[:])
+}
+
+// testUsers are the users bound to the wallets of a test offer, each holding the DB roles it is given
+type testUsers struct {
+	roles        map[string]models.Role
+	tender       models.User
+	entrepreneur models.User
+	competitor   models.User
+	expert       models.User
+}
+
+// newTestStore returns a memory store with the tender, entrepreneur and expert roles and a user
+// for every wallet of offer, the users hold no role yet
+func newTestStore(t *testing.T, offer *testOffer) (*store.MemoryStore, *testUsers) {
+	t.Helper()
+
+	st := store.NewMemory()
+	users := &testUsers{roles: map[string]models.Role{}}
+	for _, name := range []string{"tender", "entrepreneur", "expert"} {
+		role := models.Role{Name: name}
+		if err := st.Roles().Create(&role); err != nil {
+			t.Fatalf("creating role %s: %v", name, err)
+		}
+		users.roles[name] = role
+	}
+
+	for _, u := range []struct {
+		user  *models.User
+		email string
+		key   string
+	}{
+		{&users.tender, "tender@example.com", walletOf(offer.tender)},
+		{&users.entrepreneur, "entrepreneur@example.com", walletOf(offer.entrepreneur)},
+		{&users.competitor, "competitor@example.com", walletOf(offer.competitor)},
+		{&users.expert, "expert@example.com", walletOf(offer.expert)},
+	} {
+		*u.user = models.User{Email: u.email, PublicWalletAddress: u.key}
+		if err := st.Users().Create(u.user); err != nil {
+			t.Fatalf("creating user %s: %v", u.email, err)
+		}
+	}
+
+	return st, users
+}
+
+// grant gives user the DB role name
+func (u *testUsers) grant(t *testing.T, st store.Store, user models.User, name string) {
+	t.Helper()
+	if err := st.Users().GrantRole(&models.UserRole{UserID: user.ID, RoleID: u.roles[name].ID}); err != nil {
+		t.Fatalf("granting %s to user %d: %v", name, user.ID, err)
+	}
+}
+
+func newTestReconciler(t *testing.T, st store.Store, client Client) *RoleReconciler {
+	t.Helper()
+	rr, err := NewRoleReconciler(st, client, config.Config{
+		RoleReconcileInterval: "1m",
+		RoleReconcilePolicy:   RolePolicyReport,
+	}, testSeal)
+	if err != nil {
+		t.Fatalf("creating reconciler: %v", err)
+	}
+	return rr
+}
+
+func roleNames(user *models.User) []string {
+	names := []string{}
+	for _, role := range user.Roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+func TestDiffUserRoles(t *testing.T) {
+	offer := newTestOffer(t)
+	st, users := newTestStore(t, offer)
+	roles, err := st.Roles().List()
+	if err != nil {
+		t.Fatalf("listing roles: %v", err)
+	}
+	byName := func(names ...string) []models.Role {
+		held := []models.Role{}
+		for _, name := range names {
+			held = append(held, users.roles[name])
+		}
+		return held
+	}
+
+	tests := []struct {
+		name          string
+		user          models.User
+		wantMatching  []string
+		wantDBOnly    []string
+		wantChainOnly []string
+		wantError     bool
+	}{
+		{
+			name:         "roles matching the chain",
+			user:         models.User{PublicWalletAddress: walletOf(offer.tender), Roles: byName("tender")},
+			wantMatching: []string{"tender"}, wantDBOnly: []string{}, wantChainOnly: []string{},
+		},
+		{
+			name:         "role missing in the DB and role missing on chain",
+			user:         models.User{PublicWalletAddress: walletOf(offer.entrepreneur), Roles: byName("expert")},
+			wantMatching: []string{}, wantDBOnly: []string{"expert"}, wantChainOnly: []string{"entrepreneur"},
+		},
+		{
+			name:         "wallet holding no DB role",
+			user:         models.User{PublicWalletAddress: walletOf(offer.expert)},
+			wantMatching: []string{}, wantDBOnly: []string{}, wantChainOnly: []string{"expert"},
+		},
+		{
+			name:         "roles without a wallet",
+			user:         models.User{Roles: byName("tender", "expert")},
+			wantMatching: []string{}, wantDBOnly: []string{"expert", "tender"}, wantChainOnly: []string{},
+			wantError: true,
+		},
+		{
+			name:         "no wallet and no role",
+			user:         models.User{},
+			wantMatching: []string{}, wantDBOnly: []string{}, wantChainOnly: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffUserRoles(offer.sim, tt.user, roles)
+			if (diff.Error != "") != tt.wantError {
+				t.Fatalf("got error %q, want error %t", diff.Error, tt.wantError)
+			}
+			if !reflect.DeepEqual(diff.Matching, tt.wantMatching) ||
+				!reflect.DeepEqual(diff.DBOnly, tt.wantDBOnly) ||
+				!reflect.DeepEqual(diff.ChainOnly, tt.wantChainOnly) {
+				t.Errorf("got matching %v, DB only %v, chain only %v; want %v, %v, %v",
+					diff.Matching, diff.DBOnly, diff.ChainOnly, tt.wantMatching, tt.wantDBOnly, tt.wantChainOnly)
+			}
+		})
+	}
+}
+
+func TestReconcileRoles(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       string
+		wantApplied  int
+		wantRequired int
+		// wantEntrepreneur are the DB roles of the entrepreneur after the run
+		wantEntrepreneur []string
+	}{
+		{"report only", RolePolicyReport, 0, 0, []string{"expert"}},
+		{"DB authoritative", RolePolicyDB, 0, 4, []string{"expert"}},
+		{"chain authoritative", RolePolicyChain, 4, 0, []string{"entrepreneur"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offer := newTestOffer(t)
+			st, users := newTestStore(t, offer)
+			// The tender is in sync, the entrepreneur holds the wrong role, the competitor and the expert none
+			users.grant(t, st, users.tender, "tender")
+			users.grant(t, st, users.entrepreneur, "expert")
+
+			rr := newTestReconciler(t, st, offer.sim)
+			if rr.Last() != nil {
+				t.Fatal("a run is reported before any ran")
+			}
+
+			report, err := rr.Reconcile(tt.policy)
+			if err != nil {
+				t.Fatalf("reconciling: %v", err)
+			}
+			if report.Conflicts != 3 {
+				t.Errorf("got %d conflicts, want 3", report.Conflicts)
+			}
+			if len(report.Applied) != tt.wantApplied || len(report.Required) != tt.wantRequired {
+				t.Errorf("got %d applied and %d required changes, want %d and %d",
+					len(report.Applied), len(report.Required), tt.wantApplied, tt.wantRequired)
+			}
+			if rr.Last() != report {
+				t.Error("the run is not reported as the last one")
+			}
+
+			entrepreneur, err := st.Users().Get(users.entrepreneur.ID)
+			if err != nil {
+				t.Fatalf("loading entrepreneur: %v", err)
+			}
+			if got := roleNames(entrepreneur); !reflect.DeepEqual(got, tt.wantEntrepreneur) {
+				t.Errorf("entrepreneur holds %v, want %v", got, tt.wantEntrepreneur)
+			}
+
+			_, logs, err := st.RoleAuditLogs().List(nil, store.Page{Number: 1, Size: 50})
+			if err != nil {
+				t.Fatalf("listing role audit logs: %v", err)
+			}
+			_, events, err := st.AuditEvents().List(store.AuditEventFilter{}, store.Page{Number: 1, Size: 50})
+			if err != nil {
+				t.Fatalf("listing audit events: %v", err)
+			}
+			if logs != int64(tt.wantApplied) || events != int64(tt.wantApplied) {
+				t.Errorf("got %d role audit logs and %d audit events, want %d of each", logs, events, tt.wantApplied)
+			}
+
+			// A second chain-authoritative run finds nothing left to apply
+			if tt.policy == RolePolicyChain {
+				again, err := rr.Reconcile(tt.policy)
+				if err != nil {
+					t.Fatalf("reconciling again: %v", err)
+				}
+				if again.Conflicts != 0 || len(again.Applied) != 0 {
+					t.Errorf("second run found %d conflicts and applied %d changes, want none", again.Conflicts, len(again.Applied))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,696 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/google/uuid"
+)
+
+// Contract methods the relayer can call
+const (
+	RelayActionSubmitProposal = "submitProposal"
+	RelayActionReviewProposal = "reviewProposal"
+	RelayActionCreateOffer    = "createOffer"
+	RelayActionGrantRole      = "grantRole"
+	RelayActionAnchor         = "anchor" // not a contract call, a transaction carrying a hash as data
+)
+
+// relayActionKinds maps the relayed contract methods to the kind they are tracked as
+var relayActionKinds = map[string]string{
+	RelayActionSubmitProposal: models.ChainTxProposalSubmitted,
+	RelayActionReviewProposal: models.ChainTxProposalReviewed,
+	RelayActionCreateOffer:    models.ChainTxOfferCreated,
+	RelayActionGrantRole:      models.ChainTxRoleGranted,
+	RelayActionAnchor:         models.ChainTxAuditAnchor,
+}
+
+// gasLimitBufferPercent is added to the estimated gas, the state may change between estimate and inclusion
+const gasLimitBufferPercent = 20
+
+// feeBumpPercent is the increase of the tip and fee caps when replacing a stuck transaction, nodes
+// reject a replacement that does not raise both by at least 10%
+const feeBumpPercent = 10
+
+// Errors returned by the relayer
+var (
+	ErrNoCustodialWallet      = errors.New("no custodial wallet for this account")
+	ErrCustodialWalletExists  = errors.New("account already has a wallet")
+	ErrCustodialWalletUnbound = errors.New("the wallet bound to this account is not its custodial wallet")
+	ErrNoPlatformSigner       = errors.New("no platform signer is configured")
+	ErrTxWouldRevert          = errors.New("transaction would revert")
+	ErrFeeTooHigh             = errors.New("network fee is above the configured maximum, try again later")
+)
+
+// Relayer signs and broadcasts contract transactions for users without their own wallet.
+// User actions are signed with the user's custodial key, admin actions with the platform key.
+// Every transaction is persisted before it is broadcast and followed by Run until it is mined.
+type Relayer struct {
+	store            store.Store
+	client           Client
+	passphrase       string
+	platformKey      *ecdsa.PrivateKey
+	pollInterval     time.Duration
+	rebroadcastAfter time.Duration
+	maxFeeCap        *big.Int
+
+	signerLocks sync.Map // signer address -> *sync.Mutex, serializes nonce allocation
+}
+
+// NewRelayer builds a relayer from the configuration, transactions are recorded in s and sent through client
+func NewRelayer(s store.Store, client Client, cfg config.Config) (*Relayer, error) {
+	if cfg.RelayerKeystorePassphrase == "" {
+		return nil, errors.New("RELAYER_KEYSTORE_PASSPHRASE is required when the relayer is enabled")
+	}
+
+	pollInterval, err := time.ParseDuration(cfg.RelayerPollInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RELAYER_POLL_INTERVAL: %w", err)
+	}
+
+	rebroadcastAfter, err := time.ParseDuration(cfg.RelayerRebroadcastAfter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RELAYER_REBROADCAST_AFTER: %w", err)
+	}
+
+	maxFeeGwei, err := strconv.ParseUint(cfg.RelayerMaxFeeGwei, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RELAYER_MAX_FEE_GWEI: %w", err)
+	}
+
+	relayer := &Relayer{
+		store:            s,
+		client:           client,
+		passphrase:       cfg.RelayerKeystorePassphrase,
+		pollInterval:     pollInterval,
+		rebroadcastAfter: rebroadcastAfter,
+		maxFeeCap:        new(big.Int).Mul(new(big.Int).SetUint64(maxFeeGwei), big.NewInt(params.GWei)),
+	}
+
+	if cfg.RelayerPrivateKey != "" {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.RelayerPrivateKey, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid RELAYER_PRIVATE_KEY: %w", err)
+		}
+		relayer.platformKey = key
+	}
+
+	return relayer, nil
+}
+
+// PlatformAddress returns the address of the platform signer, if one is configured
+func (r *Relayer) PlatformAddress() (common.Address, bool) {
+	if r.platformKey == nil {
+		return common.Address{}, false
+	}
+	return crypto.PubkeyToAddress(r.platformKey.PublicKey), true
+}
+
+// CreateCustodialWallet generates a key for the user, stores it encrypted and binds its address
+// to the account. Users who already have a wallet bound cannot get a custodial one.
+// The wallet starts without funds, the operator has to fund it before it can pay for gas.
+func (r *Relayer) CreateCustodialWallet(userID uint) (*models.CustodialWallet, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+
+	// The keystores sit next to the data they control, the standard scrypt parameters make a
+	// leaked database expensive to brute force at the cost of a slower decryption per transaction
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Id:         uuid.New(),
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, r.passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return nil, fmt.Errorf("encrypting key: %w", err)
+	}
+
+	wallet := models.CustodialWallet{
+		UserID:   userID,
+		Address:  crypto.PubkeyToAddress(key.PublicKey).Hex(),
+		Keystore: string(keyJSON),
+	}
+
+	if err := r.store.CustodialWallets().Create(&wallet); err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			return nil, ErrCustodialWalletExists
+		}
+		return nil, err
+	}
+
+	return &wallet, nil
+}
+
+// CustodialWallet returns the custodial wallet of the user
+func (r *Relayer) CustodialWallet(userID uint) (*models.CustodialWallet, error) {
+	wallet, err := r.store.CustodialWallets().GetByUser(userID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrNoCustodialWallet
+		}
+		return nil, fmt.Errorf("loading custodial wallet: %w", err)
+	}
+	return wallet, nil
+}
+
+// custodialKey decrypts the custodial key of the user. The wallet must still be the one bound
+// to the account, the on-chain roles are checked against that address.
+func (r *Relayer) custodialKey(userID uint) (*ecdsa.PrivateKey, error) {
+	wallet, err := r.CustodialWallet(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.store.Users().Profile(userID)
+	if err != nil {
+		return nil, fmt.Errorf("loading user: %w", err)
+	}
+	if !strings.EqualFold(user.PublicWalletAddress, wallet.Address) {
+		return nil, ErrCustodialWalletUnbound
+	}
+
+	return r.decryptKey(wallet)
+}
+
+func (r *Relayer) decryptKey(wallet *models.CustodialWallet) (*ecdsa.PrivateKey, error) {
+	key, err := keystore.DecryptKey([]byte(wallet.Keystore), r.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting custodial key: %w", err)
+	}
+	return key.PrivateKey, nil
+}
+
+// signerKey returns the key that signed a relayed transaction, to sign its replacement. The
+// custodial wallet may have been unbound since, the nonce it took still has to be settled.
+func (r *Relayer) signerKey(relayed *models.RelayedTransaction) (*ecdsa.PrivateKey, error) {
+	if address, ok := r.PlatformAddress(); ok && strings.EqualFold(address.Hex(), relayed.Signer) {
+		return r.platformKey, nil
+	}
+
+	wallet, err := r.CustodialWallet(relayed.RequestedBy)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(wallet.Address, relayed.Signer) {
+		return nil, fmt.Errorf("signer %s is not the custodial wallet of user %d", relayed.Signer, relayed.RequestedBy)
+	}
+	return r.decryptKey(wallet)
+}
+
+// SubmitProposal calls submitProposal on the offer with the custodial wallet of the entrepreneur
+func (r *Relayer) SubmitProposal(userID uint, offerAddress string, description string, price *big.Int) (*models.RelayedTransaction, error) {
+	if !common.IsHexAddress(offerAddress) {
+		return nil, ErrInvalidAddress
+	}
+
+	data, err := packOffer("submitProposal", description, price)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.custodialKey(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.send(userID, RelayActionSubmitProposal, key, common.HexToAddress(offerAddress), data)
+}
+
+// ReviewProposal calls reviewProposal on the offer with the custodial wallet of the expert
+func (r *Relayer) ReviewProposal(userID uint, offerAddress string, entrepreneur string, score uint8) (*models.RelayedTransaction, error) {
+	if !common.IsHexAddress(offerAddress) || !common.IsHexAddress(entrepreneur) {
+		return nil, ErrInvalidAddress
+	}
+
+	data, err := packOffer("reviewProposal", common.HexToAddress(entrepreneur), score)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.custodialKey(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.send(userID, RelayActionReviewProposal, key, common.HexToAddress(offerAddress), data)
+}
+
+// CreateOffer calls createOffer on the OfferFactory with the custodial wallet of the tender
+func (r *Relayer) CreateOffer(userID uint, windows OfferWindows) (*models.RelayedTransaction, error) {
+	data, err := packFactory("createOffer",
+		big.NewInt(windows.SubmissionStart.Unix()),
+		big.NewInt(windows.SubmissionEnd.Unix()),
+		big.NewInt(windows.ReviewStart.Unix()),
+		big.NewInt(windows.ReviewEnd.Unix()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := r.custodialKey(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.send(userID, RelayActionCreateOffer, key, r.client.FactoryAddress(), data)
+}
+
+// GrantRole calls grantRole on the OfferFactory with the platform signer, which must hold DEFAULT_ADMIN_ROLE
+func (r *Relayer) GrantRole(requestedBy uint, roleName string, account string) (*models.RelayedTransaction, error) {
+	if r.platformKey == nil {
+		return nil, ErrNoPlatformSigner
+	}
+	if !common.IsHexAddress(account) {
+		return nil, ErrInvalidAddress
+	}
+
+	data, err := packFactory("grantRole", RoleHash(roleName), common.HexToAddress(account))
+	if err != nil {
+		return nil, err
+	}
+
+	return r.send(requestedBy, RelayActionGrantRole, r.platformKey, r.client.FactoryAddress(), data)
+}
+
+// AnchorHash publishes hash on chain as the data of a transaction the platform signer sends to
+// itself, the block timestamp proves the hash existed at that time. requestedBy is 0 for the platform.
+func (r *Relayer) AnchorHash(requestedBy uint, hash common.Hash) (*models.RelayedTransaction, error) {
+	address, ok := r.PlatformAddress()
+	if !ok {
+		return nil, ErrNoPlatformSigner
+	}
+	return r.send(requestedBy, RelayActionAnchor, r.platformKey, address, hash.Bytes())
+}
+
+// Transaction returns a relayed transaction by ID, store.ErrNotFound if it does not exist
+func (r *Relayer) Transaction(id uint) (*models.RelayedTransaction, error) {
+	return r.store.RelayedTransactions().Get(id)
+}
+
+// Transactions returns a page of the transactions relayed for a user, newest first
+func (r *Relayer) Transactions(userID uint, page store.Page) ([]models.RelayedTransaction, int64, error) {
+	return r.store.RelayedTransactions().ListByRequester(userID, page)
+}
+
+func packOffer(method string, args ...interface{}) ([]byte, error) {
+	parsedABI, err := getOfferABI()
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("packing %s: %w", method, err)
+	}
+	return data, nil
+}
+
+func packFactory(method string, args ...interface{}) ([]byte, error) {
+	parsedABI, err := getABI()
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("packing %s: %w", method, err)
+	}
+	return data, nil
+}
+
+// signerLock returns the mutex serializing the transactions of a signer
+func (r *Relayer) signerLock(signer common.Address) *sync.Mutex {
+	lock, _ := r.signerLocks.LoadOrStore(signer, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// send builds, signs, persists and broadcasts a transaction calling to with data.
+// The record is returned even when broadcasting failed, it is then marked failed.
+func (r *Relayer) send(requestedBy uint, action string, key *ecdsa.PrivateKey, to common.Address, data []byte) (*models.RelayedTransaction, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	lock := r.signerLock(from)
+	lock.Lock()
+	defer lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := r.client
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading chain ID: %w", err)
+	}
+
+	// Estimating first surfaces reverts (missing role, closed window...) before anything is signed
+	gasLimit, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &to, Data: data})
+	if err != nil {
+		var contractErr *ContractError
+		if errors.As(DecodeContractError(err), &contractErr) {
+			return nil, fmt.Errorf("%w: %w", ErrTxWouldRevert, contractErr)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrTxWouldRevert, err)
+	}
+	gasLimit += gasLimit * gasLimitBufferPercent / 100
+
+	tipCap, feeCap, err := r.fees(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := r.nextNonce(ctx, client, from)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       gasLimit,
+		To:        &to,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("signing transaction: %w", err)
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encoding transaction: %w", err)
+	}
+
+	relayed := models.RelayedTransaction{
+		RequestedBy: requestedBy,
+		Action:      action,
+		Signer:      from.Hex(),
+		To:          to.Hex(),
+		Nonce:       nonce,
+		GasLimit:    gasLimit,
+		GasTipCap:   tipCap.String(),
+		GasFeeCap:   feeCap.String(),
+		TxHash:      tx.Hash().Hex(),
+		RawTx:       hexutil.Encode(raw),
+		Status:      models.RelayedTxPending,
+		Broadcasts:  1,
+	}
+	// Persisted before broadcasting, so a crash in between leaves a record Run can rebroadcast
+	if err := r.store.RelayedTransactions().Create(&relayed); err != nil {
+		return nil, fmt.Errorf("storing relayed transaction: %w", err)
+	}
+
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		relayed.Status = models.RelayedTxFailed
+		relayed.Error = err.Error()
+		if saveErr := r.store.RelayedTransactions().Save(&relayed); saveErr != nil {
+			log.Printf("Relayer: transaction %s: failed to record broadcast failure: %v", relayed.TxHash, saveErr)
+		}
+		return &relayed, fmt.Errorf("broadcasting transaction: %w", err)
+	}
+
+	log.Printf("Relayer: %s sent by %s as %s (nonce %d)", action, from.Hex(), relayed.TxHash, nonce)
+
+	r.track(&relayed)
+
+	return &relayed, nil
+}
+
+// track hands a broadcast transaction to the transaction watcher
+func (r *Relayer) track(relayed *models.RelayedTransaction) {
+	ref := TxRef{
+		Hash: relayed.TxHash,
+		Kind: relayActionKinds[relayed.Action],
+		From: relayed.Signer,
+		To:   relayed.To,
+	}
+	if relayed.RequestedBy != 0 {
+		ref.UserID = &relayed.RequestedBy
+	}
+	if err := TrackTransaction(r.store.ChainTransactions(), ref); err != nil {
+		log.Printf("Relayer: %v", err)
+	}
+}
+
+// fees returns the EIP-1559 tip and fee caps: the suggested tip over twice the current base fee,
+// which survives several full blocks, bounded by RELAYER_MAX_FEE_GWEI
+func (r *Relayer) fees(ctx context.Context, client Client) (*big.Int, *big.Int, error) {
+	tipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("suggesting gas tip: %w", err)
+	}
+
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading latest header: %w", err)
+	}
+
+	baseFee := big.NewInt(0)
+	if head.BaseFee != nil {
+		baseFee = head.BaseFee
+	}
+	if baseFee.Cmp(r.maxFeeCap) > 0 {
+		return nil, nil, ErrFeeTooHigh
+	}
+
+	feeCap := new(big.Int).Add(new(big.Int).Mul(baseFee, big.NewInt(2)), tipCap)
+	if feeCap.Cmp(r.maxFeeCap) > 0 {
+		feeCap = new(big.Int).Set(r.maxFeeCap)
+	}
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+
+	return tipCap, feeCap, nil
+}
+
+// nextNonce returns the nonce for the next transaction of signer. The node's pending nonce is
+// used unless our own pending transactions are ahead of it, e.g. when the node dropped one.
+func (r *Relayer) nextNonce(ctx context.Context, client Client, signer common.Address) (uint64, error) {
+	nonce, err := client.PendingNonceAt(ctx, signer)
+	if err != nil {
+		return 0, fmt.Errorf("reading pending nonce: %w", err)
+	}
+
+	highest, ok, err := r.store.RelayedTransactions().HighestPendingNonce(signer.Hex())
+	if err != nil {
+		return 0, fmt.Errorf("reading pending relayed nonces: %w", err)
+	}
+
+	if ok && highest+1 > nonce {
+		nonce = highest + 1
+	}
+	return nonce, nil
+}
+
+// Run follows the pending relayed transactions until ctx is cancelled
+func (r *Relayer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Tick(time.Now()); err != nil {
+			log.Printf("Relayer: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick records the receipts of pending relayed transactions and replaces the ones the node
+// has not mined for RELAYER_REBROADCAST_AFTER with higher fees
+func (r *Relayer) Tick(now time.Time) error {
+	pending, err := r.store.RelayedTransactions().ListPending()
+	if err != nil {
+		return fmt.Errorf("loading pending relayed transactions: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	client := r.client
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, relayed := range pending {
+		receipt, err := client.TransactionReceipt(ctx, common.HexToHash(relayed.TxHash))
+		switch {
+		case err == nil:
+			r.recordReceipt(&relayed, receipt, now)
+		case errors.Is(err, ethereum.NotFound):
+			if now.Sub(relayed.UpdatedAt) >= r.rebroadcastAfter {
+				r.rebroadcast(ctx, client, &relayed)
+			}
+		default:
+			log.Printf("Relayer: transaction %s: fetching receipt: %v", relayed.TxHash, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Relayer) recordReceipt(relayed *models.RelayedTransaction, receipt *types.Receipt, now time.Time) {
+	blockNumber := receipt.BlockNumber.Uint64()
+	gasUsed := receipt.GasUsed
+
+	relayed.Status = models.RelayedTxMined
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		relayed.Status = models.RelayedTxReverted
+		relayed.Error = ErrTxReverted.Error()
+	}
+	relayed.BlockNumber = &blockNumber
+	relayed.GasUsed = &gasUsed
+	relayed.MinedAt = &now
+
+	if err := r.store.RelayedTransactions().Save(relayed); err != nil {
+		log.Printf("Relayer: transaction %s: failed to record receipt: %v", relayed.TxHash, err)
+		return
+	}
+	log.Printf("Relayer: transaction %s %s in block %d", relayed.TxHash, relayed.Status, blockNumber)
+}
+
+// rebroadcast replaces a transaction the node has not mined with the same one at higher fees.
+// Both caps are raised by at least feeBumpPercent so the node accepts the replacement, and up to
+// the fees suggested now. Past RELAYER_MAX_FEE_GWEI the stored transaction is resent unchanged.
+func (r *Relayer) rebroadcast(ctx context.Context, client Client, relayed *models.RelayedTransaction) {
+	lock := r.signerLock(common.HexToAddress(relayed.Signer))
+	lock.Lock()
+	defer lock.Unlock()
+
+	tx := new(types.Transaction)
+	raw, err := hexutil.Decode(relayed.RawTx)
+	if err == nil {
+		err = tx.UnmarshalBinary(raw)
+	}
+	if err != nil {
+		log.Printf("Relayer: transaction %s: decoding stored transaction: %v", relayed.TxHash, err)
+		return
+	}
+
+	replaced := *relayed
+	replacement, err := r.replacement(ctx, client, relayed, tx)
+	switch {
+	case err != nil:
+		log.Printf("Relayer: transaction %s: not replaced, resending it: %v", relayed.TxHash, err)
+	default:
+		// The replaced hash stays tracked by the transaction watcher, which records it if it is the one mined
+		log.Printf("Relayer: transaction %s replaced by %s (tip %s, fee cap %s)",
+			relayed.TxHash, replacement.Hash().Hex(), replacement.GasTipCap(), replacement.GasFeeCap())
+		tx = replacement
+		raw, _ = tx.MarshalBinary()
+		relayed.TxHash = tx.Hash().Hex()
+		relayed.RawTx = hexutil.Encode(raw)
+		relayed.GasTipCap = tx.GasTipCap().String()
+		relayed.GasFeeCap = tx.GasFeeCap().String()
+	}
+
+	relayed.Broadcasts++
+	// Saved before sending like in send, a record never points at a transaction older than the one in the pool
+	if err := r.store.RelayedTransactions().Save(relayed); err != nil {
+		log.Printf("Relayer: transaction %s: failed to record rebroadcast: %v", relayed.TxHash, err)
+		return
+	}
+	if tx == replacement {
+		r.track(relayed)
+	}
+
+	if err := client.SendTransaction(ctx, tx); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "already known"):
+			// still in the node's pool, just not mined yet
+		case strings.Contains(err.Error(), "nonce too low"):
+			// either mined since the receipt check, possibly as the replaced transaction, or another
+			// transaction of the signer took the nonce
+			if receipt, receiptErr := client.TransactionReceipt(ctx, tx.Hash()); receiptErr == nil {
+				r.recordReceipt(relayed, receipt, time.Now())
+				return
+			}
+			if receipt, receiptErr := client.TransactionReceipt(ctx, common.HexToHash(replaced.TxHash)); tx == replacement && receiptErr == nil {
+				relayed.TxHash, relayed.RawTx = replaced.TxHash, replaced.RawTx
+				relayed.GasTipCap, relayed.GasFeeCap = replaced.GasTipCap, replaced.GasFeeCap
+				r.recordReceipt(relayed, receipt, time.Now())
+				return
+			}
+			relayed.Status = models.RelayedTxFailed
+			relayed.Error = err.Error()
+			if err := r.store.RelayedTransactions().Save(relayed); err != nil {
+				log.Printf("Relayer: transaction %s: failed to record rebroadcast: %v", relayed.TxHash, err)
+			}
+		default:
+			log.Printf("Relayer: transaction %s: rebroadcast failed: %v", relayed.TxHash, err)
+		}
+	}
+}
+
+// replacement signs tx again with the same nonce and raised tip and fee caps
+func (r *Relayer) replacement(ctx context.Context, client Client, relayed *models.RelayedTransaction, tx *types.Transaction) (*types.Transaction, error) {
+	tipCap, feeCap, err := r.fees(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	tipCap = maxBig(tipCap, bumpFee(tx.GasTipCap()))
+	feeCap = maxBig(feeCap, bumpFee(tx.GasFeeCap()))
+	if feeCap.Cmp(r.maxFeeCap) > 0 {
+		return nil, ErrFeeTooHigh
+	}
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+
+	key, err := r.signerKey(relayed)
+	if err != nil {
+		return nil, err
+	}
+
+	replacement, err := types.SignNewTx(key, types.LatestSignerForChainID(tx.ChainId()), &types.DynamicFeeTx{
+		ChainID:   tx.ChainId(),
+		Nonce:     tx.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("signing replacement: %w", err)
+	}
+	return replacement, nil
+}
+
+// bumpFee raises a fee by feeBumpPercent, rounding up so small fees still grow
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+feeBumpPercent))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
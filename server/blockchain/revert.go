@@ -0,0 +1,136 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorStringSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+	panicSelector       = crypto.Keccak256([]byte("Panic(uint256)"))[:4]
+)
+
+// revertData returns the revert data carried by an eth_call or eth_estimateGas error, nil if there is none
+func revertData(err error) []byte {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil
+	}
+
+	encoded, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil
+	}
+	data, err := hexutil.Decode(encoded)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// DecodeContractError turns a call or gas estimation error carrying revert data into a *ContractError,
+// other errors are returned unchanged
+func DecodeContractError(err error) error {
+	if err == nil {
+		return nil
+	}
+	data := revertData(err)
+	if data == nil {
+		return err
+	}
+	return decodeRevert(data)
+}
+
+// decodeRevert decodes the revert data of a call: a custom error of the Offer or OfferFactory ABI,
+// a require message or a panic code
+func decodeRevert(data []byte) *ContractError {
+	if len(data) < 4 {
+		return newContractError("", nil, "reverted without a reason")
+	}
+
+	switch {
+	case bytes.Equal(data[:4], errorStringSelector):
+		message, err := abi.UnpackRevert(data)
+		if err != nil {
+			return newContractError("", nil, "reverted with an undecodable message")
+		}
+		return newContractError("", nil, message)
+	case bytes.Equal(data[:4], panicSelector):
+		code := new(big.Int).SetBytes(data[4:])
+		return newContractError("Panic", nil, fmt.Sprintf("Panic(0x%x)", code))
+	}
+
+	for _, getABI := range []func() (abi.ABI, error){getOfferABI, getABI} {
+		parsedABI, err := getABI()
+		if err != nil {
+			continue
+		}
+		customErr, err := parsedABI.ErrorByID([4]byte(data[:4]))
+		if err != nil {
+			continue
+		}
+
+		values, err := customErr.Inputs.Unpack(data[4:])
+		if err != nil {
+			return newContractError(customErr.Name, nil, customErr.Name+"(?)")
+		}
+		args := make(map[string]interface{}, len(values))
+		rendered := make([]string, len(values))
+		for i, value := range values {
+			if hash, ok := value.([32]byte); ok {
+				value = common.Hash(hash)
+			}
+			name := customErr.Inputs[i].Name
+			if name == "" {
+				name = fmt.Sprintf("arg%d", i)
+			}
+			args[name] = value
+			rendered[i] = fmt.Sprintf("%s=%v", name, value)
+		}
+		return newContractError(customErr.Name, args, fmt.Sprintf("%s(%s)", customErr.Name, strings.Join(rendered, ", ")))
+	}
+
+	return newContractError("", nil, fmt.Sprintf("unknown error 0x%x", data[:4]))
+}
+
+// replayRevert re-executes a reverted transaction on the state of the block before it was mined to
+// recover its revert data, receipts do not carry it
+func replayRevert(ctx context.Context, client Client, tx *types.Transaction, receipt *types.Receipt) (*ContractError, error) {
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("recovering sender: %w", err)
+	}
+
+	call := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	parent := new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1))
+
+	_, callErr := client.CallContract(ctx, call, parent)
+	if callErr == nil {
+		// The transaction succeeds on the parent state, it depended on another one in its block
+		return newContractError("", nil, "reverted, the failure could not be reproduced"), nil
+	}
+
+	data := revertData(callErr)
+	if data == nil {
+		return newContractError("", nil, callErr.Error()), nil
+	}
+	return decodeRevert(data), nil
+}
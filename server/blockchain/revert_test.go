@@ -0,0 +1,209 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Brondont/trust-api/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// customError returns the revert data of the Offer custom error name raised with args
+func customError(t *testing.T, name string, args ...interface{}) []byte {
+	t.Helper()
+	parsedABI, err := getOfferABI()
+	if err != nil {
+		t.Fatalf("parsing Offer ABI: %v", err)
+	}
+	customErr, ok := parsedABI.Errors[name]
+	if !ok {
+		t.Fatalf("no %s error in the Offer ABI", name)
+	}
+	packed, err := customErr.Inputs.Pack(args...)
+	if err != nil {
+		t.Fatalf("packing %s: %v", name, err)
+	}
+	return append(customErr.ID[:4:4], packed...)
+}
+
+// builtinError returns the revert data of require messages and panics, selector followed by value
+func builtinError(t *testing.T, selector []byte, typ string, value interface{}) []byte {
+	t.Helper()
+	argType, err := abi.NewType(typ, "", nil)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", typ, err)
+	}
+	packed, err := abi.Arguments{{Type: argType}}.Pack(value)
+	if err != nil {
+		t.Fatalf("packing %v: %v", value, err)
+	}
+	return append(append([]byte{}, selector...), packed...)
+}
+
+func TestDecodeRevert(t *testing.T) {
+	expert := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+
+	tests := []struct {
+		name       string
+		data       func(t *testing.T) []byte
+		wantName   string
+		wantCode   string
+		wantKind   error
+		wantReason string
+		wantMsg    string
+	}{
+		{
+			name:       "require message",
+			data:       func(t *testing.T) []byte { return builtinError(t, errorStringSelector, "string", "only the tender") },
+			wantCode:   "contract_reverted",
+			wantKind:   ErrContractReverted,
+			wantReason: "only the tender",
+			wantMsg:    "only the tender",
+		},
+		{
+			name:       "panic",
+			data:       func(t *testing.T) []byte { return builtinError(t, panicSelector, "uint256", big.NewInt(0x11)) },
+			wantName:   "Panic",
+			wantCode:   "contract_reverted",
+			wantKind:   ErrContractReverted,
+			wantReason: "Panic(0x11)",
+			wantMsg:    "Panic(0x11)",
+		},
+		{
+			name:       "submission stage required",
+			data:       func(t *testing.T) []byte { return customError(t, "InvalidStage", uint8(stageSubmission)) },
+			wantName:   "InvalidStage",
+			wantCode:   "submission_period_closed",
+			wantKind:   ErrInvalidStage,
+			wantReason: "InvalidStage(currentStage=1)",
+			wantMsg:    "the proposal submission period is not open",
+		},
+		{
+			name:       "review ended required",
+			data:       func(t *testing.T) []byte { return customError(t, "InvalidStage", uint8(stageAfterReview)) },
+			wantName:   "InvalidStage",
+			wantCode:   "review_period_not_ended",
+			wantKind:   ErrInvalidStage,
+			wantReason: "InvalidStage(currentStage=3)",
+			wantMsg:    "the review period has not ended yet",
+		},
+		{
+			name:       "unknown stage",
+			data:       func(t *testing.T) []byte { return customError(t, "InvalidStage", uint8(9)) },
+			wantName:   "InvalidStage",
+			wantCode:   "invalid_stage",
+			wantKind:   ErrInvalidStage,
+			wantReason: "InvalidStage(currentStage=9)",
+			wantMsg:    ErrInvalidStage.Error(),
+		},
+		{
+			name:       "already scored",
+			data:       func(t *testing.T) []byte { return customError(t, "AlreadyScored", expert) },
+			wantName:   "AlreadyScored",
+			wantCode:   "already_scored",
+			wantKind:   ErrAlreadyScored,
+			wantReason: fmt.Sprintf("AlreadyScored(expert=%s)", expert.Hex()),
+			wantMsg:    ErrAlreadyScored.Error(),
+		},
+		{
+			name:       "unknown selector",
+			data:       func(t *testing.T) []byte { return []byte{0xde, 0xad, 0xbe, 0xef, 0x01} },
+			wantCode:   "contract_reverted",
+			wantKind:   ErrContractReverted,
+			wantReason: "unknown error 0xdeadbeef",
+			wantMsg:    "unknown error 0xdeadbeef",
+		},
+		{
+			name:       "shorter than a selector",
+			data:       func(t *testing.T) []byte { return []byte{0x08, 0xc3} },
+			wantCode:   "contract_reverted",
+			wantKind:   ErrContractReverted,
+			wantReason: "reverted without a reason",
+			wantMsg:    "reverted without a reason",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeRevert(tt.data(t))
+
+			if got.Name != tt.wantName || got.Code != tt.wantCode || got.Reason != tt.wantReason {
+				t.Errorf("got %q, code %q, reason %q, want %q, %q, %q", got.Name, got.Code, got.Reason, tt.wantName, tt.wantCode, tt.wantReason)
+			}
+			if got.Error() != tt.wantMsg {
+				t.Errorf("got message %q, want %q", got.Error(), tt.wantMsg)
+			}
+			if !errors.Is(got, tt.wantKind) {
+				t.Errorf("%v is not %v", got, tt.wantKind)
+			}
+		})
+	}
+}
+
+// dataError is a JSON-RPC error carrying revert data, as eth_call and eth_estimateGas return them
+type dataError struct{ data interface{} }
+
+func (e dataError) Error() string          { return "execution reverted" }
+func (e dataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeContractError(t *testing.T) {
+	plain := errors.New("connection refused")
+	if got := DecodeContractError(plain); got != plain {
+		t.Errorf("got %v for an error without revert data, want it unchanged", got)
+	}
+
+	reverted := DecodeContractError(dataError{hexutil.Encode(customError(t, "OfferClosed"))})
+	if !errors.Is(reverted, ErrOfferClosed) {
+		t.Errorf("got %v, want the OfferClosed error", reverted)
+	}
+}
+
+// The code of a decoded revert reaches API clients through utils.WriteError, even wrapped
+func TestContractErrorResponse(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+		wantMsg  string
+	}{
+		{
+			name:     "contract error",
+			err:      decodeRevert(customError(t, "InvalidStage", uint8(stageReview))),
+			wantCode: "review_period_closed",
+			wantMsg:  "the review period is not open",
+		},
+		{
+			name:     "wrapped contract error",
+			err:      fmt.Errorf("estimating gas: %w", decodeRevert(customError(t, "ProposalAlreadySubmitted"))),
+			wantCode: "proposal_already_submitted",
+			wantMsg:  "estimating gas: " + ErrProposalAlreadySubmitted.Error(),
+		},
+		{
+			name:    "other error",
+			err:     errors.New("node unreachable"),
+			wantMsg: "node unreachable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			utils.WriteError(w, http.StatusUnprocessableEntity, tt.err)
+
+			var response utils.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decoding response %q: %v", w.Body.String(), err)
+			}
+			if response.Error.Code != tt.wantCode || response.Error.Msg != tt.wantMsg {
+				t.Errorf("got code %q and message %q, want %q and %q", response.Error.Code, response.Error.Msg, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OfferScheduler moves offers through their lifecycle and persists each transition.
+// The status is computed from the offer time windows and reconciled with
+// Offer.getStage() on chain, the later of the two wins.
+type OfferScheduler struct {
+	offers   store.OfferRepository
+	client   Client
+	interval time.Duration
+}
+
+// NewOfferScheduler builds a scheduler of the offers in s, reading their stage through client
+func NewOfferScheduler(s store.Store, client Client, cfg config.Config) (*OfferScheduler, error) {
+	interval, err := time.ParseDuration(cfg.OfferSchedulerInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OFFER_SCHEDULER_INTERVAL: %w", err)
+	}
+
+	return &OfferScheduler{offers: s.Offers(), client: client, interval: interval}, nil
+}
+
+// Run applies transitions until ctx is cancelled
+func (s *OfferScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(time.Now()); err != nil {
+			log.Printf("Offer scheduler: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick recomputes the status of every offer that is not closed yet
+func (s *OfferScheduler) Tick(now time.Time) error {
+	offers, err := s.offers.ListOpen()
+	if err != nil {
+		return fmt.Errorf("loading offers: %w", err)
+	}
+
+	for _, offer := range offers {
+		next := offer.TimeStatus(now)
+
+		if common.IsHexAddress(offer.ContractAddress) {
+			chainStatus, err := GetOfferStatus(s.client, offer.ContractAddress)
+			if err != nil {
+				log.Printf("Offer scheduler: offer %d: reading chain stage: %v", offer.ID, err)
+			} else {
+				next = models.LaterOfferStatus(next, chainStatus)
+			}
+		}
+
+		next = models.LaterOfferStatus(offer.Status, next)
+		if next == offer.Status {
+			continue
+		}
+
+		// Only update if nobody else moved the offer in the meantime
+		moved, err := s.offers.MoveStatus(offer.ID, offer.Status, next)
+		if err != nil {
+			log.Printf("Offer scheduler: offer %d: failed to persist %s: %v", offer.ID, next, err)
+			continue
+		}
+		if moved {
+			log.Printf("Offer scheduler: offer %d moved from %s to %s", offer.ID, offer.Status, next)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain/contracts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// simulatedBalance is the genesis balance of every funded account
+var simulatedBalance = new(big.Int).Mul(big.NewInt(1000), big.NewInt(params.Ether))
+
+// SimulatedClient is a Client backed by an in-process chain with the OfferFactory deployed
+// from the bundled bytecode, so chain code can run offline in go test.
+// Blocks are only mined on Commit, which the Transact helpers do for every transaction.
+type SimulatedClient struct {
+	*contractClient
+	Backend *simulated.Backend
+	Admin   *ecdsa.PrivateKey // holds DEFAULT_ADMIN_ROLE on the factory
+	chainID *big.Int
+}
+
+var _ Client = (*SimulatedClient)(nil)
+
+// NewSimulatedClient starts a simulated chain with the accounts funded and deploys
+// an OfferFactory administered by a freshly generated key
+func NewSimulatedClient(accounts ...common.Address) (*SimulatedClient, error) {
+	admin, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("generating admin key: %w", err)
+	}
+
+	alloc := types.GenesisAlloc{crypto.PubkeyToAddress(admin.PublicKey): {Balance: simulatedBalance}}
+	for _, account := range accounts {
+		alloc[account] = types.Account{Balance: simulatedBalance}
+	}
+
+	backend := simulated.NewBackend(alloc)
+	client := backend.Client()
+
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		backend.Close()
+		return nil, fmt.Errorf("reading chain ID: %w", err)
+	}
+
+	sim := &SimulatedClient{Backend: backend, Admin: admin, chainID: chainID}
+	if err := sim.deployFactory(client); err != nil {
+		backend.Close()
+		return nil, err
+	}
+
+	return sim, nil
+}
+
+// deployFactory deploys the OfferFactory from the bundled bytecode
+func (s *SimulatedClient) deployFactory(client simulated.Client) error {
+	opts, err := s.TransactOpts(s.Admin)
+	if err != nil {
+		return err
+	}
+
+	address, tx, _, err := contracts.DeployOfferFactory(opts, client, opts.From)
+	if err != nil {
+		return fmt.Errorf("deploying OfferFactory: %w", err)
+	}
+	if _, err := s.mine(tx); err != nil {
+		return fmt.Errorf("deploying OfferFactory: %w", err)
+	}
+
+	s.contractClient, err = newContractClient(client, address)
+	return err
+}
+
+// Close stops the simulated chain
+func (s *SimulatedClient) Close() error {
+	return s.Backend.Close()
+}
+
+// TransactOpts returns options signing with key on the simulated chain
+func (s *SimulatedClient) TransactOpts(key *ecdsa.PrivateKey) (*bind.TransactOpts, error) {
+	return bind.NewKeyedTransactorWithChainID(key, s.chainID)
+}
+
+// AdjustTime moves the clock of the next block forward, e.g. into an offer submission window
+func (s *SimulatedClient) AdjustTime(d time.Duration) error {
+	if err := s.Backend.AdjustTime(d); err != nil {
+		return err
+	}
+	s.Backend.Commit()
+	return nil
+}
+
+// TransactFactory sends a transaction calling method on the OfferFactory and mines it
+func (s *SimulatedClient) TransactFactory(key *ecdsa.PrivateKey, method string, args ...interface{}) (*types.Receipt, error) {
+	raw := &contracts.OfferFactoryRaw{Contract: s.factoryBinding}
+	return s.transact(key, method, raw.Transact, args...)
+}
+
+// TransactOffer sends a transaction calling method on an Offer contract and mines it
+func (s *SimulatedClient) TransactOffer(key *ecdsa.PrivateKey, offer common.Address, method string, args ...interface{}) (*types.Receipt, error) {
+	binding, err := s.Offer(offer)
+	if err != nil {
+		return nil, err
+	}
+	raw := &contracts.OfferRaw{Contract: binding}
+	return s.transact(key, method, raw.Transact, args...)
+}
+
+// GrantRole grants the on-chain role of a DB role name to account, signed by the admin
+func (s *SimulatedClient) GrantRole(roleName string, account common.Address) (*types.Receipt, error) {
+	return s.TransactFactory(s.Admin, "grantRole", RoleHash(roleName), account)
+}
+
+// CreateOffer creates an offer from the tender wallet and returns its address
+func (s *SimulatedClient) CreateOffer(tender *ecdsa.PrivateKey, windows OfferWindows) (common.Address, *types.Receipt, error) {
+	receipt, err := s.TransactFactory(tender, "createOffer",
+		big.NewInt(windows.SubmissionStart.Unix()),
+		big.NewInt(windows.SubmissionEnd.Unix()),
+		big.NewInt(windows.ReviewStart.Unix()),
+		big.NewInt(windows.ReviewEnd.Unix()),
+	)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	for _, l := range receipt.Logs {
+		if l.Address != s.factory {
+			continue
+		}
+		if event, err := s.factoryBinding.ParseOfferCreated(*l); err == nil {
+			return event.OfferAddress, receipt, nil
+		}
+	}
+
+	return common.Address{}, nil, ErrEventNotFound
+}
+
+// transact signs and sends a transaction through a binding and mines it
+func (s *SimulatedClient) transact(key *ecdsa.PrivateKey, method string,
+	send func(*bind.TransactOpts, string, ...interface{}) (*types.Transaction, error), args ...interface{}) (*types.Receipt, error) {
+	opts, err := s.TransactOpts(key)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := send(opts, method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sending %s: %w", method, err)
+	}
+
+	return s.mine(tx)
+}
+
+// mine commits a block with tx and returns its receipt, reverted transactions are returned with ErrTxReverted
+func (s *SimulatedClient) mine(tx *types.Transaction) (*types.Receipt, error) {
+	s.Backend.Commit()
+
+	receipt, err := s.Backend.Client().TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("fetching receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return receipt, ErrTxReverted
+	}
+	return receipt, nil
+}
@@ -0,0 +1,235 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxRef describes a transaction to track and what it relates to
+type TxRef struct {
+	Hash       string
+	Kind       string
+	From       string
+	To         string
+	UserID     *uint
+	EntityType string
+	EntityID   *uint
+}
+
+// NewChainTransaction returns the record tracking the transaction of ref, still pending
+func NewChainTransaction(ref TxRef) (*models.ChainTransaction, error) {
+	if !isTxHash(ref.Hash) {
+		return nil, ErrInvalidTxHash
+	}
+
+	return &models.ChainTransaction{
+		Hash:       common.HexToHash(ref.Hash).Hex(),
+		Kind:       ref.Kind,
+		From:       ref.From,
+		To:         ref.To,
+		UserID:     ref.UserID,
+		EntityType: ref.EntityType,
+		EntityID:   ref.EntityID,
+		Status:     models.ChainTxPending,
+	}, nil
+}
+
+// TrackTransaction starts tracking a transaction. Tracking a known hash again fills in what the
+// new reference knows, e.g. the proposal a relayed transaction created once it is recorded.
+func TrackTransaction(transactions store.ChainTransactionRepository, ref TxRef) error {
+	tracked, err := NewChainTransaction(ref)
+	if err != nil {
+		return err
+	}
+
+	if err := transactions.Track(tracked); err != nil {
+		return fmt.Errorf("tracking transaction %s: %w", ref.Hash, err)
+	}
+	return nil
+}
+
+// TxWatcher follows tracked transactions: it records their receipt, counts confirmations until
+// TX_WATCHER_CONFIRMATIONS, notices reorgs and marks as dropped what the node forgot about.
+type TxWatcher struct {
+	transactions  store.ChainTransactionRepository
+	client        Client
+	interval      time.Duration
+	confirmations uint64
+	dropAfter     time.Duration
+}
+
+// NewTxWatcher builds a watcher of the transactions tracked in s, following them through client
+func NewTxWatcher(s store.Store, client Client, cfg config.Config) (*TxWatcher, error) {
+	interval, err := time.ParseDuration(cfg.TxWatcherInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TX_WATCHER_INTERVAL: %w", err)
+	}
+
+	confirmations, err := strconv.ParseUint(cfg.TxWatcherConfirmations, 10, 64)
+	if err != nil || confirmations == 0 {
+		return nil, fmt.Errorf("invalid TX_WATCHER_CONFIRMATIONS %q", cfg.TxWatcherConfirmations)
+	}
+
+	dropAfter, err := time.ParseDuration(cfg.TxWatcherDropAfter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TX_WATCHER_DROP_AFTER: %w", err)
+	}
+
+	return &TxWatcher{
+		transactions:  s.ChainTransactions(),
+		client:        client,
+		interval:      interval,
+		confirmations: confirmations,
+		dropAfter:     dropAfter,
+	}, nil
+}
+
+// Run updates tracked transactions until ctx is cancelled
+func (tw *TxWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(tw.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := tw.Tick(time.Now()); err != nil {
+			log.Printf("Transaction watcher: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick updates every pending transaction and every mined one short of the confirmation target
+func (tw *TxWatcher) Tick(now time.Time) error {
+	tracked, err := tw.transactions.ListUnconfirmed(tw.confirmations)
+	if err != nil {
+		return fmt.Errorf("loading tracked transactions: %w", err)
+	}
+	if len(tracked) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	head, err := tw.client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("reading block number: %w", err)
+	}
+
+	for i := range tracked {
+		if err := tw.update(ctx, &tracked[i], head, now); err != nil {
+			log.Printf("Transaction watcher: %s: %v", tracked[i].Hash, err)
+		}
+	}
+
+	return nil
+}
+
+// update refreshes a single tracked transaction and saves it if anything changed
+func (tw *TxWatcher) update(ctx context.Context, tracked *models.ChainTransaction, head uint64, now time.Time) error {
+	client := tw.client
+	hash := common.HexToHash(tracked.Hash)
+	before := *tracked
+
+	var tx *types.Transaction
+	if tracked.From == "" || tracked.To == "" {
+		found, _, err := client.TransactionByHash(ctx, hash)
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return fmt.Errorf("fetching transaction: %w", err)
+		}
+		if found != nil {
+			tx = found
+			fillAddresses(tracked, tx)
+		}
+	}
+
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	switch {
+	case errors.Is(err, ethereum.NotFound):
+		if tracked.Status != models.ChainTxPending {
+			// Mined in a block that was reorged out, it may be mined again
+			log.Printf("Transaction watcher: %s left the chain (block %d reorged out)", tracked.Hash, *tracked.BlockNumber)
+			tracked.Status = models.ChainTxPending
+			tracked.BlockNumber = nil
+			tracked.BlockHash = ""
+			tracked.Confirmations = 0
+			tracked.MinedAt = nil
+		} else if now.Sub(tracked.CreatedAt) >= tw.dropAfter {
+			if _, _, err := client.TransactionByHash(ctx, hash); errors.Is(err, ethereum.NotFound) {
+				tracked.Status = models.ChainTxDropped
+			}
+		}
+	case err != nil:
+		return fmt.Errorf("fetching receipt: %w", err)
+	default:
+		blockNumber := receipt.BlockNumber.Uint64()
+		if tracked.BlockHash != receipt.BlockHash.Hex() {
+			tracked.BlockNumber = &blockNumber
+			tracked.BlockHash = receipt.BlockHash.Hex()
+			tracked.MinedAt = &now
+		}
+		if head >= blockNumber {
+			tracked.Confirmations = head - blockNumber + 1
+		}
+
+		tracked.Status = models.ChainTxMined
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			tracked.Status = models.ChainTxReverted
+			if tracked.RevertReason == "" {
+				tw.decodeRevert(ctx, client, tracked, tx, receipt)
+			}
+		}
+	}
+
+	if *tracked == before {
+		return nil
+	}
+	return tw.transactions.Save(tracked)
+}
+
+// decodeRevert replays a reverted transaction to record why it failed
+func (tw *TxWatcher) decodeRevert(ctx context.Context, client Client, tracked *models.ChainTransaction, tx *types.Transaction, receipt *types.Receipt) {
+	if tx == nil {
+		found, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+		if err != nil {
+			log.Printf("Transaction watcher: %s: fetching reverted transaction: %v", tracked.Hash, err)
+			return
+		}
+		tx = found
+	}
+
+	reverted, err := replayRevert(ctx, client, tx, receipt)
+	if err != nil {
+		log.Printf("Transaction watcher: %s: decoding revert: %v", tracked.Hash, err)
+		return
+	}
+	tracked.RevertError = reverted.Name
+	tracked.RevertReason = reverted.Reason
+}
+
+// fillAddresses sets the sender and recipient of a tracked transaction from the transaction itself
+func fillAddresses(tracked *models.ChainTransaction, tx *types.Transaction) {
+	if tracked.From == "" {
+		if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+			tracked.From = from.Hex()
+		}
+	}
+	if tracked.To == "" && tx.To() != nil {
+		tracked.To = tx.To().Hex()
+	}
+}
@@ -1,3 +1,419 @@
 package blockchain
 
-// TODO work on validation off chain
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain/contracts"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Errors returned while validating a transaction against the chain
+var (
+	ErrInvalidTxHash  = errors.New("invalid transaction hash")
+	ErrTxNotFound     = errors.New("transaction not found on chain")
+	ErrTxPending      = errors.New("transaction is still pending, try again once it is mined")
+	ErrTxReverted     = errors.New("transaction was reverted on chain")
+	ErrEventNotFound  = errors.New("transaction did not emit the expected event")
+	ErrEventMismatch  = errors.New("transaction event does not match the submitted data")
+	ErrInvalidAddress = errors.New("invalid contract address")
+
+	ErrOfferNotFromFactory  = errors.New("contract was not created by the offer factory for your wallet")
+	ErrOfferWindowsMismatch = errors.New("offer time windows do not match the contract")
+)
+
+// ProposalSubmittedEvent is the decoded Offer.ProposalSubmitted log
+type ProposalSubmittedEvent struct {
+	Entrepreneur common.Address
+	Description  string
+	Price        *big.Int
+	BlockNumber  uint64
+	BlockHash    common.Hash
+}
+
+// ProposalReviewedEvent is the decoded Offer.ProposalReviewed log
+type ProposalReviewedEvent struct {
+	Entrepreneur common.Address
+	Expert       common.Address
+	Score        uint8
+	BlockNumber  uint64
+	BlockHash    common.Hash
+}
+
+// WinnerDeclaredEvent is the decoded Offer.WinnerDeclared log
+type WinnerDeclaredEvent struct {
+	Entrepreneur common.Address
+	TotalScore   *big.Int
+	Price        *big.Int
+	BlockNumber  uint64
+}
+
+// OfferClosedEvent is the decoded Offer.OfferClosedEvent log, the event has no arguments
+type OfferClosedEvent struct {
+	BlockNumber uint64
+	BlockHash   common.Hash
+}
+
+// getReceipt fetches a mined receipt for the given hash, distinguishing
+// pending, unknown and reverted transactions.
+func getReceipt(client Client, txHash string) (*types.Receipt, error) {
+	if !isTxHash(txHash) {
+		return nil, ErrInvalidTxHash
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	hash := common.HexToHash(txHash)
+	receipt, err := client.TransactionReceipt(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("fetching transaction receipt: %w", err)
+		}
+
+		// No receipt yet: the tx is either waiting in the mempool or unknown
+		_, isPending, txErr := client.TransactionByHash(ctx, hash)
+		if txErr == nil && isPending {
+			return nil, ErrTxPending
+		}
+		return nil, ErrTxNotFound
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, revertedError(ctx, client, receipt)
+	}
+
+	return receipt, nil
+}
+
+// revertedError returns ErrTxReverted wrapping why the transaction reverted when it can be replayed
+func revertedError(ctx context.Context, client Client, receipt *types.Receipt) error {
+	tx, _, err := client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return ErrTxReverted
+	}
+	reverted, err := replayRevert(ctx, client, tx, receipt)
+	if err != nil {
+		return ErrTxReverted
+	}
+	return fmt.Errorf("%w: %w", ErrTxReverted, reverted)
+}
+
+// findEventLogs returns the logs of the receipt emitted by contract for the named event
+func findEventLogs(receipt *types.Receipt, contract common.Address, parsedABI abi.ABI, eventName string) ([]*types.Log, error) {
+	event, ok := parsedABI.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found in ABI", eventName)
+	}
+
+	var logs []*types.Log
+	for _, l := range receipt.Logs {
+		if l.Address != contract || len(l.Topics) == 0 || l.Topics[0] != event.ID {
+			continue
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+// VerifyProposalSubmitted checks that txHash was mined successfully and emitted
+// ProposalSubmitted from the offer contract for the given entrepreneur wallet.
+func VerifyProposalSubmitted(client Client, txHash string, offerAddress string, entrepreneurAddr string) (*ProposalSubmittedEvent, error) {
+	if !common.IsHexAddress(offerAddress) {
+		return nil, ErrInvalidAddress
+	}
+
+	parsedABI, err := getOfferABI()
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := getReceipt(client, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := common.HexToAddress(offerAddress)
+	logs, err := findEventLogs(receipt, contract, parsedABI, "ProposalSubmitted")
+	if err != nil {
+		return nil, err
+	}
+
+	filterer := offerFilterer(contract)
+	entrepreneur := common.HexToAddress(entrepreneurAddr)
+	for _, l := range logs {
+		submitted, err := filterer.ParseProposalSubmitted(*l)
+		if err != nil {
+			return nil, fmt.Errorf("decoding ProposalSubmitted log: %w", err)
+		}
+		if submitted.Entrepreneur != entrepreneur {
+			continue
+		}
+
+		return &ProposalSubmittedEvent{
+			Entrepreneur: submitted.Entrepreneur,
+			Description:  submitted.Description,
+			Price:        submitted.Price,
+			BlockNumber:  l.BlockNumber,
+			BlockHash:    l.BlockHash,
+		}, nil
+	}
+
+	return nil, ErrEventNotFound
+}
+
+// VerifyProposalReviewed checks that txHash was mined successfully and emitted
+// ProposalReviewed from the offer contract for the given entrepreneur and expert wallets.
+func VerifyProposalReviewed(client Client, txHash string, offerAddress string, entrepreneurAddr string, expertAddr string) (*ProposalReviewedEvent, error) {
+	if !common.IsHexAddress(offerAddress) {
+		return nil, ErrInvalidAddress
+	}
+
+	parsedABI, err := getOfferABI()
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := getReceipt(client, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := common.HexToAddress(offerAddress)
+	logs, err := findEventLogs(receipt, contract, parsedABI, "ProposalReviewed")
+	if err != nil {
+		return nil, err
+	}
+
+	filterer := offerFilterer(contract)
+	entrepreneur := common.HexToAddress(entrepreneurAddr)
+	expert := common.HexToAddress(expertAddr)
+	for _, l := range logs {
+		reviewed, err := filterer.ParseProposalReviewed(*l)
+		if err != nil {
+			return nil, fmt.Errorf("decoding ProposalReviewed log: %w", err)
+		}
+		if reviewed.Entrepreneur != entrepreneur || reviewed.Expert != expert {
+			continue
+		}
+
+		return &ProposalReviewedEvent{
+			Entrepreneur: reviewed.Entrepreneur,
+			Expert:       reviewed.Expert,
+			Score:        reviewed.Score,
+			BlockNumber:  l.BlockNumber,
+			BlockHash:    l.BlockHash,
+		}, nil
+	}
+
+	return nil, ErrEventNotFound
+}
+
+// VerifyWinnerDeclared checks that txHash was mined successfully and emitted
+// WinnerDeclared from the offer contract.
+func VerifyWinnerDeclared(client Client, txHash string, offerAddress string) (*WinnerDeclaredEvent, error) {
+	receipt, err := getOfferReceipt(client, txHash, offerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := decodeWinnerDeclared(receipt, common.HexToAddress(offerAddress))
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, ErrEventNotFound
+	}
+	return event, nil
+}
+
+// VerifyOfferClosed checks that txHash was mined successfully and emitted
+// OfferClosedEvent from the offer contract. closeOffer() declares the winner
+// itself when the review period is over, check it with VerifyWinnerDeclared
+// on the same transaction.
+func VerifyOfferClosed(client Client, txHash string, offerAddress string) (*OfferClosedEvent, error) {
+	parsedABI, err := getOfferABI()
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := getOfferReceipt(client, txHash, offerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := findEventLogs(receipt, common.HexToAddress(offerAddress), parsedABI, "OfferClosedEvent")
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) == 0 {
+		return nil, ErrEventNotFound
+	}
+
+	return &OfferClosedEvent{BlockNumber: logs[0].BlockNumber, BlockHash: logs[0].BlockHash}, nil
+}
+
+// getOfferReceipt validates the offer address and fetches the receipt of txHash
+func getOfferReceipt(client Client, txHash string, offerAddress string) (*types.Receipt, error) {
+	if !common.IsHexAddress(offerAddress) {
+		return nil, ErrInvalidAddress
+	}
+	return getReceipt(client, txHash)
+}
+
+// decodeWinnerDeclared returns the WinnerDeclared event of the receipt, or nil if there is none
+func decodeWinnerDeclared(receipt *types.Receipt, contract common.Address) (*WinnerDeclaredEvent, error) {
+	parsedABI, err := getOfferABI()
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := findEventLogs(receipt, contract, parsedABI, "WinnerDeclared")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range logs {
+		declared, err := offerFilterer(contract).ParseWinnerDeclared(*l)
+		if err != nil {
+			return nil, fmt.Errorf("decoding WinnerDeclared log: %w", err)
+		}
+
+		return &WinnerDeclaredEvent{
+			Entrepreneur: declared.Entrepreneur,
+			TotalScore:   declared.TotalScore,
+			Price:        declared.Price,
+			BlockNumber:  l.BlockNumber,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// offerFilterer returns the typed event decoder of an Offer contract, it only parses logs
+// already fetched so it needs no backend
+func offerFilterer(contract common.Address) *contracts.OfferFilterer {
+	filterer, _ := contracts.NewOfferFilterer(contract, nil)
+	return filterer
+}
+
+// VerifyOfferContract checks that offerAddress was deployed by the OfferFactory for
+// the tender wallet and that its on-chain time windows equal the submitted ones.
+func VerifyOfferContract(client Client, offerAddress string, tenderAddr string, submitted OfferWindows) error {
+	if !common.IsHexAddress(offerAddress) || !common.IsHexAddress(tenderAddr) {
+		return ErrInvalidAddress
+	}
+
+	parsedABI, err := getABI()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Both OfferCreated arguments are indexed so the node can filter on them directly
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{client.FactoryAddress()},
+		Topics: [][]common.Hash{
+			{parsedABI.Events["OfferCreated"].ID},
+			{common.BytesToHash(common.HexToAddress(offerAddress).Bytes())},
+			{common.BytesToHash(common.HexToAddress(tenderAddr).Bytes())},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("filtering OfferCreated logs: %w", err)
+	}
+	if len(logs) == 0 {
+		return ErrOfferNotFromFactory
+	}
+
+	onChain, err := GetOfferWindows(client, offerAddress)
+	if err != nil {
+		return err
+	}
+
+	fields := []struct {
+		name              string
+		submitted, actual time.Time
+	}{
+		{"proposalSubmissionStart", submitted.SubmissionStart, onChain.SubmissionStart},
+		{"proposalSubmissionEnd", submitted.SubmissionEnd, onChain.SubmissionEnd},
+		{"proposalReviewStart", submitted.ReviewStart, onChain.ReviewStart},
+		{"proposalReviewEnd", submitted.ReviewEnd, onChain.ReviewEnd},
+	}
+	for _, f := range fields {
+		// The contract stores unix seconds
+		if f.submitted.Unix() != f.actual.Unix() {
+			return fmt.Errorf("%w: %s is %s on chain", ErrOfferWindowsMismatch, f.name, f.actual.UTC().Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// VerifyRoleGranted checks that txHash emitted RoleGranted on the factory for roleName and account
+func VerifyRoleGranted(client Client, txHash string, roleName string, account string) error {
+	return verifyRoleEvent(client, txHash, "RoleGranted", roleName, account)
+}
+
+// VerifyRoleRevoked checks that txHash emitted RoleRevoked on the factory for roleName and account
+func VerifyRoleRevoked(client Client, txHash string, roleName string, account string) error {
+	return verifyRoleEvent(client, txHash, "RoleRevoked", roleName, account)
+}
+
+// verifyRoleEvent looks for an AccessControl role event matching the role hash and target account
+func verifyRoleEvent(client Client, txHash string, eventName string, roleName string, account string) error {
+	if !common.IsHexAddress(account) {
+		return ErrInvalidAddress
+	}
+
+	parsedABI, err := getABI()
+	if err != nil {
+		return err
+	}
+
+	receipt, err := getReceipt(client, txHash)
+	if err != nil {
+		return err
+	}
+
+	logs, err := findEventLogs(receipt, client.FactoryAddress(), parsedABI, eventName)
+	if err != nil {
+		return err
+	}
+
+	// Topics: event ID, role, account, sender
+	role := RoleHash(roleName)
+	target := common.HexToAddress(account)
+	for _, l := range logs {
+		if len(l.Topics) < 3 {
+			continue
+		}
+		if l.Topics[1] == role && common.BytesToAddress(l.Topics[2].Bytes()) == target {
+			return nil
+		}
+	}
+
+	return ErrEventNotFound
+}
+
+// isTxHash reports whether s is a 0x-prefixed 32 byte hex string
+func isTxHash(s string) bool {
+	if !strings.HasPrefix(s, "0x") || len(s) != 66 {
+		return false
+	}
+	for _, c := range s[2:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,207 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testOffer is an offer deployed on a simulated chain with a tender, two entrepreneurs and an expert
+type testOffer struct {
+	sim          *SimulatedClient
+	address      common.Address
+	windows      OfferWindows
+	tender       *ecdsa.PrivateKey
+	entrepreneur *ecdsa.PrivateKey
+	competitor   *ecdsa.PrivateKey
+	expert       *ecdsa.PrivateKey
+}
+
+func newTestOffer(t *testing.T) *testOffer {
+	t.Helper()
+
+	keys := make([]*ecdsa.PrivateKey, 4)
+	accounts := make([]common.Address, len(keys))
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("generating key: %v", err)
+		}
+		keys[i], accounts[i] = key, crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	sim, err := NewSimulatedClient(accounts...)
+	if err != nil {
+		t.Fatalf("starting simulated chain: %v", err)
+	}
+	t.Cleanup(func() { sim.Close() })
+
+	for i, role := range []string{"tender", "entrepreneur", "entrepreneur", "expert"} {
+		if _, err := sim.GrantRole(role, accounts[i]); err != nil {
+			t.Fatalf("granting %s: %v", role, err)
+		}
+	}
+
+	// The contract compares block timestamps, the windows follow the simulated clock
+	now := time.Now()
+	windows := OfferWindows{
+		SubmissionStart: now.Add(time.Minute),
+		SubmissionEnd:   now.Add(time.Hour),
+		ReviewStart:     now.Add(2 * time.Hour),
+		ReviewEnd:       now.Add(3 * time.Hour),
+	}
+	address, _, err := sim.CreateOffer(keys[0], windows)
+	if err != nil {
+		t.Fatalf("creating offer: %v", err)
+	}
+
+	return &testOffer{
+		sim:          sim,
+		address:      address,
+		windows:      windows,
+		tender:       keys[0],
+		entrepreneur: keys[1],
+		competitor:   keys[2],
+		expert:       keys[3],
+	}
+}
+
+// advanceTo moves the simulated clock past at
+func (o *testOffer) advanceTo(t *testing.T, at time.Time) {
+	t.Helper()
+	if err := o.sim.AdjustTime(time.Until(at) + time.Minute); err != nil {
+		t.Fatalf("adjusting time: %v", err)
+	}
+}
+
+func (o *testOffer) transact(t *testing.T, key *ecdsa.PrivateKey, method string, args ...interface{}) common.Hash {
+	t.Helper()
+	receipt, err := o.sim.TransactOffer(key, o.address, method, args...)
+	if err != nil {
+		t.Fatalf("%s: %v", method, err)
+	}
+	return receipt.TxHash
+}
+
+func walletOf(key *ecdsa.PrivateKey) string {
+	return crypto.PubkeyToAddress(key.PublicKey).Hex()
+}
+
+func TestVerifyProposalSubmitted(t *testing.T) {
+	offer := newTestOffer(t)
+	offer.advanceTo(t, offer.windows.SubmissionStart)
+
+	price := big.NewInt(125000)
+	submitted := offer.transact(t, offer.entrepreneur, "submitProposal", "road works", price)
+	competing := offer.transact(t, offer.competitor, "submitProposal", "bridge works", big.NewInt(99000))
+
+	tests := []struct {
+		name         string
+		txHash       string
+		offer        string
+		entrepreneur string
+		wantErr      error
+	}{
+		{"matching submission", submitted.Hex(), offer.address.Hex(), walletOf(offer.entrepreneur), nil},
+		{"hash of another entrepreneur", competing.Hex(), offer.address.Hex(), walletOf(offer.entrepreneur), ErrEventNotFound},
+		{"other offer", submitted.Hex(), common.HexToAddress("0x1234").Hex(), walletOf(offer.entrepreneur), ErrEventNotFound},
+		{"unknown transaction", common.HexToHash("0xdead").Hex(), offer.address.Hex(), walletOf(offer.entrepreneur), ErrTxNotFound},
+		{"malformed hash", "0x1234", offer.address.Hex(), walletOf(offer.entrepreneur), ErrInvalidTxHash},
+		{"malformed offer address", submitted.Hex(), "not-an-address", walletOf(offer.entrepreneur), ErrInvalidAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := VerifyProposalSubmitted(offer.sim, tt.txHash, tt.offer, tt.entrepreneur)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if event.Entrepreneur.Hex() != tt.entrepreneur {
+				t.Errorf("entrepreneur %s, want %s", event.Entrepreneur.Hex(), tt.entrepreneur)
+			}
+			if event.Description != "road works" || event.Price.Cmp(price) != 0 {
+				t.Errorf("got proposal %q at %s, want %q at %s", event.Description, event.Price, "road works", price)
+			}
+			if event.BlockNumber == 0 {
+				t.Error("block number is not set")
+			}
+		})
+	}
+}
+
+func TestVerifyOfferClosed(t *testing.T) {
+	t.Run("closed before the review ended", func(t *testing.T) {
+		offer := newTestOffer(t)
+		offer.advanceTo(t, offer.windows.SubmissionStart)
+		submitted := offer.transact(t, offer.entrepreneur, "submitProposal", "road works", big.NewInt(125000))
+		closed := offer.transact(t, offer.tender, "closeOffer")
+
+		event, err := VerifyOfferClosed(offer.sim, closed.Hex(), offer.address.Hex())
+		if err != nil {
+			t.Fatalf("verifying close: %v", err)
+		}
+		if event.BlockNumber == 0 {
+			t.Error("block number is not set")
+		}
+		if _, err := VerifyWinnerDeclared(offer.sim, closed.Hex(), offer.address.Hex()); !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("verifying the winner of an early close: got %v, want %v", err, ErrEventNotFound)
+		}
+
+		state, err := readOfferState(offer.sim, offer.address)
+		if err != nil {
+			t.Fatalf("reading offer state: %v", err)
+		}
+		if state.Stage != models.OfferStageClosed || state.Status != models.OfferStatusClosed {
+			t.Errorf("got stage %d (%s), want %d (%s)", state.Stage, state.Status, models.OfferStageClosed, models.OfferStatusClosed)
+		}
+
+		if _, err := VerifyOfferClosed(offer.sim, submitted.Hex(), offer.address.Hex()); !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("verifying a submission as a close: got %v, want %v", err, ErrEventNotFound)
+		}
+	})
+
+	t.Run("closed after the review declares the winner", func(t *testing.T) {
+		offer := newTestOffer(t)
+		offer.advanceTo(t, offer.windows.SubmissionStart)
+		offer.transact(t, offer.entrepreneur, "submitProposal", "road works", big.NewInt(125000))
+		offer.transact(t, offer.competitor, "submitProposal", "bridge works", big.NewInt(99000))
+
+		offer.advanceTo(t, offer.windows.ReviewStart)
+		offer.transact(t, offer.expert, "reviewProposal", crypto.PubkeyToAddress(offer.entrepreneur.PublicKey), uint8(9))
+		offer.transact(t, offer.expert, "reviewProposal", crypto.PubkeyToAddress(offer.competitor.PublicKey), uint8(6))
+
+		offer.advanceTo(t, offer.windows.ReviewEnd)
+		closed := offer.transact(t, offer.tender, "closeOffer")
+
+		if _, err := VerifyOfferClosed(offer.sim, closed.Hex(), offer.address.Hex()); err != nil {
+			t.Fatalf("verifying close: %v", err)
+		}
+		winner, err := VerifyWinnerDeclared(offer.sim, closed.Hex(), offer.address.Hex())
+		if err != nil {
+			t.Fatalf("closing after the review declared no winner: %v", err)
+		}
+		if winner.Entrepreneur.Hex() != walletOf(offer.entrepreneur) || winner.TotalScore.Int64() != 9 {
+			t.Errorf("got winner %s with %s, want %s with 9", winner.Entrepreneur.Hex(), winner.TotalScore, walletOf(offer.entrepreneur))
+		}
+	})
+
+	t.Run("other offer", func(t *testing.T) {
+		offer := newTestOffer(t)
+		closed := offer.transact(t, offer.tender, "closeOffer")
+
+		other := common.HexToAddress("0x1234").Hex()
+		if _, err := VerifyOfferClosed(offer.sim, closed.Hex(), other); !errors.Is(err, ErrEventNotFound) {
+			t.Errorf("got %v, want %v", err, ErrEventNotFound)
+		}
+	})
+}
@@ -1,41 +1,70 @@
 package api
 
 import (
-	"database/sql"
 	"net/http"
 
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/internal/handlers"
 	"github.com/Brondont/trust-api/internal/routes"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
 type APIServer struct {
-	addr string
-	db   *sql.DB
+	addr    string
+	handler *handlers.Handler
 }
 
-func NewAPIServer(addr string, db *sql.DB) *APIServer {
+// NewAPIServer wires the production dependencies of the handlers around the store and the chain
+// client, relayer is nil when the relayer is disabled
+func NewAPIServer(addr string, st store.Store, client blockchain.Client, reconciler *blockchain.RoleReconciler, relayer *blockchain.Relayer) (*APIServer, error) {
+	chain, err := blockchain.NewNodeChain(st, client, config.Envs, reconciler)
+	if err != nil {
+		return nil, err
+	}
+
 	return &APIServer{
 		addr: addr,
-		db:   db,
+		handler: handlers.NewHandler(
+			st,
+			chain,
+			utils.NewSMTPMailer(config.Envs),
+			utils.SystemClock{},
+			config.Envs,
+			relayer,
+		),
+	}, nil
+}
+
+// NewAPIServerWithHandler builds a server around already wired dependencies
+func NewAPIServerWithHandler(addr string, handler *handlers.Handler) *APIServer {
+	return &APIServer{
+		addr:    addr,
+		handler: handler,
 	}
 }
 
-func (s *APIServer) Run() error {
+// Router returns the HTTP handler serving the API
+func (s *APIServer) Router() http.Handler {
 	router := mux.NewRouter()
 	subrouter := router.PathPrefix("/api/v1").Subrouter()
 
-	routes.SetupRoutes(subrouter)
+	routes.SetupRoutes(subrouter, s.handler)
 	routes.SetupStaticRoutes(router)
 
-	corsHandler := cors.New(cors.Options{
+	return cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"Content-Disposition"}, // download file names of /documents
 		AllowCredentials: true,
 		Debug:            true,
 	}).Handler(router)
+}
 
-	return http.ListenAndServe(s.addr, corsHandler)
-
+func (s *APIServer) Run() error {
+	return http.ListenAndServe(s.addr, s.Router())
 }
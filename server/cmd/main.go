@@ -1,24 +1,150 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
+	"strconv"
 
 	"github.com/Brondont/trust-api/blockchain"
 	"github.com/Brondont/trust-api/cmd/api"
+	"github.com/Brondont/trust-api/config"
 	"github.com/Brondont/trust-api/db"
+	"github.com/Brondont/trust-api/internal/audit"
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	fmt.Println("starting backend server")
 	fmt.Println("starting connection to database")
 	db.ConnectDB()
 
-	log.Println("Synchronizing database with blockchain…")
-	blockchain.ChainSyncDB()
+	log.Println("Running migrations")
+	if _, err := db.MigrateUp(db.DB.DB); err != nil {
+		log.Fatalf("Migration failed: %v", err)
+	}
+
+	st := store.New(db.DB.DB)
+
+	client, err := blockchain.NewEthClient(config.Envs.BlockchainRPCURL, common.HexToAddress(config.Envs.OfferFactoryAddress))
+	if err != nil {
+		log.Fatalf("Failed to configure blockchain client: %v", err)
+	}
+
+	reconciler, err := blockchain.NewRoleReconciler(st, client, config.Envs, audit.Seal)
+	if err != nil {
+		log.Fatalf("Failed to configure role reconciler: %v", err)
+	}
+	log.Printf("Reconciling roles with the blockchain (policy %s)", reconciler.Policy())
+	go reconciler.Run(context.Background())
+
+	indexer, err := blockchain.NewIndexer(st, client, config.Envs, audit.Seal)
+	if err != nil {
+		log.Fatalf("Failed to configure blockchain indexer: %v", err)
+	}
+	go indexer.Run(context.Background())
+
+	scheduler, err := blockchain.NewOfferScheduler(st, client, config.Envs)
+	if err != nil {
+		log.Fatalf("Failed to configure offer scheduler: %v", err)
+	}
+	go scheduler.Run(context.Background())
+
+	tokenCleaner, err := auth.NewTokenCleaner(st, config.Envs)
+	if err != nil {
+		log.Fatalf("Failed to configure token cleanup: %v", err)
+	}
+	go tokenCleaner.Run(context.Background())
+
+	txWatcher, err := blockchain.NewTxWatcher(st, client, config.Envs)
+	if err != nil {
+		log.Fatalf("Failed to configure transaction watcher: %v", err)
+	}
+	go txWatcher.Run(context.Background())
+
+	var relayer *blockchain.Relayer
+	if config.Envs.RelayerEnabled == "true" {
+		relayer, err = blockchain.NewRelayer(st, client, config.Envs)
+		if err != nil {
+			log.Fatalf("Failed to configure transaction relayer: %v", err)
+		}
+		if address, ok := relayer.PlatformAddress(); ok {
+			log.Printf("Relaying transactions, platform signer %s", address.Hex())
+		} else {
+			log.Println("Relaying transactions, no platform signer configured")
+		}
+		go relayer.Run(context.Background())
+	}
 
-	server := api.NewAPIServer(":3080", nil)
+	if config.Envs.AuditCheckpointPrivateKey != "" {
+		checkpointer, err := audit.NewCheckpointer(st.AuditEvents(), relayer, config.Envs)
+		if err != nil {
+			log.Fatalf("Failed to configure audit checkpoints: %v", err)
+		}
+		go checkpointer.Run(context.Background())
+	} else {
+		log.Println("Audit checkpoints are disabled, AUDIT_CHECKPOINT_PRIVATE_KEY is not set")
+	}
+
+	server, err := api.NewAPIServer(":3080", st, client, reconciler, relayer)
+	if err != nil {
+		log.Fatalf("Failed to configure API server: %v", err)
+	}
 	if err := server.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// runMigrate handles `migrate up`, `migrate down [steps]` and `migrate status`
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		log.Fatal("usage: migrate up | down [steps] | status")
+	}
+
+	db.ConnectDB()
+
+	switch args[0] {
+	case "up":
+		count, err := db.MigrateUp(db.DB.DB)
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Printf("%d migration(s) applied", count)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				log.Fatalf("invalid number of steps %q", args[1])
+			}
+			steps = n
+		}
+		count, err := db.MigrateDown(db.DB.DB, steps)
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+		log.Printf("%d migration(s) reverted", count)
+	case "status":
+		states, err := db.MigrationStatus(db.DB.DB)
+		if err != nil {
+			log.Fatalf("Reading migration status failed: %v", err)
+		}
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", state.Version, state.Name, applied)
+		}
+	default:
+		log.Fatalf("unknown migrate command %q", args[0])
+	}
+}
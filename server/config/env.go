@@ -16,6 +16,41 @@ type Config struct {
 	SMTPPort            string
 	FrontendURL         string
 	BlockchainRPCURL    string
+
+	IndexerPollInterval  string
+	IndexerConfirmations string
+	IndexerStartBlock    string
+
+	OfferSchedulerInterval string
+	OfferChainCacheTTL     string
+
+	RoleReconcileInterval string
+	RoleReconcilePolicy   string
+
+	AccessTokenTTL       string
+	RefreshTokenTTL      string
+	TokenCleanupInterval string
+
+	SIWEDomain            string
+	ChainID               string
+	SIWENonceRateInterval string
+	SIWENonceRateBurst    string
+
+	TxWatcherInterval      string
+	TxWatcherConfirmations string
+	TxWatcherDropAfter     string
+
+	RelayerEnabled            string
+	RelayerKeystorePassphrase string
+	RelayerPrivateKey         string
+	RelayerPollInterval       string
+	RelayerRebroadcastAfter   string
+	RelayerMaxFeeGwei         string
+
+	AuditCheckpointInterval   string
+	AuditCheckpointFile       string
+	AuditCheckpointPrivateKey string
+	AuditCheckpointAnchor     string
 }
 
 var Envs = initConfig()
@@ -33,6 +68,41 @@ func initConfig() Config {
 		SMTPHost:            getEnv("SMTPHost", "smtp.ethereal.email"),
 		SMTPPort:            getEnv("SMTPPort", "587"),
 		FrontendURL:         getEnv("FrontendURL", "http://localhost:3000"),
+
+		IndexerPollInterval:  getEnv("INDEXER_POLL_INTERVAL", "15s"),
+		IndexerConfirmations: getEnv("INDEXER_CONFIRMATIONS", "2"),
+		IndexerStartBlock:    getEnv("INDEXER_START_BLOCK", "0"),
+
+		OfferSchedulerInterval: getEnv("OFFER_SCHEDULER_INTERVAL", "1m"),
+		OfferChainCacheTTL:     getEnv("OFFER_CHAIN_CACHE_TTL", "15s"),
+
+		RoleReconcileInterval: getEnv("ROLE_RECONCILE_INTERVAL", "10m"),
+		RoleReconcilePolicy:   getEnv("ROLE_RECONCILE_POLICY", "report-only"),
+
+		AccessTokenTTL:       getEnv("ACCESS_TOKEN_TTL", "15m"),
+		RefreshTokenTTL:      getEnv("REFRESH_TOKEN_TTL", "720h"),
+		TokenCleanupInterval: getEnv("TOKEN_CLEANUP_INTERVAL", "1h"),
+
+		SIWEDomain:            getEnv("SIWE_DOMAIN", "localhost:3000"),
+		ChainID:               getEnv("CHAIN_ID", "31337"),
+		SIWENonceRateInterval: getEnv("SIWE_NONCE_RATE_INTERVAL", "6s"),
+		SIWENonceRateBurst:    getEnv("SIWE_NONCE_RATE_BURST", "10"),
+
+		TxWatcherInterval:      getEnv("TX_WATCHER_INTERVAL", "15s"),
+		TxWatcherConfirmations: getEnv("TX_WATCHER_CONFIRMATIONS", "12"),
+		TxWatcherDropAfter:     getEnv("TX_WATCHER_DROP_AFTER", "30m"),
+
+		RelayerEnabled:            getEnv("RELAYER_ENABLED", "false"),
+		RelayerKeystorePassphrase: getEnv("RELAYER_KEYSTORE_PASSPHRASE", ""),
+		RelayerPrivateKey:         getEnv("RELAYER_PRIVATE_KEY", ""),
+		RelayerPollInterval:       getEnv("RELAYER_POLL_INTERVAL", "15s"),
+		RelayerRebroadcastAfter:   getEnv("RELAYER_REBROADCAST_AFTER", "2m"),
+		RelayerMaxFeeGwei:         getEnv("RELAYER_MAX_FEE_GWEI", "500"),
+
+		AuditCheckpointInterval:   getEnv("AUDIT_CHECKPOINT_INTERVAL", "1h"),
+		AuditCheckpointFile:       getEnv("AUDIT_CHECKPOINT_FILE", "./audit/checkpoints.jsonl"),
+		AuditCheckpointPrivateKey: getEnv("AUDIT_CHECKPOINT_PRIVATE_KEY", ""),
+		AuditCheckpointAnchor:     getEnv("AUDIT_CHECKPOINT_ANCHOR", "false"),
 	}
 }
 
@@ -32,24 +32,13 @@ func ConnectDB() {
 
 	log.Println("Server connected to database")
 
-	log.Println("Running migrations")
-
-	if err := db.AutoMigrate(
-		&models.Role{},
-		&models.User{},
-
-		&models.Sector{},
-		&models.Qualification{},
-
-		&models.UserQualification{},
-
-		&models.Document{},
-
-		&models.Offer{},
-		&models.Proposal{},
-		&models.ExpertEvaluation{},
-	); err != nil {
-		log.Fatalf("Migration failed: %v", err)
+	// user_roles carries the grant tx hash, so it needs a custom join model.
+	// The schema itself is managed by the versioned migrations in migrations/
+	if err := db.SetupJoinTable(&models.User{}, "Roles", &models.UserRole{}); err != nil {
+		log.Fatalf("Join table setup failed: %v", err)
+	}
+	if err := db.SetupJoinTable(&models.Role{}, "Users", &models.UserRole{}); err != nil {
+		log.Fatalf("Join table setup failed: %v", err)
 	}
 
 	DB = DBInstance{
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS expert_evaluations;
+DROP TABLE IF EXISTS proposals;
+DROP TABLE IF EXISTS offers;
+DROP TABLE IF EXISTS documents;
+DROP TABLE IF EXISTS user_qualifications;
+DROP TABLE IF EXISTS qualifications;
+DROP TABLE IF EXISTS sectors;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,151 @@
+-- Baseline schema, matching what AutoMigrate created before versioned migrations.
+-- Every statement is idempotent so databases created by AutoMigrate adopt it as is.
+
+CREATE TABLE IF NOT EXISTS roles (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(50) NOT NULL,
+    role_tx_hash varchar(66),
+    CONSTRAINT uni_roles_name UNIQUE (name)
+);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_roles_role_tx_hash ON roles (role_tx_hash);
+
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    first_name varchar(100),
+    last_name varchar(100),
+    email varchar(100) NOT NULL,
+    password text NOT NULL,
+    phone_number varchar(100),
+    public_wallet_address varchar(42),
+    is_active boolean DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_public_wallet_address ON users (public_wallet_address);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id)
+);
+
+CREATE TABLE IF NOT EXISTS sectors (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    code varchar(50) NOT NULL,
+    description text
+);
+CREATE INDEX IF NOT EXISTS idx_sectors_deleted_at ON sectors (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sectors_code ON sectors (code);
+
+CREATE TABLE IF NOT EXISTS qualifications (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    sector_id bigint NOT NULL,
+    level varchar(50),
+    CONSTRAINT fk_qualifications_sector FOREIGN KEY (sector_id) REFERENCES sectors (id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_qualifications_deleted_at ON qualifications (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_qualifications_sector_id ON qualifications (sector_id);
+CREATE INDEX IF NOT EXISTS idx_qualifications_level ON qualifications (level);
+
+CREATE TABLE IF NOT EXISTS user_qualifications (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint,
+    qualification_id bigint,
+    CONSTRAINT fk_user_qualifications_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_qualifications_qualification FOREIGN KEY (qualification_id) REFERENCES qualifications (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_user_qualifications_deleted_at ON user_qualifications (deleted_at);
+
+CREATE TABLE IF NOT EXISTS documents (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    document_type varchar(50) NOT NULL,
+    document_path text NOT NULL,
+    documentable_id bigint,
+    documentable_type text
+);
+CREATE INDEX IF NOT EXISTS idx_documents_deleted_at ON documents (deleted_at);
+
+CREATE TABLE IF NOT EXISTS offers (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    tender_number varchar(100) NOT NULL,
+    contract_address varchar(42) NOT NULL,
+    proposal_start timestamptz NOT NULL,
+    proposal_end timestamptz NOT NULL,
+    review_start timestamptz NOT NULL,
+    review_end timestamptz NOT NULL,
+    min_qualification varchar(100),
+    status varchar(50) DEFAULT 'Open',
+    created_by bigint NOT NULL,
+    sector_id bigint NOT NULL,
+    CONSTRAINT fk_offers_creator FOREIGN KEY (created_by) REFERENCES users (id) ON DELETE RESTRICT,
+    CONSTRAINT fk_offers_sector FOREIGN KEY (sector_id) REFERENCES sectors (id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_offers_deleted_at ON offers (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_tender_number ON offers (tender_number);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_offers_contract_address ON offers (contract_address);
+CREATE INDEX IF NOT EXISTS idx_offers_status ON offers (status);
+CREATE INDEX IF NOT EXISTS idx_offers_created_by ON offers (created_by);
+CREATE INDEX IF NOT EXISTS idx_offers_sector_id ON offers (sector_id);
+
+CREATE TABLE IF NOT EXISTS proposals (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    contract_id bigint NOT NULL,
+    proposer_id bigint NOT NULL,
+    details text,
+    status varchar(50) DEFAULT 'pending',
+    submitted_at timestamptz NOT NULL,
+    proposal_tx_hash varchar(66),
+    CONSTRAINT fk_proposals_contract FOREIGN KEY (contract_id) REFERENCES offers (id) ON DELETE CASCADE,
+    CONSTRAINT fk_proposals_proposer FOREIGN KEY (proposer_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_proposals_deleted_at ON proposals (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_proposals_contract_id ON proposals (contract_id);
+CREATE INDEX IF NOT EXISTS idx_proposals_proposer_id ON proposals (proposer_id);
+CREATE INDEX IF NOT EXISTS idx_proposals_status ON proposals (status);
+CREATE INDEX IF NOT EXISTS idx_proposals_submitted_at ON proposals (submitted_at);
+CREATE INDEX IF NOT EXISTS idx_proposals_proposal_tx_hash ON proposals (proposal_tx_hash);
+
+CREATE TABLE IF NOT EXISTS expert_evaluations (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    proposal_id bigint NOT NULL,
+    expert_id bigint NOT NULL,
+    score decimal NOT NULL,
+    comment text,
+    review_tx_hash varchar(66),
+    CONSTRAINT fk_expert_evaluations_proposal FOREIGN KEY (proposal_id) REFERENCES proposals (id) ON DELETE CASCADE,
+    CONSTRAINT fk_expert_evaluations_expert FOREIGN KEY (expert_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_expert_evaluations_deleted_at ON expert_evaluations (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_expert_evaluations_proposal_id ON expert_evaluations (proposal_id);
+CREATE INDEX IF NOT EXISTS idx_expert_evaluations_expert_id ON expert_evaluations (expert_id);
+CREATE INDEX IF NOT EXISTS idx_expert_evaluations_review_tx_hash ON expert_evaluations (review_tx_hash);
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS chain_cursors;
+
+UPDATE offers SET status = 'Open' WHERE status NOT IN ('WinnerDeclared', 'Closed');
+ALTER TABLE offers ALTER COLUMN status SET DEFAULT 'Open';
+
+DROP INDEX IF EXISTS idx_offers_winning_proposal_id;
+ALTER TABLE offers DROP COLUMN IF EXISTS close_tx_hash;
+ALTER TABLE offers DROP COLUMN IF EXISTS winner_tx_hash;
+ALTER TABLE offers DROP COLUMN IF EXISTS winning_proposal_id;
+
+DROP INDEX IF EXISTS idx_evaluation_proposal_expert;
+
+DROP INDEX IF EXISTS idx_user_roles_role_tx_hash;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS created_at;
+ALTER TABLE user_roles DROP COLUMN IF EXISTS role_tx_hash;
+
+ALTER TABLE proposals DROP COLUMN IF EXISTS price;
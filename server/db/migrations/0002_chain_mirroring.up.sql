@@ -0,0 +1,33 @@
+-- Columns and tables added for on-chain verification, the indexer and the offer lifecycle.
+
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS price numeric(78, 0);
+
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS role_tx_hash varchar(66);
+ALTER TABLE user_roles ADD COLUMN IF NOT EXISTS created_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_user_roles_role_tx_hash ON user_roles (role_tx_hash);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_evaluation_proposal_expert ON expert_evaluations (proposal_id, expert_id);
+
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS winning_proposal_id bigint;
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS winner_tx_hash varchar(66);
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS close_tx_hash varchar(66);
+CREATE INDEX IF NOT EXISTS idx_offers_winning_proposal_id ON offers (winning_proposal_id);
+
+-- 'Open' predates the lifecycle, the scheduler moves these offers to their real status
+ALTER TABLE offers ALTER COLUMN status SET DEFAULT 'NotStarted';
+UPDATE offers SET status = 'NotStarted' WHERE status = 'Open';
+
+CREATE TABLE IF NOT EXISTS chain_cursors (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    contract_address varchar(42) NOT NULL,
+    contract_type varchar(50) NOT NULL,
+    next_block bigint NOT NULL DEFAULT 0,
+    last_block_hash varchar(66),
+    finished boolean DEFAULT false
+);
+CREATE INDEX IF NOT EXISTS idx_chain_cursors_deleted_at ON chain_cursors (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chain_cursors_contract_address ON chain_cursors (contract_address);
+CREATE INDEX IF NOT EXISTS idx_chain_cursors_contract_type ON chain_cursors (contract_type);
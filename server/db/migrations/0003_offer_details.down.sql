@@ -0,0 +1,5 @@
+ALTER TABLE offers DROP COLUMN IF EXISTS location;
+ALTER TABLE offers DROP COLUMN IF EXISTS currency;
+ALTER TABLE offers DROP COLUMN IF EXISTS budget;
+ALTER TABLE offers DROP COLUMN IF EXISTS description;
+ALTER TABLE offers DROP COLUMN IF EXISTS title;
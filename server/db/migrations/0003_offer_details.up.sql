@@ -0,0 +1,7 @@
+-- Offer fields written by the tender API but missing from the original schema.
+
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS title varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS description text;
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS budget numeric(20, 2);
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS currency varchar(10);
+ALTER TABLE offers ADD COLUMN IF NOT EXISTS location varchar(255);
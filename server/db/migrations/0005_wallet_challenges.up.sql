@@ -0,0 +1,19 @@
+-- Nonces signed by wallets for Sign-In With Ethereum and wallet binding.
+
+CREATE TABLE IF NOT EXISTS wallet_challenges (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    nonce varchar(64) NOT NULL,
+    address varchar(42) NOT NULL,
+    purpose varchar(20) NOT NULL,
+    user_id bigint,
+    expires_at timestamptz NOT NULL,
+    used_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_wallet_challenges_deleted_at ON wallet_challenges (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_challenges_nonce ON wallet_challenges (nonce);
+CREATE INDEX IF NOT EXISTS idx_wallet_challenges_address ON wallet_challenges (address);
+CREATE INDEX IF NOT EXISTS idx_wallet_challenges_user_id ON wallet_challenges (user_id);
+CREATE INDEX IF NOT EXISTS idx_wallet_challenges_expires_at ON wallet_challenges (expires_at);
@@ -0,0 +1,20 @@
+-- Admin-approved wallet rotation and unbinding.
+
+CREATE TABLE IF NOT EXISTS wallet_change_requests (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    action varchar(10) NOT NULL,
+    old_address varchar(42) NOT NULL,
+    new_address varchar(42),
+    reason text,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    reviewed_by bigint,
+    reviewed_at timestamptz,
+    review_note text
+);
+CREATE INDEX IF NOT EXISTS idx_wallet_change_requests_deleted_at ON wallet_change_requests (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_wallet_change_requests_user_id ON wallet_change_requests (user_id);
+CREATE INDEX IF NOT EXISTS idx_wallet_change_requests_status ON wallet_change_requests (status);
@@ -0,0 +1,15 @@
+-- Role changes applied by the role reconciler.
+
+CREATE TABLE IF NOT EXISTS role_audit_logs (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    role_name varchar(50) NOT NULL,
+    action varchar(20) NOT NULL,
+    source varchar(50) NOT NULL,
+    policy varchar(30),
+    reason text,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_role_audit_logs_user_id ON role_audit_logs (user_id);
+CREATE INDEX IF NOT EXISTS idx_role_audit_logs_created_at ON role_audit_logs (created_at);
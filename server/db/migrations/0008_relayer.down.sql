@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS relayed_transactions;
+DROP TABLE IF EXISTS custodial_wallets;
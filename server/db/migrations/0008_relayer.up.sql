@@ -0,0 +1,42 @@
+-- Custodial wallets and the transactions the relayer submits for them.
+
+CREATE TABLE IF NOT EXISTS custodial_wallets (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id bigint NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    address varchar(42) NOT NULL,
+    keystore text NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_custodial_wallets_deleted_at ON custodial_wallets (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custodial_wallets_user_id ON custodial_wallets (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custodial_wallets_address ON custodial_wallets (address);
+
+CREATE TABLE IF NOT EXISTS relayed_transactions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    requested_by bigint NOT NULL,
+    action varchar(30) NOT NULL,
+    signer varchar(42) NOT NULL,
+    "to" varchar(42) NOT NULL,
+    nonce bigint NOT NULL,
+    gas_limit bigint NOT NULL,
+    gas_tip_cap numeric(78,0),
+    gas_fee_cap numeric(78,0),
+    tx_hash varchar(66) NOT NULL,
+    raw_tx text NOT NULL,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    error text,
+    broadcasts bigint NOT NULL DEFAULT 0,
+    block_number bigint,
+    gas_used bigint,
+    mined_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_relayed_transactions_deleted_at ON relayed_transactions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_relayed_transactions_requested_by ON relayed_transactions (requested_by);
+CREATE INDEX IF NOT EXISTS idx_relayed_transactions_signer_nonce ON relayed_transactions (signer, nonce);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_relayed_transactions_tx_hash ON relayed_transactions (tx_hash);
+CREATE INDEX IF NOT EXISTS idx_relayed_transactions_status ON relayed_transactions (status);
@@ -0,0 +1,56 @@
+-- Transactions of the platform contracts and their status, followed by the transaction watcher.
+
+CREATE TABLE IF NOT EXISTS chain_transactions (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    hash varchar(66) NOT NULL,
+    kind varchar(30) NOT NULL,
+    "from" varchar(42),
+    "to" varchar(42),
+    user_id bigint,
+    entity_type varchar(30),
+    entity_id bigint,
+    status varchar(20) NOT NULL DEFAULT 'pending',
+    block_number bigint,
+    block_hash varchar(66),
+    confirmations bigint NOT NULL DEFAULT 0,
+    revert_error varchar(100),
+    revert_reason text,
+    mined_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_chain_transactions_deleted_at ON chain_transactions (deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chain_transactions_hash ON chain_transactions (hash);
+CREATE INDEX IF NOT EXISTS idx_chain_transactions_kind ON chain_transactions (kind);
+CREATE INDEX IF NOT EXISTS idx_chain_transactions_from ON chain_transactions ("from");
+CREATE INDEX IF NOT EXISTS idx_chain_transactions_user_id ON chain_transactions (user_id);
+CREATE INDEX IF NOT EXISTS idx_chain_transactions_entity ON chain_transactions (entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_chain_transactions_status ON chain_transactions (status);
+
+-- Backfill the transactions already recorded on their entities, the watcher fills in the rest.
+-- Hashes are stored lowercase, as TrackTransaction normalizes them.
+INSERT INTO chain_transactions (created_at, updated_at, hash, kind, user_id, entity_type, entity_id)
+SELECT created_at, created_at, LOWER(proposal_tx_hash), 'proposal_submitted', proposer_id, 'Proposal', id
+FROM proposals WHERE proposal_tx_hash <> '' AND deleted_at IS NULL
+ON CONFLICT (hash) DO NOTHING;
+
+INSERT INTO chain_transactions (created_at, updated_at, hash, kind, user_id, entity_type, entity_id)
+SELECT created_at, created_at, LOWER(review_tx_hash), 'proposal_reviewed', expert_id, 'Evaluation', id
+FROM expert_evaluations WHERE review_tx_hash <> '' AND deleted_at IS NULL
+ON CONFLICT (hash) DO NOTHING;
+
+INSERT INTO chain_transactions (created_at, updated_at, hash, kind, entity_type, entity_id)
+SELECT updated_at, updated_at, LOWER(winner_tx_hash), 'winner_declared', 'Offer', id
+FROM offers WHERE winner_tx_hash <> '' AND deleted_at IS NULL
+ON CONFLICT (hash) DO NOTHING;
+
+INSERT INTO chain_transactions (created_at, updated_at, hash, kind, entity_type, entity_id)
+SELECT updated_at, updated_at, LOWER(close_tx_hash), 'offer_closed', 'Offer', id
+FROM offers WHERE close_tx_hash <> '' AND deleted_at IS NULL
+ON CONFLICT (hash) DO NOTHING;
+
+INSERT INTO chain_transactions (created_at, updated_at, hash, kind, entity_type, entity_id)
+SELECT created_at, created_at, LOWER(role_tx_hash), 'role_granted', 'User', user_id
+FROM user_roles WHERE role_tx_hash <> ''
+ON CONFLICT (hash) DO NOTHING;
@@ -0,0 +1,34 @@
+-- Append-only audit trail of admin and tender actions.
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id bigserial PRIMARY KEY,
+    actor_id bigint,
+    action varchar(50) NOT NULL,
+    method varchar(10) NOT NULL,
+    path text NOT NULL,
+    status bigint NOT NULL,
+    target_type varchar(30),
+    target_id bigint,
+    before jsonb,
+    after jsonb,
+    ip varchar(45),
+    user_agent text,
+    tx_hash varchar(66),
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
+
+-- Rows can only be appended, an edited or removed event would defeat the trail
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
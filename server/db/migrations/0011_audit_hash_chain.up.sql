@@ -0,0 +1,5 @@
+-- Hash chain of the audit trail, events recorded before it stay unsealed.
+
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS prev_hash varchar(64);
+ALTER TABLE audit_events ADD COLUMN IF NOT EXISTS hash varchar(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_events_hash ON audit_events (hash);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS roles_protect_built_in ON roles;
+DROP FUNCTION IF EXISTS roles_protect_built_in();
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+ALTER TABLE roles DROP COLUMN IF EXISTS built_in;
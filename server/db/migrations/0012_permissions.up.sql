@@ -0,0 +1,64 @@
+-- Permissions granted by roles. Routes are guarded by permissions so renaming a role
+-- no longer changes who can reach them, and the built-in roles cannot be renamed or deleted.
+
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS built_in boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    id bigserial PRIMARY KEY,
+    name varchar(50) NOT NULL,
+    description text,
+    CONSTRAINT uni_permissions_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id bigint NOT NULL,
+    permission_id bigint NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('user:manage', 'Create, edit, delete and view users, their roles, sessions and wallet changes'),
+    ('role:manage', 'Create, edit and delete roles, reconcile and grant them on chain'),
+    ('audit:read', 'Read, export and verify the audit trail'),
+    ('transaction:read', 'Read the chain and relayed transactions of every user'),
+    ('offer:create', 'Create offers'),
+    ('offer:manage', 'Declare the winner of and close own offers'),
+    ('proposal:submit', 'Submit proposals to offers'),
+    ('evaluation:submit', 'Review proposals and submit evaluations')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO roles (created_at, updated_at, name, built_in) VALUES
+    (now(), now(), 'admin', true),
+    (now(), now(), 'tender', true),
+    (now(), now(), 'entrepreneur', true),
+    (now(), now(), 'expert', true)
+ON CONFLICT (name) DO UPDATE SET built_in = true, deleted_at = NULL;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON
+    r.name = 'admin'
+    OR (r.name = 'tender' AND p.name IN ('offer:create', 'offer:manage'))
+    OR (r.name = 'entrepreneur' AND p.name = 'proposal:submit')
+    OR (r.name = 'expert' AND p.name = 'evaluation:submit')
+WHERE r.built_in
+ON CONFLICT DO NOTHING;
+
+-- Built-in roles map to the contract roles by name, a rename would orphan their on-chain grants
+CREATE OR REPLACE FUNCTION roles_protect_built_in() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        RAISE EXCEPTION 'built-in role % cannot be deleted', OLD.name;
+    END IF;
+    IF NEW.name <> OLD.name OR NOT NEW.built_in OR NEW.deleted_at IS NOT NULL THEN
+        RAISE EXCEPTION 'built-in role % cannot be renamed or deleted', OLD.name;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS roles_protect_built_in ON roles;
+CREATE TRIGGER roles_protect_built_in
+    BEFORE UPDATE OR DELETE ON roles
+    FOR EACH ROW WHEN (OLD.built_in) EXECUTE FUNCTION roles_protect_built_in();
@@ -0,0 +1,21 @@
+-- Experts assigned by the tender to review the proposals of an offer.
+-- Experts who already reviewed a proposal are assigned to its offer so their evaluations stay valid.
+
+CREATE TABLE IF NOT EXISTS offer_experts (
+    offer_id bigint NOT NULL,
+    expert_id bigint NOT NULL,
+    assigned_by bigint NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (offer_id, expert_id),
+    CONSTRAINT fk_offer_experts_offer FOREIGN KEY (offer_id) REFERENCES offers (id) ON DELETE CASCADE,
+    CONSTRAINT fk_offer_experts_expert FOREIGN KEY (expert_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_offer_experts_expert_id ON offer_experts (expert_id);
+
+INSERT INTO offer_experts (offer_id, expert_id, assigned_by, created_at)
+SELECT DISTINCT p.contract_id, e.expert_id, o.created_by, now()
+FROM expert_evaluations e
+JOIN proposals p ON p.id = e.proposal_id
+JOIN offers o ON o.id = p.contract_id
+WHERE e.deleted_at IS NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_evaluation_proposal_expert;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_evaluation_proposal_expert ON expert_evaluations (proposal_id, expert_id);
+
+DROP INDEX IF EXISTS idx_expert_evaluations_block_number;
+ALTER TABLE expert_evaluations DROP COLUMN IF EXISTS indexed_only;
+ALTER TABLE expert_evaluations DROP COLUMN IF EXISTS block_hash;
+ALTER TABLE expert_evaluations DROP COLUMN IF EXISTS block_number;
+
+DROP INDEX IF EXISTS idx_proposals_block_number;
+ALTER TABLE proposals DROP COLUMN IF EXISTS indexed_only;
+ALTER TABLE proposals DROP COLUMN IF EXISTS block_hash;
+ALTER TABLE proposals DROP COLUMN IF EXISTS block_number;
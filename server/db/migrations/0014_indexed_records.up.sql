@@ -0,0 +1,24 @@
+-- Block of the transaction behind proposals and evaluations, re-checked by the indexer after a reorg,
+-- and whether the row was only mirrored by the indexer, the API adopts those rows when their
+-- transaction is submitted.
+
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS block_number bigint;
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS block_hash varchar(66);
+ALTER TABLE proposals ADD COLUMN IF NOT EXISTS indexed_only boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_proposals_block_number ON proposals (block_number);
+
+ALTER TABLE expert_evaluations ADD COLUMN IF NOT EXISTS block_number bigint;
+ALTER TABLE expert_evaluations ADD COLUMN IF NOT EXISTS block_hash varchar(66);
+ALTER TABLE expert_evaluations ADD COLUMN IF NOT EXISTS indexed_only boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS idx_expert_evaluations_block_number ON expert_evaluations (block_number);
+
+-- Proposals submitted through the API always come with documents, the others were mirrored
+UPDATE proposals SET indexed_only = true
+WHERE NOT EXISTS (
+    SELECT 1 FROM documents
+    WHERE documents.documentable_type = 'Proposal' AND documents.documentable_id = proposals.id
+);
+
+-- Evaluations removed by a reorg are soft deleted, the replayed review must be able to record them again
+DROP INDEX IF EXISTS idx_evaluation_proposal_expert;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_evaluation_proposal_expert ON expert_evaluations (proposal_id, expert_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+)
+
+// ignoredFields are left out of before/after diffs: secrets, and bookkeeping every update changes
+var ignoredFields = map[string]bool{
+	"password":  true,
+	"UpdatedAt": true,
+}
+
+type eventKey struct{}
+
+// Recorder writes an audit event for every request to the routes it wraps
+type Recorder struct {
+	events store.AuditEventRepository
+}
+
+func NewRecorder(events store.AuditEventRepository) *Recorder {
+	return &Recorder{events: events}
+}
+
+// Audited records action for every request reaching next. It goes inside Guard.RequirePermission so the
+// actor is known, rejected logins are not actions. Handlers describe what they changed through
+// SetTarget, SetChange and SetTxHash.
+func (rec *Recorder) Audited(action string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		event := &models.AuditEvent{
+			Action:    action,
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			IP:        utils.ClientIP(r),
+			UserAgent: r.UserAgent(),
+		}
+		if claims, ok := r.Context().Value("claims").(*auth.AuthClaims); ok {
+			event.ActorID = &claims.UserID
+		}
+
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next(sw, r.WithContext(context.WithValue(r.Context(), eventKey{}, event)))
+
+		event.Status = sw.status
+		if err := rec.events.Append(event, Seal); err != nil {
+			log.Printf("Failed to record audit event %s: %v", action, err)
+		}
+	}
+}
+
+// pending returns the event being recorded for r, nil outside of an audited route
+func pending(r *http.Request) *models.AuditEvent {
+	event, _ := r.Context().Value(eventKey{}).(*models.AuditEvent)
+	return event
+}
+
+// SetTarget names the record the audited action applies to
+func SetTarget(r *http.Request, targetType string, targetID uint) {
+	if event := pending(r); event != nil {
+		event.TargetType = targetType
+		event.TargetID = &targetID
+	}
+}
+
+// SetChange records the fields that differ between before and after, before is nil for a creation
+// and after is nil for a deletion
+func SetChange(r *http.Request, before, after interface{}) {
+	if event := pending(r); event != nil {
+		event.Before, event.After = diff(before, after)
+	}
+}
+
+// SetTxHash links the audited action to the chain transaction that carried it
+func SetTxHash(r *http.Request, txHash string) {
+	if event := pending(r); event != nil {
+		event.TxHash = txHash
+	}
+}
+
+// diff returns the fields of before and after whose JSON value differs
+func diff(before, after interface{}) (models.JSON, models.JSON) {
+	beforeFields, afterFields := fields(before), fields(after)
+
+	changedBefore := map[string]interface{}{}
+	for key, value := range beforeFields {
+		if other, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			changedBefore[key] = value
+		}
+	}
+	changedAfter := map[string]interface{}{}
+	for key, value := range afterFields {
+		if other, ok := beforeFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			changedAfter[key] = value
+		}
+	}
+
+	return encode(changedBefore), encode(changedAfter)
+}
+
+// fields returns the JSON object of v by key, nil if v is not an object
+func fields(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil
+	}
+	for key := range ignoredFields {
+		delete(decoded, key)
+	}
+	return decoded
+}
+
+// encode returns the JSON of changed, nil when nothing changed
+func encode(changed map[string]interface{}) models.JSON {
+	if len(changed) == 0 {
+		return nil
+	}
+	encoded, err := json.Marshal(changed)
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
+
+// statusWriter remembers the status code written by the handler
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
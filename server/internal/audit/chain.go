@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/utils"
+)
+
+// Seal links event to the event before it: it stamps the event and sets its hash over its content
+// and prevHash. It is the seal passed to AuditEventRepository.Append.
+func Seal(event *models.AuditEvent, prevHash string) {
+	// The database keeps microseconds, the hash must survive the round trip
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.PrevHash = prevHash
+	event.Hash = eventHash(*event, prevHash)
+}
+
+// eventHash is the SHA-256 of the content of event chained to prevHash
+func eventHash(event models.AuditEvent, prevHash string) string {
+	content, _ := json.Marshal([]string{
+		prevHash,
+		optionalID(event.ActorID),
+		event.Action,
+		event.Method,
+		event.Path,
+		strconv.Itoa(event.Status),
+		event.TargetType,
+		optionalID(event.TargetID),
+		canonicalJSON(event.Before),
+		canonicalJSON(event.After),
+		event.IP,
+		event.UserAgent,
+		event.TxHash,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	return utils.HashSHA256(string(content))
+}
+
+// canonicalJSON re-encodes a JSON document with sorted keys, jsonb columns do not return the text
+// they were given
+func canonicalJSON(document models.JSON) string {
+	if len(document) == 0 {
+		return ""
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(document, &decoded); err != nil {
+		return string(document)
+	}
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		return string(document)
+	}
+	return string(encoded)
+}
+
+func optionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+)
+
+func testEvent() models.AuditEvent {
+	actor, target := uint(3), uint(7)
+	return models.AuditEvent{
+		ActorID:    &actor,
+		Action:     "user.update",
+		Method:     "PUT",
+		Path:       "/admin/users/7",
+		Status:     200,
+		TargetType: "User",
+		TargetID:   &target,
+		Before:     models.JSON(`{"email":"old@example.com","isActive":true}`),
+		After:      models.JSON(`{"email":"new@example.com","isActive":false}`),
+		IP:         "10.0.0.1",
+		UserAgent:  "curl/8.0",
+		CreatedAt:  time.Date(2026, 3, 1, 9, 0, 0, 123456000, time.UTC),
+	}
+}
+
+func TestSeal(t *testing.T) {
+	event := testEvent()
+	Seal(&event, "previous")
+
+	if event.PrevHash != "previous" {
+		t.Errorf("got previous hash %q, want %q", event.PrevHash, "previous")
+	}
+	if event.CreatedAt.Nanosecond()%int(time.Microsecond) != 0 {
+		t.Errorf("creation time %s keeps more than microseconds, the database would round it", event.CreatedAt)
+	}
+	if len(event.Hash) != 64 || event.Hash != eventHash(event, "previous") {
+		t.Errorf("got hash %q, want the hash of the sealed event", event.Hash)
+	}
+}
+
+func TestEventHash(t *testing.T) {
+	original := eventHash(testEvent(), "previous")
+	other := uint(4)
+
+	tests := []struct {
+		name     string
+		change   func(event *models.AuditEvent)
+		prevHash string
+		wantSame bool
+	}{
+		{name: "actor", change: func(e *models.AuditEvent) { e.ActorID = &other }},
+		{name: "no actor", change: func(e *models.AuditEvent) { e.ActorID = nil }},
+		{name: "action", change: func(e *models.AuditEvent) { e.Action = "user.delete" }},
+		{name: "status", change: func(e *models.AuditEvent) { e.Status = 500 }},
+		{name: "target", change: func(e *models.AuditEvent) { e.TargetID = &other }},
+		{name: "before", change: func(e *models.AuditEvent) { e.Before = models.JSON(`{"email":"old@example.com","isActive":false}`) }},
+		{name: "after", change: func(e *models.AuditEvent) { e.After = nil }},
+		{name: "transaction", change: func(e *models.AuditEvent) { e.TxHash = "0x01" }},
+		{name: "creation time", change: func(e *models.AuditEvent) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) }},
+		{name: "previous hash", prevHash: "other"},
+		{
+			name: "document as read back from jsonb",
+			change: func(e *models.AuditEvent) {
+				e.Before = models.JSON(`{"isActive": true, "email": "old@example.com"}`)
+			},
+			wantSame: true,
+		},
+		{
+			name:     "creation time in another zone",
+			change:   func(e *models.AuditEvent) { e.CreatedAt = e.CreatedAt.In(time.FixedZone("CET", 3600)) },
+			wantSame: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := testEvent()
+			if tt.change != nil {
+				tt.change(&event)
+			}
+			prevHash := tt.prevHash
+			if prevHash == "" {
+				prevHash = "previous"
+			}
+
+			if same := eventHash(event, prevHash) == original; same != tt.wantSame {
+				t.Errorf("hash unchanged: %t, want %t", same, tt.wantSame)
+			}
+		})
+	}
+}
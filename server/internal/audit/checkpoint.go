@@ -0,0 +1,239 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Checkpoint is a signed statement of the head of the audit trail at some time, written as one JSON
+// line of the checkpoint file. Events removed or rewritten after a checkpoint no longer match it.
+type Checkpoint struct {
+	EventID      uint      `json:"eventID"`
+	Hash         string    `json:"hash"`
+	CreatedAt    time.Time `json:"createdAt"`
+	AnchorTxHash string    `json:"anchorTxHash,omitempty"` // transaction carrying Hash on chain
+	Signer       string    `json:"signer"`
+	Signature    string    `json:"signature"` // personal_sign signature of Message
+}
+
+// Message is the text signed for the checkpoint, auditors can check it with any personal_sign tool
+func (c Checkpoint) Message() string {
+	return fmt.Sprintf("Anchora audit checkpoint\nevent: %d\nhash: %s\ntime: %s\nanchor: %s",
+		c.EventID, c.Hash, c.CreatedAt.UTC().Format(time.RFC3339), c.AnchorTxHash)
+}
+
+// verifySignature checks the checkpoint was signed by signer
+func (c Checkpoint) verifySignature(signer common.Address) bool {
+	signature, err := hexutil.Decode(c.Signature)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return false
+	}
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(c.Message())), signature)
+	if err != nil {
+		return false
+	}
+	return crypto.PubkeyToAddress(*pub) == signer
+}
+
+// checkpointKey parses AUDIT_CHECKPOINT_PRIVATE_KEY
+func checkpointKey(cfg config.Config) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.AuditCheckpointPrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUDIT_CHECKPOINT_PRIVATE_KEY: %w", err)
+	}
+	return key, nil
+}
+
+// readCheckpoints returns the checkpoints of the file, oldest first. A missing file has none.
+func readCheckpoints(path string) ([]Checkpoint, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening checkpoint file: %w", err)
+	}
+	defer file.Close()
+
+	var checkpoints []Checkpoint
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(scanner.Bytes(), &checkpoint); err != nil {
+			return nil, fmt.Errorf("checkpoint file line %d: %w", line, err)
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading checkpoint file: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// Checkpointer periodically signs the head of the audit trail into the checkpoint file and, with
+// AUDIT_CHECKPOINT_ANCHOR, publishes it on chain through the relayer's platform signer
+type Checkpointer struct {
+	events   store.AuditEventRepository
+	relayer  *blockchain.Relayer
+	key      *ecdsa.PrivateKey
+	path     string
+	interval time.Duration
+	anchor   bool
+
+	lastEventID uint
+}
+
+// NewCheckpointer builds a checkpointer from the configuration, relayer may be nil when on-chain
+// anchoring is off
+func NewCheckpointer(events store.AuditEventRepository, relayer *blockchain.Relayer, cfg config.Config) (*Checkpointer, error) {
+	interval, err := time.ParseDuration(cfg.AuditCheckpointInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUDIT_CHECKPOINT_INTERVAL: %w", err)
+	}
+
+	key, err := checkpointKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := cfg.AuditCheckpointAnchor == "true"
+	if anchor {
+		if relayer == nil {
+			return nil, errors.New("AUDIT_CHECKPOINT_ANCHOR needs the relayer, set RELAYER_ENABLED")
+		}
+		if _, ok := relayer.PlatformAddress(); !ok {
+			return nil, errors.New("AUDIT_CHECKPOINT_ANCHOR needs the relayer platform signer, set RELAYER_PRIVATE_KEY")
+		}
+	}
+
+	cp := &Checkpointer{
+		events:   events,
+		relayer:  relayer,
+		key:      key,
+		path:     cfg.AuditCheckpointFile,
+		interval: interval,
+		anchor:   anchor,
+	}
+
+	checkpoints, err := readCheckpoints(cp.path)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkpoints) > 0 {
+		cp.lastEventID = checkpoints[len(checkpoints)-1].EventID
+	}
+
+	return cp, nil
+}
+
+// Run writes checkpoints until ctx is cancelled
+func (cp *Checkpointer) Run(ctx context.Context) {
+	ticker := time.NewTicker(cp.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := cp.Tick(time.Now()); err != nil {
+			log.Printf("Audit checkpoint: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick checkpoints the head of the audit trail if events were recorded since the last checkpoint
+func (cp *Checkpointer) Tick(now time.Time) error {
+	head, err := cp.events.Head()
+	if errors.Is(err, store.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading audit head: %w", err)
+	}
+	if head.ID == cp.lastEventID || head.Hash == "" {
+		return nil
+	}
+
+	checkpoint := Checkpoint{
+		EventID:   head.ID,
+		Hash:      head.Hash,
+		CreatedAt: now.UTC().Truncate(time.Second),
+		Signer:    crypto.PubkeyToAddress(cp.key.PublicKey).Hex(),
+	}
+
+	if cp.anchor {
+		relayed, err := cp.relayer.AnchorHash(0, common.HexToHash(head.Hash))
+		if err != nil && relayed == nil {
+			return fmt.Errorf("anchoring event %d on chain: %w", head.ID, err)
+		}
+		if err != nil {
+			// Recorded and rebroadcast by the relayer, the checkpoint can point at it already
+			log.Printf("Audit checkpoint: anchor transaction %s not accepted yet: %v", relayed.TxHash, err)
+		}
+		checkpoint.AnchorTxHash = relayed.TxHash
+	}
+
+	signature, err := crypto.Sign(accounts.TextHash([]byte(checkpoint.Message())), cp.key)
+	if err != nil {
+		return fmt.Errorf("signing checkpoint: %w", err)
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	checkpoint.Signature = hexutil.Encode(signature)
+
+	if err := cp.write(checkpoint); err != nil {
+		return err
+	}
+	cp.lastEventID = head.ID
+
+	log.Printf("Audit checkpoint: event %d, hash %s", checkpoint.EventID, checkpoint.Hash)
+	return nil
+}
+
+// write appends checkpoint to the checkpoint file
+func (cp *Checkpointer) write(checkpoint Checkpoint) error {
+	line, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cp.path), 0o755); err != nil {
+		return fmt.Errorf("creating checkpoint directory: %w", err)
+	}
+	file, err := os.OpenFile(cp.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening checkpoint file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+	return file.Sync()
+}
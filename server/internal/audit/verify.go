@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Verification is the result of recomputing the audit hash chain
+type Verification struct {
+	Valid              bool        `json:"valid"`
+	EventsChecked      int         `json:"eventsChecked"`
+	UnsealedEvents     int         `json:"unsealedEvents"` // recorded before the hash chain existed
+	HeadEventID        uint        `json:"headEventID"`
+	HeadHash           string      `json:"headHash"`
+	CheckpointsChecked int         `json:"checkpointsChecked"`
+	BrokenLink         *BrokenLink `json:"brokenLink"` // the first one found, nil when Valid
+}
+
+// BrokenLink describes where the audit trail stops matching its hashes
+type BrokenLink struct {
+	EventID  uint   `json:"eventID"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// Verify recomputes the hash of every audit event, checks each one links to the event before it
+// and that every signed checkpoint still matches the event it was taken at
+func Verify(events store.AuditEventRepository, cfg config.Config) (*Verification, error) {
+	var checkpoints []Checkpoint
+	if cfg.AuditCheckpointPrivateKey != "" {
+		key, err := checkpointKey(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoints, err = readCheckpoints(cfg.AuditCheckpointFile); err != nil {
+			return nil, err
+		}
+
+		signer := crypto.PubkeyToAddress(key.PublicKey)
+		for _, checkpoint := range checkpoints {
+			if !checkpoint.verifySignature(signer) {
+				return brokenAt(&Verification{}, BrokenLink{
+					EventID: checkpoint.EventID,
+					Reason:  fmt.Sprintf("checkpoint of event %d is not signed by %s", checkpoint.EventID, signer.Hex()),
+				}), nil
+			}
+		}
+	}
+
+	pending := make(map[uint]Checkpoint, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		pending[checkpoint.EventID] = checkpoint
+	}
+
+	result := &Verification{}
+	var previous *models.AuditEvent
+	var broken *BrokenLink
+
+	err := events.Each(store.AuditEventFilter{}, func(event models.AuditEvent) error {
+		result.EventsChecked++
+		defer func() { previous = &event }()
+
+		if event.Hash == "" {
+			if previous != nil && previous.Hash != "" {
+				broken = &BrokenLink{EventID: event.ID, Reason: "event is not sealed but follows sealed events"}
+				return errStop
+			}
+			result.UnsealedEvents++
+			return nil
+		}
+
+		prevHash := ""
+		if previous != nil {
+			prevHash = previous.Hash
+		}
+		if event.PrevHash != prevHash {
+			broken = &BrokenLink{
+				EventID:  event.ID,
+				Reason:   "previous hash does not match the event before it, an event was removed or inserted",
+				Expected: prevHash,
+				Actual:   event.PrevHash,
+			}
+			return errStop
+		}
+		if computed := eventHash(event, event.PrevHash); computed != event.Hash {
+			broken = &BrokenLink{
+				EventID:  event.ID,
+				Reason:   "hash does not match the event content, the event was edited",
+				Expected: computed,
+				Actual:   event.Hash,
+			}
+			return errStop
+		}
+
+		if checkpoint, ok := pending[event.ID]; ok {
+			delete(pending, event.ID)
+			result.CheckpointsChecked++
+			if checkpoint.Hash != event.Hash {
+				broken = &BrokenLink{
+					EventID:  event.ID,
+					Reason:   "hash differs from the signed checkpoint, the trail was rewritten",
+					Expected: checkpoint.Hash,
+					Actual:   event.Hash,
+				}
+				return errStop
+			}
+		}
+
+		result.HeadEventID = event.ID
+		result.HeadHash = event.Hash
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStop) {
+		return nil, fmt.Errorf("reading audit events: %w", err)
+	}
+	if broken != nil {
+		return brokenAt(result, *broken), nil
+	}
+
+	// A checkpointed event that no longer exists means the tail of the trail was cut off
+	for _, checkpoint := range checkpoints {
+		if _, missing := pending[checkpoint.EventID]; missing {
+			return brokenAt(result, BrokenLink{
+				EventID:  checkpoint.EventID,
+				Reason:   "event of a signed checkpoint is missing, the trail was truncated",
+				Expected: checkpoint.Hash,
+			}), nil
+		}
+	}
+
+	result.Valid = true
+	return result, nil
+}
+
+// errStop ends the walk over the events at the first broken link
+var errStop = errors.New("broken link found")
+
+func brokenAt(result *Verification, link BrokenLink) *Verification {
+	result.Valid = false
+	result.BrokenLink = &link
+	return result
+}
@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"crypto/ecdsa"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testTrail is an audit trail held in a slice, tests tamper with the events the way someone with
+// write access to the audit_events table could. Filters are ignored, Verify reads every event.
+type testTrail struct {
+	events []models.AuditEvent
+	lastID uint
+}
+
+var _ store.AuditEventRepository = (*testTrail)(nil)
+
+func (tr *testTrail) Append(event *models.AuditEvent, seal func(event *models.AuditEvent, prevHash string)) error {
+	prevHash := ""
+	if len(tr.events) > 0 {
+		prevHash = tr.events[len(tr.events)-1].Hash
+	}
+	seal(event, prevHash)
+	tr.lastID++
+	event.ID = tr.lastID
+	tr.events = append(tr.events, *event)
+	return nil
+}
+
+func (tr *testTrail) Head() (*models.AuditEvent, error) {
+	if len(tr.events) == 0 {
+		return nil, store.ErrNotFound
+	}
+	event := tr.events[len(tr.events)-1]
+	return &event, nil
+}
+
+func (tr *testTrail) List(filter store.AuditEventFilter, page store.Page) ([]models.AuditEvent, int64, error) {
+	events := make([]models.AuditEvent, 0, len(tr.events))
+	for i := len(tr.events) - 1; i >= 0; i-- {
+		events = append(events, tr.events[i])
+	}
+	return events, int64(len(events)), nil
+}
+
+func (tr *testTrail) Each(filter store.AuditEventFilter, fn func(event models.AuditEvent) error) error {
+	for _, event := range tr.events {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record appends sealed events for the actions
+func (tr *testTrail) record(t *testing.T, actions ...string) {
+	t.Helper()
+	for _, action := range actions {
+		event := &models.AuditEvent{Action: action, Method: "POST", Path: "/" + strings.ReplaceAll(action, ".", "/"), Status: 200}
+		if err := tr.Append(event, Seal); err != nil {
+			t.Fatalf("recording %s: %v", action, err)
+		}
+	}
+}
+
+// reseal recomputes the hash chain from the event at index i, as someone covering an edit would
+func (tr *testTrail) reseal(i int) {
+	for ; i < len(tr.events); i++ {
+		prevHash := ""
+		if i > 0 {
+			prevHash = tr.events[i-1].Hash
+		}
+		tr.events[i].PrevHash = prevHash
+		tr.events[i].Hash = eventHash(tr.events[i], prevHash)
+	}
+}
+
+func hexKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	return key, hexutil.Encode(crypto.FromECDSA(key))
+}
+
+// checkpointConfig is the configuration of a checkpoint file in a temporary directory signed by key
+func checkpointConfig(t *testing.T, key string) config.Config {
+	return config.Config{
+		AuditCheckpointInterval:   "1h",
+		AuditCheckpointFile:       filepath.Join(t.TempDir(), "checkpoints.jsonl"),
+		AuditCheckpointPrivateKey: key,
+		AuditCheckpointAnchor:     "false",
+	}
+}
+
+// checkpoint signs the head of trail into the checkpoint file of cfg
+func checkpoint(t *testing.T, trail *testTrail, cfg config.Config) {
+	t.Helper()
+	cp, err := NewCheckpointer(trail, nil, cfg)
+	if err != nil {
+		t.Fatalf("creating checkpointer: %v", err)
+	}
+	if err := cp.Tick(time.Now()); err != nil {
+		t.Fatalf("writing checkpoint: %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	_, signingKey := hexKey(t)
+	_, otherKey := hexKey(t)
+
+	tests := []struct {
+		name string
+		// unsealed events recorded before the hash chain existed
+		legacy int
+		// checkpointed signs the head of the trail before tamper runs
+		checkpointed bool
+		// verifyKey is the key Verify checks the checkpoints against, the signing key when empty
+		verifyKey  string
+		tamper     func(trail *testTrail)
+		wantEvent  uint
+		wantReason string
+	}{
+		{name: "untouched trail", checkpointed: true},
+		{name: "events recorded before the hash chain", legacy: 2},
+		{
+			name:       "edited event",
+			tamper:     func(trail *testTrail) { trail.events[1].Status = 403 },
+			wantEvent:  2,
+			wantReason: "the event was edited",
+		},
+		{
+			name: "deleted middle event",
+			tamper: func(trail *testTrail) {
+				trail.events = append(trail.events[:1], trail.events[2:]...)
+			},
+			wantEvent:  3,
+			wantReason: "an event was removed or inserted",
+		},
+		{
+			name: "sealed event blanked to look unsealed",
+			tamper: func(trail *testTrail) {
+				trail.events[2].Hash, trail.events[2].PrevHash = "", ""
+			},
+			wantEvent:  3,
+			wantReason: "not sealed but follows sealed events",
+		},
+		{
+			name:         "truncated tail",
+			checkpointed: true,
+			tamper:       func(trail *testTrail) { trail.events = trail.events[:3] },
+			wantEvent:    4,
+			wantReason:   "the trail was truncated",
+		},
+		{
+			name:         "edit resealed after a checkpoint",
+			checkpointed: true,
+			tamper: func(trail *testTrail) {
+				trail.events[1].Status = 403
+				trail.reseal(1)
+			},
+			wantEvent:  4,
+			wantReason: "the trail was rewritten",
+		},
+		{
+			name:         "checkpoint signed with another key",
+			checkpointed: true,
+			verifyKey:    otherKey,
+			wantEvent:    4,
+			wantReason:   "is not signed by",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trail := &testTrail{}
+			for i := 0; i < tt.legacy; i++ {
+				trail.Append(&models.AuditEvent{Action: "user.login", Method: "POST", Path: "/login", Status: 200},
+					func(*models.AuditEvent, string) {})
+			}
+			trail.record(t, "offer.create", "offer.update", "proposal.create", "offer.close")
+
+			cfg := checkpointConfig(t, signingKey)
+			if tt.checkpointed {
+				checkpoint(t, trail, cfg)
+			}
+			if tt.tamper != nil {
+				tt.tamper(trail)
+			}
+			if tt.verifyKey != "" {
+				cfg.AuditCheckpointPrivateKey = tt.verifyKey
+			}
+
+			result, err := Verify(trail, cfg)
+			if err != nil {
+				t.Fatalf("verifying: %v", err)
+			}
+
+			if tt.wantReason == "" {
+				if !result.Valid {
+					t.Fatalf("got broken link %+v, want a valid trail", result.BrokenLink)
+				}
+				head := trail.events[len(trail.events)-1]
+				if result.EventsChecked != len(trail.events) || result.UnsealedEvents != tt.legacy || result.HeadHash != head.Hash {
+					t.Errorf("got %d events checked, %d unsealed, head %s, want %d, %d and %s",
+						result.EventsChecked, result.UnsealedEvents, result.HeadHash, len(trail.events), tt.legacy, head.Hash)
+				}
+				if tt.checkpointed && result.CheckpointsChecked != 1 {
+					t.Errorf("got %d checkpoints checked, want 1", result.CheckpointsChecked)
+				}
+				return
+			}
+
+			if result.Valid || result.BrokenLink == nil {
+				t.Fatal("tampered trail verified as valid")
+			}
+			if result.BrokenLink.EventID != tt.wantEvent || !strings.Contains(result.BrokenLink.Reason, tt.wantReason) {
+				t.Errorf("got broken link at event %d: %s, want event %d: ...%s...",
+					result.BrokenLink.EventID, result.BrokenLink.Reason, tt.wantEvent, tt.wantReason)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Brondont/trust-api/config"
@@ -11,7 +12,7 @@ import (
 )
 
 const (
-	TokenExpirationTime         = 24 * time.Hour // Token valid for 24 hours
+	TokenExpirationTime         = 24 * time.Hour // Verification token valid for 24 hours
 	PasswordTokenExpirationTime = time.Hour
 )
 
@@ -20,6 +21,10 @@ type AuthClaims struct {
 	UserID    uint     `json:"userID"`
 	Roles     []string `json:"roles"`
 	TokenType string   `json:"type"`
+	SessionID uint     `json:"sid"`
+	// Permissions granted by the roles, loaded from the database on every request and never
+	// carried in the token, so editing a role takes effect right away
+	Permissions []string `json:"-"`
 	jwt.RegisteredClaims
 }
 
@@ -38,35 +43,49 @@ type PasswordResetClaims struct {
 	jwt.RegisteredClaims
 }
 
-// CreateAuthToken generates a JWT token for user authenticatio
-func CreateAuthToken(userID uint, roles []models.Role, isActive bool) (string, error) {
+// CreateAuthToken generates a short-lived access token bound to a login session, signed with the
+// JWT secret of cfg. The token carries a unique jti so it can be revoked before it expires.
+func CreateAuthToken(cfg config.Config, userID uint, roles []models.Role, sessionID uint) (string, *AuthClaims, error) {
+	ttl, err := time.ParseDuration(cfg.AccessTokenTTL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid ACCESS_TOKEN_TTL: %w", err)
+	}
+
+	jti, err := randomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
 	// Convert Role structs to a slice of role names (strings)
 	var roleNames []string
 	for _, role := range roles {
 		roleNames = append(roleNames, role.Name)
 	}
 	// Build the claims using the AuthClaims struct
-	claims := AuthClaims{
+	now := time.Now()
+	claims := &AuthClaims{
 		UserID:    userID,
 		Roles:     roleNames,
 		TokenType: "auth",
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpirationTime)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
 	// Create token with typed claims
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Envs.JWTSecret))
+	tokenString, err := token.SignedString([]byte(cfg.JWTSecret))
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	return tokenString, nil
+	return tokenString, claims, nil
 }
 
 // CrateVerificationToken creates the verification token for account
-func CreateVerificationToken(email string, password string) (string, error) {
+func CreateVerificationToken(cfg config.Config, email string, password string) (string, error) {
 	// we use the first hashed letters of password and hash them again with sha256 so when the user changes his password
 	// the old JWT become invalid
 	passwordFingerprint := utils.HashSHA256(password)[:8]
@@ -82,7 +101,7 @@ func CreateVerificationToken(email string, password string) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Envs.JWTSecret))
+	tokenString, err := token.SignedString([]byte(cfg.JWTSecret))
 	if err != nil {
 		return "", errors.New("failed to generate reset token")
 	}
@@ -91,7 +110,7 @@ func CreateVerificationToken(email string, password string) (string, error) {
 }
 
 // CreatePasswordResetToken generates a password reset token and returns the reset URL.
-func CreatePasswordResetToken(email, password string) (string, error) {
+func CreatePasswordResetToken(cfg config.Config, email, password string) (string, error) {
 	passwordFingerprint := utils.HashSHA256(password)[:8]
 
 	claims := PasswordResetClaims{
@@ -105,7 +124,7 @@ func CreatePasswordResetToken(email, password string) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(config.Envs.JWTSecret))
+	tokenString, err := token.SignedString([]byte(cfg.JWTSecret))
 	if err != nil {
 		return "", errors.New("failed to generate reset token")
 	}
@@ -114,12 +133,12 @@ func CreatePasswordResetToken(email, password string) (string, error) {
 }
 
 // ParseVerificationToken validates and parses a verification token, returning the claims
-func ParseVerificationToken(tokenString string) (*VerificationClaims, error) {
+func ParseVerificationToken(cfg config.Config, tokenString string) (*VerificationClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &VerificationClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(config.Envs.JWTSecret), nil
+		return []byte(cfg.JWTSecret), nil
 	})
 	if err != nil {
 		return nil, errors.New("failed to parse token: " + err.Error())
@@ -144,12 +163,12 @@ func ParseVerificationToken(tokenString string) (*VerificationClaims, error) {
 }
 
 // ParsePasswordResetToken validates and parses a password reset token
-func ParsePasswordResetToken(tokenString string) (*PasswordResetClaims, error) {
+func ParsePasswordResetToken(cfg config.Config, tokenString string) (*PasswordResetClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &PasswordResetClaims{}, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
-		return []byte(config.Envs.JWTSecret), nil
+		return []byte(cfg.JWTSecret), nil
 	})
 	if err != nil {
 		return nil, errors.New("failed to parse token: " + err.Error())
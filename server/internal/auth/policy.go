@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+)
+
+// Policies decide whether the caller may act on a given record, on top of the permission that
+// guards the route. Handlers load the record and ask the policy before touching it.
+
+// CanViewUser reports whether the caller may read the account of userID, their own or any account
+// when they manage users
+func CanViewUser(claims *AuthClaims, userID uint) bool {
+	return claims.UserID == userID || HasPermission(claims, models.PermUserManage)
+}
+
+// CanViewTransaction reports whether the caller may follow a transaction sent or reported by ownerID
+func CanViewTransaction(claims *AuthClaims, ownerID *uint) bool {
+	if ownerID != nil && *ownerID == claims.UserID {
+		return true
+	}
+	return HasPermission(claims, models.PermTransactionRead)
+}
+
+// CanEditOffer reports whether the caller may manage offer as its tender: declare the winner,
+// close it and assign its experts
+func CanEditOffer(claims *AuthClaims, offer *models.Offer) bool {
+	return offer.CreatedBy == claims.UserID && HasPermission(claims, models.PermOfferManage)
+}
+
+// IsAssignedExpert reports whether the caller was assigned to review the proposals of offer.
+// offer.ExpertAssignments must be loaded.
+func IsAssignedExpert(claims *AuthClaims, offer *models.Offer) bool {
+	for _, assignment := range offer.ExpertAssignments {
+		if assignment.ExpertID == claims.UserID {
+			return true
+		}
+	}
+	return false
+}
+
+// CanViewProposal reports whether the caller may read proposal: its proposer, the tender of the
+// offer and the experts assigned to it. proposal.Contract must be loaded with its expert assignments.
+func CanViewProposal(claims *AuthClaims, proposal *models.Proposal) bool {
+	if proposal.ProposerID == claims.UserID {
+		return true
+	}
+	if proposal.Contract.CreatedBy == claims.UserID {
+		return true
+	}
+	return IsAssignedExpert(claims, &proposal.Contract) && HasPermission(claims, models.PermEvaluationSubmit)
+}
+
+// CanEvaluate reports whether the caller may review proposal: an expert assigned to its offer who
+// did not submit it. proposal.Contract must be loaded with its expert assignments.
+func CanEvaluate(claims *AuthClaims, proposal *models.Proposal) bool {
+	if proposal.ProposerID == claims.UserID {
+		return false
+	}
+	return HasPermission(claims, models.PermEvaluationSubmit) && IsAssignedExpert(claims, &proposal.Contract)
+}
+
+// CanViewProposalDocument reports whether the caller may download document of proposal. Its proposer
+// always can, the tender and the assigned experts cannot open the financial documents before the
+// review period so no bid is seen early. proposal.Contract must be loaded with its expert assignments.
+func CanViewProposalDocument(claims *AuthClaims, proposal *models.Proposal, document *models.Document, now time.Time) bool {
+	if !CanViewProposal(claims, proposal) {
+		return false
+	}
+	if proposal.ProposerID == claims.UserID {
+		return true
+	}
+	return document.DocumentType != models.DocTypeFinancial || !now.Before(proposal.Contract.ReviewStart)
+}
+
+// RolesGrant reports whether one of roles grants permission, roles must be loaded with their permissions
+func RolesGrant(roles []models.Role, permission string) bool {
+	for _, name := range permissionNames(roles) {
+		if name == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+	"gorm.io/gorm"
+)
+
+const (
+	tenderID uint = iota + 1
+	proposerID
+	expertID
+	outsiderID
+)
+
+var reviewStart = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+// testProposal is a proposal of an offer created by tenderID with expertID assigned to it
+func testProposal() *models.Proposal {
+	return &models.Proposal{
+		Model:      gorm.Model{ID: 10},
+		ContractID: 5,
+		Contract: models.Offer{
+			Model:             gorm.Model{ID: 5},
+			CreatedBy:         tenderID,
+			ReviewStart:       reviewStart,
+			ExpertAssignments: []models.OfferExpert{{OfferID: 5, ExpertID: expertID}},
+		},
+		ProposerID: proposerID,
+	}
+}
+
+func claimsFor(userID uint, permissions ...string) *AuthClaims {
+	return &AuthClaims{UserID: userID, Permissions: permissions}
+}
+
+func TestCanViewProposal(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *AuthClaims
+		want   bool
+	}{
+		{"proposer", claimsFor(proposerID, models.PermProposalSubmit), true},
+		{"tender of the offer", claimsFor(tenderID), true},
+		{"assigned expert", claimsFor(expertID, models.PermEvaluationSubmit), true},
+		{"assigned expert without the permission", claimsFor(expertID), false},
+		{"unassigned expert", claimsFor(outsiderID, models.PermEvaluationSubmit), false},
+		{"offer manager of another offer", claimsFor(outsiderID, models.PermOfferManage), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanViewProposal(tt.claims, testProposal()); got != tt.want {
+				t.Errorf("CanViewProposal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanEvaluate(t *testing.T) {
+	ownProposal := testProposal()
+	ownProposal.ProposerID = expertID
+
+	tests := []struct {
+		name     string
+		claims   *AuthClaims
+		proposal *models.Proposal
+		want     bool
+	}{
+		{"assigned expert", claimsFor(expertID, models.PermEvaluationSubmit), testProposal(), true},
+		{"assigned expert without the permission", claimsFor(expertID), testProposal(), false},
+		{"unassigned expert", claimsFor(outsiderID, models.PermEvaluationSubmit), testProposal(), false},
+		{"assigned expert on their own proposal", claimsFor(expertID, models.PermEvaluationSubmit), ownProposal, false},
+		{"tender of the offer", claimsFor(tenderID, models.PermOfferManage), testProposal(), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanEvaluate(tt.claims, tt.proposal); got != tt.want {
+				t.Errorf("CanEvaluate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanViewProposalDocument(t *testing.T) {
+	financial := &models.Document{DocumentType: models.DocTypeFinancial}
+	technical := &models.Document{DocumentType: models.DocTypeTechnical}
+	beforeReview := reviewStart.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		claims   *AuthClaims
+		document *models.Document
+		now      time.Time
+		want     bool
+	}{
+		{"proposer opens their financial offer before the review", claimsFor(proposerID), financial, beforeReview, true},
+		{"tender before the review, financial", claimsFor(tenderID), financial, beforeReview, false},
+		{"tender before the review, technical", claimsFor(tenderID), technical, beforeReview, true},
+		{"tender once the review opens, financial", claimsFor(tenderID), financial, reviewStart, true},
+		{"expert before the review, financial", claimsFor(expertID, models.PermEvaluationSubmit), financial, beforeReview, false},
+		{"expert during the review, financial", claimsFor(expertID, models.PermEvaluationSubmit), financial, reviewStart.Add(time.Hour), true},
+		{"outsider during the review, technical", claimsFor(outsiderID, models.PermEvaluationSubmit), technical, reviewStart.Add(time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanViewProposalDocument(tt.claims, testProposal(), tt.document, tt.now); got != tt.want {
+				t.Errorf("CanViewProposalDocument() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanEditOffer(t *testing.T) {
+	offer := &testProposal().Contract
+
+	tests := []struct {
+		name   string
+		claims *AuthClaims
+		want   bool
+	}{
+		{"tender with the permission", claimsFor(tenderID, models.PermOfferManage), true},
+		{"tender without the permission", claimsFor(tenderID, models.PermOfferCreate), false},
+		{"other offer manager", claimsFor(outsiderID, models.PermOfferManage), false},
+		{"assigned expert", claimsFor(expertID, models.PermEvaluationSubmit), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanEditOffer(tt.claims, offer); got != tt.want {
+				t.Errorf("CanEditOffer() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAssignedExpert(t *testing.T) {
+	offer := &testProposal().Contract
+
+	tests := []struct {
+		name   string
+		claims *AuthClaims
+		offer  *models.Offer
+		want   bool
+	}{
+		{"assigned expert", claimsFor(expertID), offer, true},
+		{"tender of the offer", claimsFor(tenderID), offer, false},
+		{"unassigned user", claimsFor(outsiderID), offer, false},
+		{"offer without assignments", claimsFor(expertID), &models.Offer{CreatedBy: tenderID}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAssignedExpert(tt.claims, tt.offer); got != tt.want {
+				t.Errorf("IsAssignedExpert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
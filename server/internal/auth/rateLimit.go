@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/utils"
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterSweepInterval is how often the limiters of clients that went quiet are dropped
+const rateLimiterSweepInterval = time.Minute
+
+// RateLimiter limits how often a single client IP may call the routes it wraps. Every IP may send
+// burst requests at once, then one per interval.
+type RateLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	clients   map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+func NewRateLimiter(interval time.Duration, burst int) *RateLimiter {
+	return &RateLimiter{
+		limit:     rate.Every(interval),
+		burst:     burst,
+		clients:   map[string]*rate.Limiter{},
+		lastSweep: time.Now(),
+	}
+}
+
+// NewSIWENonceLimiter builds the limiter of the unauthenticated SIWE nonce route from the configuration,
+// every nonce issued is a row until the token cleaner purges it
+func NewSIWENonceLimiter(cfg config.Config) (*RateLimiter, error) {
+	interval, err := time.ParseDuration(cfg.SIWENonceRateInterval)
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid SIWE_NONCE_RATE_INTERVAL %q", cfg.SIWENonceRateInterval)
+	}
+	burst, err := strconv.Atoi(cfg.SIWENonceRateBurst)
+	if err != nil || burst < 1 {
+		return nil, fmt.Errorf("invalid SIWE_NONCE_RATE_BURST %q", cfg.SIWENonceRateBurst)
+	}
+	return NewRateLimiter(interval, burst), nil
+}
+
+// Limit answers 429 Too Many Requests once the client IP ran out of requests, next is not called
+func (rl *RateLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reservation := rl.reserve(utils.ClientIP(r), time.Now())
+		if delay := reservation.Delay(); delay > 0 {
+			// The request is refused, give the token back so waiting clients are not pushed further
+			reservation.Cancel()
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			utils.WriteError(w, http.StatusTooManyRequests, errors.New("too many requests, try again later"))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// reserve takes a request from the limiter of ip, dropping the limiters of clients whose tokens are
+// all back since they are the same as new ones
+func (rl *RateLimiter) reserve(ip string, now time.Time) *rate.Reservation {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if now.Sub(rl.lastSweep) >= rateLimiterSweepInterval {
+		for client, limiter := range rl.clients {
+			if limiter.TokensAt(now) >= float64(rl.burst) {
+				delete(rl.clients, client)
+			}
+		}
+		rl.lastSweep = now
+	}
+
+	limiter, ok := rl.clients[ip]
+	if !ok {
+		limiter = rate.NewLimiter(rl.limit, rl.burst)
+		rl.clients[ip] = limiter
+	}
+	return limiter.ReserveN(now, 1)
+}
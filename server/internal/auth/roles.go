@@ -3,20 +3,31 @@ package auth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/Brondont/trust-api/config"
-	"github.com/Brondont/trust-api/db"
 	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
 	"github.com/Brondont/trust-api/utils"
 	"github.com/golang-jwt/jwt/v5"
-	"gorm.io/gorm"
 )
 
+// Guard authenticates requests against the sessions and users of a store
+type Guard struct {
+	store  store.Store
+	secret []byte
+}
+
+// NewGuard returns a guard accepting the access tokens signed with the JWT secret of cfg
+func NewGuard(s store.Store, cfg config.Config) *Guard {
+	return &Guard{store: s, secret: []byte(cfg.JWTSecret)}
+}
+
 // ValidateAuthToken parses and validates an authentication JWT token, returning AuthClaims.
 // It ensures that the token is of type "auth" and that the user's account is active.
-func ValidateAuthToken(r *http.Request) (*AuthClaims, error) {
+func (g *Guard) ValidateAuthToken(r *http.Request) (*AuthClaims, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		return nil, errors.New("authorization header missing")
@@ -33,7 +44,7 @@ func ValidateAuthToken(r *http.Request) (*AuthClaims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, jwt.ErrSignatureInvalid
 		}
-		return []byte(config.Envs.JWTSecret), nil
+		return g.secret, nil
 	})
 	if err != nil {
 		return nil, errors.New("invalid token: " + err.Error())
@@ -48,18 +59,43 @@ func ValidateAuthToken(r *http.Request) (*AuthClaims, error) {
 		return nil, errors.New("invalid token type")
 	}
 
+	// Tokens issued before sessions existed cannot be revoked, make their holders log in again
+	if claims.ID == "" || claims.SessionID == 0 {
+		return nil, errors.New("token is no longer supported, log in again")
+	}
+
+	// Reject access tokens revoked on logout
+	revoked, err := g.store.AuthSessions().TokenRevoked(claims.ID)
+	if err != nil {
+		return nil, errors.New("failed to validate token")
+	}
+	if revoked {
+		return nil, errors.New("token has been revoked")
+	}
+
+	// Reject access tokens of sessions ended by logout-all or by an admin
+	session, err := g.store.AuthSessions().Get(claims.SessionID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, errors.New("failed to validate session")
+	}
+	if session.RevokedAt != nil || session.UserID != claims.UserID {
+		return nil, ErrSessionRevoked
+	}
+
 	// Validate user existence and current state
-	var user models.User
-	err = db.DB.DB.Preload("Roles").Where("id = ?", claims.UserID).First(&user).Error
+	user, err := g.store.Users().Get(claims.UserID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, store.ErrNotFound) {
 			return nil, errors.New("user not found")
 		}
 		return nil, errors.New("failed to validate user")
 	}
 
 	if !user.IsActive {
-		return nil, errors.New("account is not active")
+		return nil, ErrAccountInactive
 	}
 
 	// Validate roles consistency
@@ -80,11 +116,41 @@ func ValidateAuthToken(r *http.Request) (*AuthClaims, error) {
 		}
 	}
 
+	claims.Permissions = permissionNames(user.Roles)
+
 	return claims, nil
 }
 
-// HasRole checks if a user (represented by AuthClaims) has any of the required roles.
+// permissionNames returns the distinct permissions granted by roles
+func permissionNames(roles []models.Role) []string {
+	seen := make(map[string]struct{})
+	var names []string
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			if _, ok := seen[permission.Name]; ok {
+				continue
+			}
+			seen[permission.Name] = struct{}{}
+			names = append(names, permission.Name)
+		}
+	}
+	return names
+}
+
+// HasPermission checks if one of the user's roles grants permission
+func HasPermission(claims *AuthClaims, permission string) bool {
+	for _, granted := range claims.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole checks if a user (represented by AuthClaims) has any of the required roles by name.
 // If no roles are specified (len(requiredRoles) == 0), it returns true, allowing access.
+//
+// Deprecated: role names can be edited by admins, check a permission with HasPermission instead.
 func HasRole(claims *AuthClaims, requiredRoles []string) bool {
 	// No specific role required – allow any authenticated user.
 	if len(requiredRoles) == 0 {
@@ -92,10 +158,6 @@ func HasRole(claims *AuthClaims, requiredRoles []string) bool {
 	}
 
 	for _, userRole := range claims.Roles {
-		// The "admin" role has override access.
-		if userRole == "admin" {
-			return true
-		}
 		for _, requiredRole := range requiredRoles {
 			if userRole == requiredRole {
 				return true
@@ -105,18 +167,18 @@ func HasRole(claims *AuthClaims, requiredRoles []string) bool {
 	return false
 }
 
-// RequireRole is a middleware that verifies the token and checks that the user has at least one
-// of the required roles. If no roles are provided, any authenticated user is allowed.
-func RequireRole(next http.HandlerFunc, requiredRoles ...string) http.HandlerFunc {
+// RequirePermission is a middleware that verifies the token and checks that one of the user's roles
+// grants permission.
+func (g *Guard) RequirePermission(next http.HandlerFunc, permission string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		claims, err := ValidateAuthToken(r)
+		claims, err := g.ValidateAuthToken(r)
 		if err != nil {
 			utils.WriteError(w, http.StatusUnauthorized, err)
 			return
 		}
 
-		if !HasRole(claims, requiredRoles) {
-			utils.WriteError(w, http.StatusForbidden, errors.New("insufficient permissions"))
+		if !HasPermission(claims, permission) {
+			utils.WriteError(w, http.StatusForbidden, fmt.Errorf("insufficient permissions, %s is required", permission))
 			return
 		}
 
@@ -128,20 +190,26 @@ func RequireRole(next http.HandlerFunc, requiredRoles ...string) http.HandlerFun
 	}
 }
 
-func RequireRoleHandler(next http.Handler, requiredRoles ...string) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		claims, err := ValidateAuthToken(r)
+// RequireRole is a middleware that verifies the token and checks that the user has at least one
+// of the required roles. If no roles are provided, any authenticated user is allowed.
+// Guard role-specific routes with RequirePermission instead.
+func (g *Guard) RequireRole(next http.HandlerFunc, requiredRoles ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		claims, err := g.ValidateAuthToken(r)
 		if err != nil {
 			utils.WriteError(w, http.StatusUnauthorized, err)
 			return
 		}
+
 		if !HasRole(claims, requiredRoles) {
 			utils.WriteError(w, http.StatusForbidden, errors.New("insufficient permissions"))
 			return
 		}
+
 		// Set userID and claims in context for downstream handlers
 		ctx := context.WithValue(r.Context(), "userID", claims.UserID)
 		ctx = context.WithValue(ctx, "claims", claims)
+
 		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	}
 }
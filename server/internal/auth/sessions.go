@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session has been revoked")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrAccountInactive     = errors.New("account is not active")
+)
+
+// TokenPair is the access and refresh token issued on login and on every refresh
+type TokenPair struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// randomToken returns n random bytes, hex encoded
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func refreshTokenTTL(cfg config.Config) (time.Duration, error) {
+	ttl, err := time.ParseDuration(cfg.RefreshTokenTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid REFRESH_TOKEN_TTL: %w", err)
+	}
+	return ttl, nil
+}
+
+// newRefreshToken returns a refresh token and the record storing its hash. Only the hash is
+// stored, a database leak does not expose usable tokens.
+func newRefreshToken(expiresAt time.Time) (string, *models.RefreshToken, error) {
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return refreshToken, &models.RefreshToken{
+		TokenHash: utils.HashSHA256(refreshToken),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// signTokenPair signs an access token for the session matching the stored refresh token
+func signTokenPair(cfg config.Config, user models.User, sessionID uint, refreshToken string, refreshExpiresAt time.Time) (*TokenPair, error) {
+	accessToken, claims, err := CreateAuthToken(cfg, user.ID, user.Roles, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  claims.ExpiresAt.Time,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// CreateSession starts a login session for the user and issues its first token pair.
+// The user must be loaded with its roles, the token lifetimes and secret are read from cfg.
+func CreateSession(sessions store.AuthSessionRepository, cfg config.Config, user models.User, userAgent, ipAddress string) (*TokenPair, error) {
+	ttl, err := refreshTokenTTL(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.AuthSession{
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		ExpiresAt:  now.Add(ttl),
+		LastUsedAt: now,
+	}
+
+	refreshToken, token, err := newRefreshToken(session.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sessions.Create(&session, token); err != nil {
+		return nil, fmt.Errorf("creating session: %w", err)
+	}
+
+	return signTokenPair(cfg, user, session.ID, refreshToken, session.ExpiresAt)
+}
+
+// RefreshSession rotates a refresh token: the presented token is consumed and a new pair is issued
+// in the same session. Presenting a token that was already consumed means it leaked, so the whole
+// session is revoked.
+func RefreshSession(sessions store.AuthSessionRepository, cfg config.Config, refreshToken, userAgent, ipAddress string) (*TokenPair, error) {
+	ttl, err := refreshTokenTTL(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var next string
+	var user models.User
+	var session models.AuthSession
+
+	err = sessions.Rotate(utils.HashSHA256(refreshToken), func(token *models.RefreshToken, current *models.AuthSession, holder *models.User) (*models.RefreshToken, error) {
+		if current.RevokedAt != nil {
+			return nil, ErrSessionRevoked
+		}
+
+		if token.UsedAt != nil {
+			session = *current
+			return nil, ErrRefreshTokenReused
+		}
+
+		if now.After(token.ExpiresAt) || now.After(current.ExpiresAt) {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		// Roles and activation are read again so the new access token reflects the current state
+		if !holder.IsActive {
+			return nil, ErrAccountInactive
+		}
+
+		token.UsedAt = &now
+		current.ExpiresAt = now.Add(ttl)
+		current.LastUsedAt = now
+		current.UserAgent = userAgent
+		current.IPAddress = ipAddress
+
+		var stored *models.RefreshToken
+		var err error
+		next, stored, err = newRefreshToken(current.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+
+		user, session = *holder, *current
+		return stored, nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			return nil, ErrInvalidRefreshToken
+		case errors.Is(err, ErrRefreshTokenReused):
+			if err := RevokeSession(sessions, session.ID); err != nil {
+				log.Printf("Failed to revoke session %d after refresh token reuse: %v", session.ID, err)
+			}
+		}
+		return nil, err
+	}
+
+	return signTokenPair(cfg, user, session.ID, next, session.ExpiresAt)
+}
+
+// RevokeToken adds an access token to the revocation list until it expires
+func RevokeToken(sessions store.AuthSessionRepository, claims *AuthClaims) error {
+	if claims.ID == "" {
+		return nil
+	}
+
+	return sessions.RevokeToken(&models.RevokedToken{
+		JTI:       claims.ID,
+		UserID:    claims.UserID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	})
+}
+
+// RevokeSession ends a session. Its refresh tokens stop working and, since ValidateAuthToken
+// checks the session, so do the access tokens issued in it.
+func RevokeSession(sessions store.AuthSessionRepository, sessionID uint) error {
+	return sessions.Revoke(sessionID, time.Now())
+}
+
+// RevokeUserSessions ends every session of a user and returns how many were still active
+func RevokeUserSessions(sessions store.AuthSessionRepository, userID uint) (int64, error) {
+	return sessions.RevokeAll(userID, time.Now())
+}
+
+// PurgeExpiredTokens deletes revocation entries, refresh tokens and sessions that can no longer be used
+func PurgeExpiredTokens(sessions store.AuthSessionRepository, now time.Time) error {
+	// Sessions outlive their access tokens by at most the refresh TTL, keep them a day more for auditing
+	return sessions.PurgeExpired(now, now.Add(-24*time.Hour))
+}
+
+// TokenCleaner periodically purges expired tokens, sessions and wallet challenges
+type TokenCleaner struct {
+	store    store.Store
+	interval time.Duration
+}
+
+// NewTokenCleaner builds a cleaner of the tokens in s from the configuration
+func NewTokenCleaner(s store.Store, cfg config.Config) (*TokenCleaner, error) {
+	interval, err := time.ParseDuration(cfg.TokenCleanupInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TOKEN_CLEANUP_INTERVAL: %w", err)
+	}
+
+	return &TokenCleaner{store: s, interval: interval}, nil
+}
+
+// Run purges expired tokens until ctx is cancelled
+func (c *TokenCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if err := PurgeExpiredTokens(c.store.AuthSessions(), now); err != nil {
+			log.Printf("Token cleanup: %v", err)
+		}
+		if err := c.store.WalletChallenges().PurgeExpired(now); err != nil {
+			log.Printf("Token cleanup: purging wallet challenges: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
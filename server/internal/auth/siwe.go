@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Brondont/trust-api/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+	siweStatement    = "Sign in to Anchora with your wallet."
+)
+
+var ErrInvalidSIWEMessage = errors.New("invalid sign-in message")
+
+// SIWEMessage is an EIP-4361 Sign-In With Ethereum message
+type SIWEMessage struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// NewSIWEMessage builds the message the wallet is asked to sign for a nonce, for the domain, URI
+// and chain of cfg
+func NewSIWEMessage(cfg config.Config, address common.Address, nonce string, issuedAt, expiresAt time.Time) (*SIWEMessage, error) {
+	chainID, err := strconv.ParseInt(cfg.ChainID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHAIN_ID: %w", err)
+	}
+
+	return &SIWEMessage{
+		Domain:         cfg.SIWEDomain,
+		Address:        address,
+		Statement:      siweStatement,
+		URI:            cfg.FrontendURL,
+		Version:        "1",
+		ChainID:        chainID,
+		Nonce:          nonce,
+		IssuedAt:       issuedAt,
+		ExpirationTime: &expiresAt,
+	}, nil
+}
+
+// String renders the message in the EIP-4361 format
+func (m *SIWEMessage) String() string {
+	var b strings.Builder
+
+	b.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	b.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n\n")
+	}
+	b.WriteString("URI: " + m.URI + "\n")
+	b.WriteString("Version: " + m.Version + "\n")
+	b.WriteString("Chain ID: " + strconv.FormatInt(m.ChainID, 10) + "\n")
+	b.WriteString("Nonce: " + m.Nonce + "\n")
+	b.WriteString("Issued At: " + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\nNot Before: " + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+
+	return b.String()
+}
+
+// ParseSIWEMessage parses a message in the EIP-4361 format
+func ParseSIWEMessage(raw string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidSIWEMessage)
+	}
+
+	msg := &SIWEMessage{Domain: strings.TrimSuffix(lines[0], siweHeaderSuffix)}
+
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("%w: invalid address", ErrInvalidSIWEMessage)
+	}
+	msg.Address = common.HexToAddress(lines[1])
+
+	// Everything between the address and the first field is the optional statement
+	i := 2
+	var statement []string
+	for ; i < len(lines) && !strings.HasPrefix(lines[i], "URI: "); i++ {
+		statement = append(statement, lines[i])
+	}
+	msg.Statement = strings.TrimSpace(strings.Join(statement, "\n"))
+
+	parseTime := func(field, value string) (time.Time, error) {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: invalid %s", ErrInvalidSIWEMessage, field)
+		}
+		return t, nil
+	}
+
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "Resources:" {
+			for i++; i < len(lines); i++ {
+				resource, ok := strings.CutPrefix(lines[i], "- ")
+				if !ok {
+					return nil, fmt.Errorf("%w: invalid resource", ErrInvalidSIWEMessage)
+				}
+				msg.Resources = append(msg.Resources, resource)
+			}
+			break
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("%w: unexpected line %q", ErrInvalidSIWEMessage, line)
+		}
+
+		var err error
+		switch key {
+		case "URI":
+			msg.URI = value
+		case "Version":
+			msg.Version = value
+		case "Chain ID":
+			msg.ChainID, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid chain ID", ErrInvalidSIWEMessage)
+			}
+		case "Nonce":
+			msg.Nonce = value
+		case "Issued At":
+			msg.IssuedAt, err = parseTime("issued at", value)
+		case "Expiration Time":
+			var t time.Time
+			t, err = parseTime("expiration time", value)
+			msg.ExpirationTime = &t
+		case "Not Before":
+			var t time.Time
+			t, err = parseTime("not before", value)
+			msg.NotBefore = &t
+		case "Request ID":
+			msg.RequestID = value
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSIWEMessage, key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if msg.URI == "" || msg.Version == "" || msg.Nonce == "" || msg.IssuedAt.IsZero() || msg.ChainID == 0 {
+		return nil, fmt.Errorf("%w: missing required field", ErrInvalidSIWEMessage)
+	}
+
+	return msg, nil
+}
+
+// Validate checks the message was made for the domain and chain of cfg and is currently valid
+func (m *SIWEMessage) Validate(cfg config.Config, now time.Time) error {
+	if m.Domain != cfg.SIWEDomain {
+		return fmt.Errorf("%w: domain %q is not accepted", ErrInvalidSIWEMessage, m.Domain)
+	}
+
+	if m.Version != "1" {
+		return fmt.Errorf("%w: unsupported version %q", ErrInvalidSIWEMessage, m.Version)
+	}
+
+	chainID, err := strconv.ParseInt(cfg.ChainID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid CHAIN_ID: %w", err)
+	}
+	if m.ChainID != chainID {
+		return fmt.Errorf("%w: wrong chain ID %d", ErrInvalidSIWEMessage, m.ChainID)
+	}
+
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return fmt.Errorf("%w: message has expired", ErrInvalidSIWEMessage)
+	}
+
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return fmt.Errorf("%w: message is not valid yet", ErrInvalidSIWEMessage)
+	}
+
+	return nil
+}
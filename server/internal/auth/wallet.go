@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	WalletChallengeTTL = 5 * time.Minute
+
+	ChallengePurposeSIWE       = "siwe"
+	ChallengePurposeWalletBind = "wallet-bind"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrSignerMismatch   = errors.New("signature was not made by the expected wallet")
+	ErrInvalidNonce     = errors.New("invalid, expired or already used nonce")
+)
+
+// CreateWalletChallenge issues a single-use nonce for the wallet. userID is set when the
+// challenge is requested by an authenticated user and must then be consumed by the same user.
+func CreateWalletChallenge(challenges store.WalletChallengeRepository, address common.Address, purpose string, userID *uint) (*models.WalletChallenge, error) {
+	// SIWE nonces must be alphanumeric, hex fits
+	nonce, err := randomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := models.WalletChallenge{
+		Nonce:     nonce,
+		Address:   address.Hex(),
+		Purpose:   purpose,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(WalletChallengeTTL),
+	}
+	if err := challenges.Create(&challenge); err != nil {
+		return nil, fmt.Errorf("storing wallet challenge: %w", err)
+	}
+
+	return &challenge, nil
+}
+
+// ConsumeWalletChallenge marks the nonce as used, failing if it does not belong to the wallet,
+// the purpose and the user, or if it expired or was used already.
+func ConsumeWalletChallenge(challenges store.WalletChallengeRepository, nonce string, address common.Address, purpose string, userID *uint) error {
+	if err := challenges.Consume(nonce, address.Hex(), purpose, userID, time.Now()); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrInvalidNonce
+		}
+		return fmt.Errorf("consuming wallet challenge: %w", err)
+	}
+
+	return nil
+}
+
+// WalletBindMessage is the personal_sign message proving control of the wallet of a bind challenge
+func WalletBindMessage(challenge *models.WalletChallenge) string {
+	return fmt.Sprintf("Anchora wallet ownership proof\n\n"+
+		"I confirm that I control the wallet %s and want to link it to my Anchora account.\n\n"+
+		"Nonce: %s\nIssued At: %s",
+		challenge.Address, challenge.Nonce, challenge.CreatedAt.UTC().Format(time.RFC3339))
+}
+
+// VerifyWalletBind checks the signature of a wallet-bind challenge issued to the user and consumes it
+func VerifyWalletBind(challenges store.WalletChallengeRepository, nonce string, address common.Address, userID uint, signature string) error {
+	challenge, err := challenges.GetUsable(nonce, ChallengePurposeWalletBind, userID, time.Now())
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrInvalidNonce
+		}
+		return fmt.Errorf("loading wallet challenge: %w", err)
+	}
+
+	if !strings.EqualFold(challenge.Address, address.Hex()) {
+		return ErrSignerMismatch
+	}
+
+	signer, err := RecoverSigner(WalletBindMessage(challenge), signature)
+	if err != nil {
+		return err
+	}
+	if signer != address {
+		return ErrSignerMismatch
+	}
+
+	return ConsumeWalletChallenge(challenges, nonce, address, ChallengePurposeWalletBind, &userID)
+}
+
+// RecoverSigner returns the address that produced a personal_sign (EIP-191) signature of message
+func RecoverSigner(message string, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	// Wallets return v as 27/28, crypto expects 0/1
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pubKey, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
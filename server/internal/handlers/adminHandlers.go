@@ -3,100 +3,61 @@ package handlers
 import (
 	"errors"
 	"fmt"
-	"log"
-	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
-	"github.com/Brondont/trust-api/config"
-	"github.com/Brondont/trust-api/db"
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/internal/audit"
 	"github.com/Brondont/trust-api/internal/auth"
 	"github.com/Brondont/trust-api/middleware"
 	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
 	"github.com/Brondont/trust-api/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
-	"gorm.io/gorm"
 )
 
 type AdminHandler struct {
 	*Handler
 }
 
-func NewAdminHandler() *AdminHandler {
+func NewAdminHandler(h *Handler) *AdminHandler {
 	return &AdminHandler{
-		Handler: NewHandler(),
+		Handler: h,
 	}
 }
 
 func (h *AdminHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
-	page, _ := strconv.Atoi(query.Get("page"))
-	limit, _ := strconv.Atoi(query.Get("limit"))
-	search := query.Get("search")
+	page := store.PageFromQuery(query)
+	filter := store.UserFilter{Search: strings.TrimSpace(query.Get("search"))}
 
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
-
-	offset := (page - 1) * limit
-
-	baseQuery := db.DB.DB.Model(&models.User{}).Preload("Roles").Select(
-		"id", "email", "first_name", "last_name", "created_at", "phone_number", "public_wallet_address",
-	)
-
-	if search != "" {
-		searchPattern := "%" + search + "%"
-		baseQuery = baseQuery.Where(
-			"last_name LIKE ? OR first_name LIKE ? OR email LIKE ? OR phone_number LIKE ?"+
-				" OR CONCAT(first_name, ' ', last_name) LIKE ?",
-			searchPattern, searchPattern, searchPattern, searchPattern, searchPattern,
-		)
-	}
-
-	var total int64
-	if err := baseQuery.Count(&total).Error; err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, errors.New("error counting users"))
-		return
-	}
-
-	var users []models.User
-	result := baseQuery.Limit(limit).Offset(offset).Find(&users)
-	if result.Error != nil {
+	users, total, err := h.Store.Users().List(filter, page)
+	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("error fetching users"))
 		return
 	}
 
-	totalPages := int(math.Ceil(float64(total) / float64(limit)))
-
 	response := map[string]interface{}{
-		"users": users,
-		"pagination": map[string]interface{}{
-			"currentPage":  page,
-			"totalPages":   totalPages,
-			"totalItems":   total,
-			"itemsPerPage": limit,
-		},
+		"users":      users,
+		"pagination": store.NewPageInfo(page, total),
 	}
 
 	utils.WriteJson(w, http.StatusOK, response)
 }
 
 func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["userID"]
-
-	if userID == "" {
-		utils.WriteError(w, http.StatusBadRequest, errors.New("user id is missing in the URL"))
+	userID, err := strconv.ParseUint(mux.Vars(r)["userID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid user ID"))
 		return
 	}
 
 	// Check if the user exists
-	var user models.User
-	if err := db.DB.DB.Preload("Roles").Preload("Certificates").First(&user, userID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	user, err := h.Store.Users().Get(uint(userID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
 			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
 		} else {
 			utils.WriteError(w, http.StatusInternalServerError, err)
@@ -104,32 +65,14 @@ func (h *AdminHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Start a transaction to ensure a clean deletion
-	tx := db.DB.DB.Begin()
-
-	// Remove user-role associations (many-to-many)
-	if err := tx.Model(&user).Association("Roles").Clear(); err != nil {
-		tx.Rollback()
-		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to remove user-role associations"))
-		return
-	}
-
-	// Remove certificates (polymorphic relation)
-	if err := tx.Where("documentable_id = ? AND documentable_type = ?", user.ID, "User").Delete(&models.Document{}).Error; err != nil {
-		tx.Rollback()
-		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to delete user certificates"))
-		return
-	}
-
-	// Delete the user
-	if err := tx.Unscoped().Delete(&user).Error; err != nil {
-		tx.Rollback()
+	// Role assignments and documents are removed with the user
+	if err := h.Store.Users().Delete(user); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to delete user"))
 		return
 	}
 
-	// Commit the transaction
-	tx.Commit()
+	audit.SetTarget(r, "User", user.ID)
+	audit.SetChange(r, user, nil)
 
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 		"message": "User was deleted successfully.",
@@ -149,28 +92,17 @@ func (h *AdminHandler) PutUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Start a database transaction
-	tx := db.DB.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
 	// Determine the user ID to update
-	vars := mux.Vars(r)
-	userID := vars["userID"]
-	if userID == "" {
-		tx.Rollback()
+	userID, err := strconv.ParseUint(mux.Vars(r)["userID"], 10, 64)
+	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid user ID"))
 		return
 	}
 
 	// Fetch the existing user from the database
-	var existingUser models.User
-	if result := tx.Preload("Roles").First(&existingUser, userID); result.Error != nil {
-		tx.Rollback()
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+	existingUser, err := h.Store.Users().Get(uint(userID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
 			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
 			return
 		}
@@ -187,15 +119,17 @@ func (h *AdminHandler) PutUser(w http.ResponseWriter, r *http.Request) {
 	}
 	inputErrors := middleware.ValidateUserInput(updatedUser)
 	if len(inputErrors) > 0 {
-		tx.Rollback()
 		utils.WriteInputValidationError(w, http.StatusUnprocessableEntity, inputErrors)
 		return
 	}
 
 	// Check if email is already in use by another user
-	var emailCheckUser models.User
-	if result := tx.Where("email = ? AND id != ?", payload.Email, userID).First(&emailCheckUser); result.Error == nil {
-		tx.Rollback()
+	taken, err := h.Store.Users().EmailTaken(payload.Email, existingUser.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to update user"))
+		return
+	}
+	if taken {
 		err := middleware.InputValidationError{
 			Type:  "invalid",
 			Value: payload.Email,
@@ -207,27 +141,24 @@ func (h *AdminHandler) PutUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update user fields
+	before := *existingUser
 	existingUser.FirstName = payload.FirstName
 	existingUser.LastName = payload.LastName
 	existingUser.Email = payload.Email
 	existingUser.PhoneNumber = payload.PhoneNumber
 
 	// Save the updated user
-	if err := tx.Save(&existingUser).Error; err != nil {
-		tx.Rollback()
+	if err := h.Store.Users().Save(existingUser); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to update user"))
 		return
 	}
 
-	// Commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
-		return
-	}
+	audit.SetTarget(r, "User", existingUser.ID)
+	audit.SetChange(r, before, existingUser)
 
 	// Fetch the complete user with roles for response
-	var completeUser models.User
-	if err := db.DB.DB.Preload("Roles").First(&completeUser, existingUser.ID).Error; err != nil {
+	completeUser, err := h.Store.Users().Profile(existingUser.ID)
+	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -275,18 +206,13 @@ func (h *AdminHandler) PostUser(w http.ResponseWriter, r *http.Request) {
 	}
 	user.Password = hashedPassword
 
-	// Start a database transaction
-	tx := db.DB.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
 	// Check if user exists
-	var existingUser models.User
-	if result := tx.Where("email = ?", user.Email).First(&existingUser); result.Error == nil {
-		tx.Rollback()
+	taken, err := h.Store.Users().EmailTaken(user.Email, 0)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to create user"))
+		return
+	}
+	if taken {
 		err := middleware.InputValidationError{
 			Type:  "invalid",
 			Value: user.Email,
@@ -298,31 +224,29 @@ func (h *AdminHandler) PostUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create verification token
-	verificationToken, err := auth.CreateVerificationToken(user.Email, user.Password)
+	verificationToken, err := auth.CreateVerificationToken(h.Config, user.Email, user.Password)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
 	// Create the user
-	if err := tx.Create(&user).Error; err != nil {
-		tx.Rollback()
+	if err := h.Store.Users().Create(&user); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to create user"))
 		return
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
-		return
-	}
+	audit.SetTarget(r, "User", user.ID)
+	audit.SetChange(r, nil, user)
+
 	// Fetch the complete user with roles for response
-	var completeUser models.User
-	if err := db.DB.DB.Preload("Roles").First(&completeUser, user.ID).Error; err != nil {
+	completeUser, err := h.Store.Users().Profile(user.ID)
+	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 	// Construct the frontend verification URL
-	verificationURL := fmt.Sprintf("%s/activation?token=%s", config.Envs.FrontendURL, verificationToken)
+	verificationURL := fmt.Sprintf("%s/activation?token=%s", h.Config.FrontendURL, verificationToken)
 
 	// Email content
 	emailBody := fmt.Sprintf(`
@@ -334,7 +258,7 @@ func (h *AdminHandler) PostUser(w http.ResponseWriter, r *http.Request) {
 `, completeUser.FirstName, verificationURL)
 
 	// Send email
-	err = utils.SendEmail(completeUser.Email, "Account Verification", emailBody)
+	err = h.Mailer.Send(completeUser.Email, "Account Verification", emailBody)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -349,10 +273,9 @@ func (h *AdminHandler) PostUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AdminHandler) GetRoles(w http.ResponseWriter, _ *http.Request) {
-	var roles []models.Role
-	result := db.DB.DB.Find(&roles)
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	roles, err := h.Store.Roles().List()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -361,9 +284,66 @@ func (h *AdminHandler) GetRoles(w http.ResponseWriter, _ *http.Request) {
 		"roles":   roles,
 	})
 }
+
+// GetPermissions lists the permissions roles can grant
+func (h *AdminHandler) GetPermissions(w http.ResponseWriter, _ *http.Request) {
+	permissions, err := h.Store.Permissions().List()
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":     "success",
+		"permissions": permissions,
+	})
+}
+
+// lookupPermissions resolves permission names, writing a bad request if one of them does not exist
+func (h *AdminHandler) lookupPermissions(w http.ResponseWriter, names []string) ([]models.Permission, bool) {
+	permissions, err := h.Store.Permissions().GetByNames(names)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusBadRequest, errors.New("unknown permission, see GET /permissions"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+		}
+		return nil, false
+	}
+	return permissions, true
+}
+
+// loadEditableRole loads the role named in the URL, built-in roles mirror the contract roles and
+// cannot be changed
+func (h *AdminHandler) loadEditableRole(w http.ResponseWriter, r *http.Request) (*models.Role, bool) {
+	roleName := mux.Vars(r)["roleName"]
+	if roleName == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("role name is missing in the URL"))
+		return nil, false
+	}
+
+	role, err := h.Store.Roles().GetByName(roleName)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("role not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+		}
+		return nil, false
+	}
+
+	if role.BuiltIn {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("'%s' is a built-in role and cannot be changed", role.Name))
+		return nil, false
+	}
+
+	return role, true
+}
+
 func (h *AdminHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
-		Name string `json:"name"`
+		Name        string   `json:"name"`
+		Permissions []string `json:"permissions"`
 	}
 
 	if err := utils.ParseJson(r, &payload); err != nil {
@@ -376,13 +356,20 @@ func (h *AdminHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	role := models.Role{Name: payload.Name}
-	result := db.DB.DB.Create(&role)
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	permissions, ok := h.lookupPermissions(w, payload.Permissions)
+	if !ok {
+		return
+	}
+
+	role := models.Role{Name: payload.Name, Permissions: permissions}
+	if err := h.Store.Roles().Create(&role); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	audit.SetTarget(r, "Role", role.ID)
+	audit.SetChange(r, nil, role)
+
 	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
 		"message": "Role created successfully",
 		"role":    role,
@@ -390,14 +377,6 @@ func (h *AdminHandler) CreateRole(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AdminHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	roleName := vars["roleName"]
-
-	if roleName == "" {
-		utils.WriteError(w, http.StatusBadRequest, errors.New("role name is missing in the URL"))
-		return
-	}
-
 	var payload struct {
 		Name string `json:"name"`
 	}
@@ -412,22 +391,21 @@ func (h *AdminHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Println(roleName)
-
-	var role models.Role
-	result := db.DB.DB.Where("name = ?", roleName).First(&role)
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusNotFound, errors.New("role not found"))
+	role, ok := h.loadEditableRole(w, r)
+	if !ok {
 		return
 	}
 
+	before := *role
 	role.Name = payload.Name
-	result = db.DB.DB.Save(&role)
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	if err := h.Store.Roles().Save(role); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	audit.SetTarget(r, "Role", role.ID)
+	audit.SetChange(r, before, role)
+
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 		"message": "Role updated successfully",
 		"role":    role,
@@ -435,50 +413,94 @@ func (h *AdminHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AdminHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	roleName := vars["roleName"]
-
-	if roleName == "" {
-		utils.WriteError(w, http.StatusBadRequest, errors.New("role name is missing in the URL"))
+	role, ok := h.loadEditableRole(w, r)
+	if !ok {
 		return
 	}
 
 	// Check if any users are assigned to this role
-	var userCount int64
-	result := db.DB.DB.Table("user_roles").
-		Joins("JOIN roles ON user_roles.role_id = roles.id").
-		Where("roles.name = ?", roleName).
-		Count(&userCount)
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	userCount, err := h.Store.Roles().CountUsers(role.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
 	// If users are assigned to the role, return an error
 	if userCount > 0 {
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("cannot delete role '%s' because it is assigned to %d user(s)", roleName, userCount))
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("cannot delete role '%s' because it is assigned to %d user(s)", role.Name, userCount))
 		return
 	}
 
 	// Proceed with deleting the role if no users are assigned
-	result = db.DB.DB.Unscoped().Where("name = ?", roleName).Delete(&models.Role{})
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	if err := h.Store.Roles().Delete(role); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
+	audit.SetTarget(r, "Role", role.ID)
+	audit.SetChange(r, role, nil)
+
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 		"message": "Role deleted successfully",
 	})
 }
 
+// PutRolePermissions replaces the permissions a role grants
+func (h *AdminHandler) PutRolePermissions(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Permissions []string `json:"permissions"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	role, ok := h.loadEditableRole(w, r)
+	if !ok {
+		return
+	}
+
+	permissions, ok := h.lookupPermissions(w, payload.Permissions)
+	if !ok {
+		return
+	}
+
+	before := permissionNames(role.Permissions)
+	if err := h.Store.Roles().SetPermissions(role, permissions); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	audit.SetTarget(r, "Role", role.ID)
+	audit.SetChange(r, map[string]interface{}{"permissions": before}, map[string]interface{}{"permissions": permissionNames(permissions)})
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message": "Role permissions updated successfully",
+		"role":    role,
+	})
+}
+
+// permissionNames lists the names of permissions, for the audit trail
+func permissionNames(permissions []models.Permission) []string {
+	names := make([]string, len(permissions))
+	for i, permission := range permissions {
+		names[i] = permission.Name
+	}
+	return names
+}
+
 // PostUserRole assigns a role to a user.
 func (h *AdminHandler) PostUserRole(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	userID := vars["userID"]
+	userID, err := strconv.ParseUint(mux.Vars(r)["userID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return
+	}
 
 	var payload struct {
-		RoleID uint `json:"roleID"`
+		RoleID uint   `json:"roleID"`
+		TxHash string `json:"txHash"`
 	}
 
 	if err := utils.ParseJson(r, &payload); err != nil {
@@ -486,15 +508,19 @@ func (h *AdminHandler) PostUserRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if userID == "" {
-		utils.WriteError(w, http.StatusBadRequest, errors.New("userID and roleID are required"))
+	// The grant transaction hash may also come from the X-Tx-Hash header
+	if payload.TxHash == "" {
+		payload.TxHash = r.Header.Get("X-Tx-Hash")
+	}
+	if !middleware.IsValidTxHash(payload.TxHash) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("a valid txHash of the role grant transaction is required"))
 		return
 	}
 
 	// Load user
-	var user models.User
-	if err := db.DB.DB.Preload("Roles").First(&user, userID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	user, err := h.Store.Users().Get(uint(userID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
 			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
 		} else {
 			utils.WriteError(w, http.StatusInternalServerError, err)
@@ -502,12 +528,15 @@ func (h *AdminHandler) PostUserRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// TODO: add server side validatoin of transaction happening before commiting role change on the server
+	if user.PublicWalletAddress == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("user has no wallet address bound to their account"))
+		return
+	}
 
 	// Load role
-	var role models.Role
-	if err := db.DB.DB.First(&role, payload.RoleID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	role, err := h.Store.Roles().Get(payload.RoleID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
 			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("role %d not found", payload.RoleID))
 		} else {
 			utils.WriteError(w, http.StatusInternalServerError, err)
@@ -523,38 +552,84 @@ func (h *AdminHandler) PostUserRole(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Assign role in a transaction
-	tx := db.DB.DB.Begin()
-	if err := tx.Model(&user).Association("Roles").Append(&role); err != nil {
-		tx.Rollback()
+	// Verify the role was really granted on chain to the user's wallet
+	if err := h.Chain.VerifyRoleGranted(payload.TxHash, role.Name, user.PublicWalletAddress); err != nil {
+		writeChainError(w, err)
+		return
+	}
+
+	// Assign the role, recording the grant transaction
+	userRole := models.UserRole{
+		UserID:     user.ID,
+		RoleID:     role.ID,
+		RoleTxHash: payload.TxHash,
+	}
+	if err := h.Store.Users().GrantRole(&userRole); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to assign role"))
 		return
 	}
-	if err := tx.Commit().Error; err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+
+	audit.SetTarget(r, "User", user.ID)
+	audit.SetChange(r,
+		map[string]interface{}{"roles": roleNames(user.Roles)},
+		map[string]interface{}{"roles": append(roleNames(user.Roles), role.Name)})
+	audit.SetTxHash(r, payload.TxHash)
+
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
 		return
 	}
+	h.trackTransaction(blockchain.TxRef{
+		Hash:       payload.TxHash,
+		Kind:       models.ChainTxRoleGranted,
+		UserID:     &claims.UserID,
+		EntityType: "User",
+		EntityID:   &user.ID,
+	})
 
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 		"message": fmt.Sprintf("role %s assigned to user", role.Name),
 	})
 }
 
+// roleNames returns the names of roles, in order
+func roleNames(roles []models.Role) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	return names
+}
+
 // DeleteUserRole removes a role from a user, ensuring at least one remains.
 func (h *AdminHandler) DeleteUserRole(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userID"]
-	roleID := vars["roleID"]
+	userID, err := strconv.ParseUint(vars["userID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return
+	}
+	roleID, err := strconv.ParseUint(vars["roleID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid role ID"))
+		return
+	}
 
-	if userID == "" || roleID == "" {
-		utils.WriteError(w, http.StatusBadRequest, errors.New("userID and roleID are required"))
+	// DELETE has no body, the revoke transaction hash comes from the header or the query
+	txHash := r.Header.Get("X-Tx-Hash")
+	if txHash == "" {
+		txHash = r.URL.Query().Get("txHash")
+	}
+	if !middleware.IsValidTxHash(txHash) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("a valid txHash of the role revoke transaction is required"))
 		return
 	}
 
 	// Load user with roles
-	var user models.User
-	if err := db.DB.DB.Preload("Roles").First(&user, userID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+	user, err := h.Store.Users().Get(uint(userID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
 			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
 		} else {
 			utils.WriteError(w, http.StatusInternalServerError, err)
@@ -563,18 +638,16 @@ func (h *AdminHandler) DeleteUserRole(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Load the specific role
-	var role models.Role
-	if err := db.DB.DB.First(&role, roleID).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("role %s not found", roleID))
+	role, err := h.Store.Roles().Get(uint(roleID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("role %d not found", roleID))
 		} else {
 			utils.WriteError(w, http.StatusInternalServerError, err)
 		}
 		return
 	}
 
-	// TODO: add server side validatoin of transaction happening before commiting role change on the server
-
 	// Check the user actually has this role
 	hasRole := false
 	for _, r := range user.Roles {
@@ -588,19 +661,282 @@ func (h *AdminHandler) DeleteUserRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Remove the role in a transaction
-	tx := db.DB.DB.Begin()
-	if err := tx.Model(&user).Association("Roles").Delete(&role); err != nil {
-		tx.Rollback()
+	if user.PublicWalletAddress == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("user has no wallet address bound to their account"))
+		return
+	}
+
+	// Verify the role was really revoked on chain from the user's wallet
+	if err := h.Chain.VerifyRoleRevoked(txHash, role.Name, user.PublicWalletAddress); err != nil {
+		writeChainError(w, err)
+		return
+	}
+
+	// Remove the role
+	if err := h.Store.Users().RevokeRole(user.ID, role.ID); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to remove role"))
 		return
 	}
-	if err := tx.Commit().Error; err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+
+	remaining := []string{}
+	for _, name := range roleNames(user.Roles) {
+		if name != role.Name {
+			remaining = append(remaining, name)
+		}
+	}
+	audit.SetTarget(r, "User", user.ID)
+	audit.SetChange(r,
+		map[string]interface{}{"roles": roleNames(user.Roles)},
+		map[string]interface{}{"roles": remaining})
+	audit.SetTxHash(r, txHash)
+
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
 		return
 	}
+	h.trackTransaction(blockchain.TxRef{
+		Hash:       txHash,
+		Kind:       models.ChainTxRoleRevoked,
+		UserID:     &claims.UserID,
+		EntityType: "User",
+		EntityID:   &user.ID,
+	})
 
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 		"message": fmt.Sprintf("role %s removed from user", role.Name),
 	})
 }
+
+// DeleteUserSessions ends every session of a user immediately, e.g. when their account is compromised.
+func (h *AdminHandler) DeleteUserSessions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.ParseUint(vars["userID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return
+	}
+
+	user, err := h.Store.Users().Profile(uint(userID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	revoked, err := auth.RevokeUserSessions(h.Store.AuthSessions(), user.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to revoke user sessions"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":         "user sessions revoked",
+		"revokedSessions": revoked,
+	})
+}
+
+var (
+	errWalletRequestReviewed = errors.New("this wallet change request was already reviewed")
+	errWalletRequestStale    = errors.New("the account wallet changed since this request was made")
+	errWalletTaken           = errors.New("this wallet is already linked to another account")
+)
+
+// GetWalletChangeRequests lists wallet rotation and unbinding requests, pending ones by default.
+func (h *AdminHandler) GetWalletChangeRequests(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page := store.PageFromQuery(query)
+	status := query.Get("status")
+
+	switch status {
+	case "":
+		status = models.WalletChangePending
+	case "all":
+		status = ""
+	}
+
+	requests, total, err := h.Store.WalletChangeRequests().List(status, page)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("error fetching wallet change requests"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"requests":   requests,
+		"pagination": store.NewPageInfo(page, total),
+	})
+}
+
+// PutWalletChangeRequest approves or rejects a wallet change request. Approving applies the rotation
+// or unbinding; roles granted on chain stay with the old wallet and have to be granted to the new one.
+func (h *AdminHandler) PutWalletChangeRequest(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	requestID, err := strconv.ParseUint(mux.Vars(r)["requestID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request ID"))
+		return
+	}
+
+	var payload struct {
+		Approve bool   `json:"approve"`
+		Note    string `json:"note"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	request, user, err := h.Store.WalletChangeRequests().Review(uint(requestID), func(request *models.WalletChangeRequest, user *models.User) error {
+		if request.Status != models.WalletChangePending {
+			return errWalletRequestReviewed
+		}
+
+		now := h.Clock.Now()
+		request.ReviewedBy = &claims.UserID
+		request.ReviewedAt = &now
+		request.ReviewNote = strings.TrimSpace(payload.Note)
+		request.Status = models.WalletChangeRejected
+
+		if payload.Approve {
+			// The wallet changed since the request was made, it has to be made again
+			if !strings.EqualFold(user.PublicWalletAddress, request.OldAddress) {
+				return errWalletRequestStale
+			}
+
+			// The store applies the new wallet of an approved request, an unbind request has none
+			if request.Action == models.WalletChangeRotate {
+				taken, err := h.walletInUse(common.HexToAddress(request.NewAddress), user.ID)
+				if err != nil {
+					return err
+				}
+				if taken {
+					return errWalletTaken
+				}
+			}
+			request.Status = models.WalletChangeApproved
+		}
+
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			utils.WriteError(w, http.StatusNotFound, errors.New("wallet change request not found"))
+		case errors.Is(err, store.ErrConflict),
+			errors.Is(err, errWalletRequestReviewed),
+			errors.Is(err, errWalletRequestStale),
+			errors.Is(err, errWalletTaken):
+			utils.WriteError(w, http.StatusConflict, err)
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to review wallet change request"))
+		}
+		return
+	}
+
+	// Roles the new wallet needs on chain for the account to keep working
+	var rolesToGrant []string
+	if request.Status == models.WalletChangeApproved && request.Action == models.WalletChangeRotate {
+		for _, role := range user.Roles {
+			rolesToGrant = append(rolesToGrant, role.Name)
+		}
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":      fmt.Sprintf("wallet change request %s", request.Status),
+		"request":      request,
+		"rolesToGrant": rolesToGrant,
+	})
+}
+
+// GetRoleReconciliation returns the latest DB/chain role diff. With refresh=true a new diff is
+// computed in report-only mode, nothing is applied.
+func (h *AdminHandler) GetRoleReconciliation(w http.ResponseWriter, r *http.Request) {
+	report := h.Chain.LastRoleReconciliation()
+
+	if report == nil || r.URL.Query().Get("refresh") == "true" {
+		var err error
+		report, err = h.Chain.ReconcileRoles(blockchain.RolePolicyReport)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to compare roles with the blockchain"))
+			return
+		}
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":        "Role reconciliation fetched successfully",
+		"reconciliation": report,
+	})
+}
+
+// PostRoleReconciliation runs a reconciliation now, with the configured policy unless one is given.
+func (h *AdminHandler) PostRoleReconciliation(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Policy string `json:"policy"`
+	}
+
+	// The body is optional
+	if r.ContentLength > 0 {
+		if err := utils.ParseJson(r, &payload); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+			return
+		}
+	}
+
+	if payload.Policy == "" {
+		payload.Policy = h.Config.RoleReconcilePolicy
+	}
+
+	if !blockchain.IsValidRolePolicy(payload.Policy) {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("policy must be one of %s, %s or %s",
+			blockchain.RolePolicyChain, blockchain.RolePolicyDB, blockchain.RolePolicyReport))
+		return
+	}
+
+	report, err := h.Chain.ReconcileRoles(payload.Policy)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to reconcile roles with the blockchain"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":        fmt.Sprintf("Roles reconciled with policy %s", report.Policy),
+		"reconciliation": report,
+	})
+}
+
+// GetRoleAuditLogs returns the paginated role changes applied by the reconciler.
+func (h *AdminHandler) GetRoleAuditLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page := store.PageFromQuery(query)
+
+	var userID *uint
+	if raw := query.Get("userID"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid userID"))
+			return
+		}
+		value := uint(id)
+		userID = &value
+	}
+
+	logs, total, err := h.Store.RoleAuditLogs().List(userID, page)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("error fetching role audit logs"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"logs":       logs,
+		"pagination": store.NewPageInfo(page, total),
+	})
+}
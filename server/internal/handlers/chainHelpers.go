@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/utils"
+)
+
+// writeChainError maps a blockchain verification error to an HTTP response, contract reverts
+// carry their code through utils.WriteError
+func writeChainError(w http.ResponseWriter, err error) {
+	var contractErr *blockchain.ContractError
+	switch {
+	case errors.Is(err, blockchain.ErrInvalidTxHash),
+		errors.Is(err, blockchain.ErrInvalidAddress):
+		utils.WriteError(w, http.StatusBadRequest, err)
+	case errors.Is(err, blockchain.ErrTxPending):
+		utils.WriteError(w, http.StatusConflict, err)
+	case errors.Is(err, blockchain.ErrTxNotFound),
+		errors.Is(err, blockchain.ErrTxReverted),
+		errors.Is(err, blockchain.ErrEventNotFound),
+		errors.Is(err, blockchain.ErrEventMismatch),
+		errors.Is(err, blockchain.ErrOfferNotFromFactory),
+		errors.Is(err, blockchain.ErrOfferWindowsMismatch),
+		errors.As(err, &contractErr):
+		utils.WriteError(w, http.StatusUnprocessableEntity, err)
+	default:
+		utils.WriteError(w, http.StatusBadGateway, errors.New("failed to verify transaction on chain, try again later"))
+	}
+}
+
+// trackTransaction adds a verified transaction to the transaction feed, a failure only loses the feed entry
+func (h *Handler) trackTransaction(ref blockchain.TxRef) {
+	if err := h.Chain.TrackTransaction(ref); err != nil {
+		log.Printf("Failed to track transaction %s: %v", ref.Hash, err)
+	}
+}
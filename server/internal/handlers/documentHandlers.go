@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// documentRoot is where utils.SaveUploadedFile stores uploads, document paths are relative to it
+const documentRoot = "/authenticated/"
+
+// DocumentHandler serves uploaded documents by ID once the caller may see the record they belong to
+type DocumentHandler struct {
+	*Handler
+}
+
+func NewDocumentHandler(h *Handler) *DocumentHandler {
+	return &DocumentHandler{
+		Handler: h,
+	}
+}
+
+// GetDocument streams a document. Offer documents are open to any authenticated user, proposal
+// documents follow auth.CanViewProposalDocument and qualification documents auth.CanViewUser.
+// Documents the caller cannot see are answered as not found, except sealed financial documents.
+func (h *DocumentHandler) GetDocument(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	documentID, err := strconv.ParseUint(mux.Vars(r)["documentID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid document ID"))
+		return
+	}
+
+	document, err := h.Store.Documents().Get(uint(documentID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("document not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch document"))
+		}
+		return
+	}
+
+	status, err := h.documentAccess(claims, document)
+	if err != nil {
+		utils.WriteError(w, status, err)
+		return
+	}
+
+	h.serveDocument(w, r, document)
+}
+
+// documentAccess resolves the record owning document and applies its access rules, returning the
+// status and error to answer with when the caller may not download it
+func (h *DocumentHandler) documentAccess(claims *auth.AuthClaims, document *models.Document) (int, error) {
+	notFound := errors.New("document not found")
+
+	switch document.DocumentableType {
+	case "Offer":
+		return http.StatusOK, nil
+
+	case "Proposal":
+		proposal, err := h.Store.Proposals().Get(document.DocumentableID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return http.StatusNotFound, notFound
+			}
+			return http.StatusInternalServerError, errors.New("failed to fetch the proposal of the document")
+		}
+
+		if !auth.CanViewProposal(claims, proposal) {
+			return http.StatusNotFound, notFound
+		}
+		if !auth.CanViewProposalDocument(claims, proposal, document, h.Clock.Now()) {
+			return http.StatusForbidden, fmt.Errorf("financial documents are sealed until the review period opens on %s",
+				proposal.Contract.ReviewStart.UTC().Format("2006-01-02 15:04 MST"))
+		}
+		return http.StatusOK, nil
+
+	case "UserQualification":
+		qualification, err := h.Store.Users().GetQualification(document.DocumentableID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return http.StatusNotFound, notFound
+			}
+			return http.StatusInternalServerError, errors.New("failed to fetch the qualification of the document")
+		}
+
+		if !auth.CanViewUser(claims, qualification.UserID) {
+			return http.StatusNotFound, notFound
+		}
+		return http.StatusOK, nil
+	}
+
+	log.Printf("Document %d belongs to unknown type %q, refusing to serve it", document.ID, document.DocumentableType)
+	return http.StatusNotFound, notFound
+}
+
+// serveDocument streams the file of document as an attachment, ServeContent handles range and
+// conditional requests
+func (h *DocumentHandler) serveDocument(w http.ResponseWriter, r *http.Request, document *models.Document) {
+	// Paths are stored as /authenticated/<dir>/<file>, never follow one outside of that tree
+	relative, ok := strings.CutPrefix(document.DocumentPath, documentRoot)
+	if !ok {
+		log.Printf("Document %d has a path outside of %s: %s", document.ID, documentRoot, document.DocumentPath)
+		utils.WriteError(w, http.StatusNotFound, errors.New("document not found"))
+		return
+	}
+	path := filepath.Join("."+documentRoot, filepath.Clean("/"+relative))
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("document file is missing"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to open document"))
+		}
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		utils.WriteError(w, http.StatusNotFound, errors.New("document file is missing"))
+		return
+	}
+
+	extension := filepath.Ext(path)
+	name := fmt.Sprintf("%s-%d%s", document.DocumentType, document.ID, extension)
+
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	if contentType := mime.TypeByExtension(extension); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	} else {
+		w.Header().Set("Content-Type", "application/octet-stream")
+	}
+
+	http.ServeContent(w, r, name, info.ModTime(), file)
+}
@@ -1,23 +1,170 @@
 package handlers
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/middleware"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+)
 
 type EntrepreneurHandler struct {
 	*Handler
 }
 
-func NewEntrepreneurHandler() *EntrepreneurHandler {
+func NewEntrepreneurHandler(h *Handler) *EntrepreneurHandler {
 	return &EntrepreneurHandler{
-		Handler: NewHandler(),
+		Handler: h,
 	}
 }
 
-const (
-	DocTypeAdmin     = "administrative" // Dossier de candidature
-	DocTypeTechnical = "technical"      // Offre technique
-	DocTypeFinancial = "financial"      // Offre financière
-)
+// proposalDocumentFields maps the multipart file field of each bundle to its document type
+var proposalDocumentFields = map[string]string{
+	"administrativeDocuments": models.DocTypeAdmin,
+	"technicalDocuments":      models.DocTypeTechnical,
+	"financialDocuments":      models.DocTypeFinancial,
+}
 
+// PostProposal stores a proposal once its ProposalSubmitted transaction is verified on chain.
 func (h *EntrepreneurHandler) PostProposal(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	formData, err := utils.ParseMultipartForm(r, 32<<20)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid form data: %w", err))
+		return
+	}
+
+	proposalForm, validationErrors := middleware.ValidateProposalForm(formData.Fields)
+
+	// Every proposal needs the three document bundles
+	for field := range proposalDocumentFields {
+		if len(formData.FileFields[field]) == 0 {
+			validationErrors = append(validationErrors, middleware.InputValidationError{
+				Type: "required",
+				Msg:  fmt.Sprintf("%s are required", field),
+				Path: field,
+			})
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		utils.WriteJson(w, http.StatusBadRequest, middleware.ErrorResponse{Error: validationErrors})
+		return
+	}
+
+	// Load the offer the proposal is submitted to
+	offer, err := h.Store.Offers().Get(proposalForm.OfferID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("offer not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch offer"))
+		}
+		return
+	}
+
+	// Proposals are only accepted while the offer is in its submission stage
+	now := h.Clock.Now()
+	if status := offer.EffectiveStatus(now); status != models.OfferStatusSubmission {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("the submission period for this offer is not open (offer is in %s stage)", status))
+		return
+	}
+
+	// Load the caller to get their wallet
+	user, err := h.Store.Users().Get(claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while fetching user data"))
+		return
+	}
+
+	if user.PublicWalletAddress == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("you need to link a wallet to your account before submitting a proposal"))
+		return
+	}
+
+	// One proposal per entrepreneur per offer, matching the contract
+	exists, err := h.Store.Proposals().Exists(offer.ID, user.ID, proposalForm.ProposalTxHash)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if exists {
+		utils.WriteError(w, http.StatusConflict, errors.New("a proposal has already been submitted for this offer"))
+		return
+	}
+
+	// Verify the transaction really submitted this proposal on chain
+	event, err := h.Chain.VerifyProposalSubmitted(proposalForm.ProposalTxHash, offer.ContractAddress, user.PublicWalletAddress)
+	if err != nil {
+		writeChainError(w, err)
+		return
+	}
+
+	if event.Price.Cmp(proposalForm.Price) != 0 || event.Description != proposalForm.Details {
+		writeChainError(w, blockchain.ErrEventMismatch)
+		return
+	}
+
+	// Everything is validated, write the document bundles to disk before storing the proposal.
+	// The files are removed again if the proposal is not recorded.
+	var documents []models.Document
+	var savedPaths []string
+	for field, documentType := range proposalDocumentFields {
+		paths, err := utils.SaveUploadedFiles(formData.FileFields[field], "/authenticated/proposals")
+		if err != nil {
+			utils.RemoveUploadedFiles(savedPaths)
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to save the proposal documents"))
+			return
+		}
+		savedPaths = append(savedPaths, paths...)
+
+		for _, path := range paths {
+			documents = append(documents, models.Document{DocumentType: documentType, DocumentPath: path})
+		}
+	}
+
+	proposal := models.Proposal{
+		ContractID:     offer.ID,
+		ProposerID:     user.ID,
+		Details:        proposalForm.Details,
+		Price:          proposalForm.Price.String(),
+		Status:         models.ProposalStatusPending,
+		SubmittedAt:    now,
+		ProposalTxHash: proposalForm.ProposalTxHash,
+		BlockNumber:    event.BlockNumber,
+		BlockHash:      event.BlockHash.Hex(),
+	}
+
+	// A proposal the indexer already mirrored from the transaction is adopted with the documents
+	if err := h.Store.Proposals().CreateWithDocuments(&proposal, documents); err != nil {
+		utils.RemoveUploadedFiles(savedPaths)
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to save proposal"))
+		return
+	}
+
+	h.trackTransaction(blockchain.TxRef{
+		Hash:       proposalForm.ProposalTxHash,
+		Kind:       models.ChainTxProposalSubmitted,
+		From:       user.PublicWalletAddress,
+		To:         offer.ContractAddress,
+		UserID:     &user.ID,
+		EntityType: "Proposal",
+		EntityID:   &proposal.ID,
+	})
 
+	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
+		"message":  "Proposal submitted successfully",
+		"proposal": proposal,
+	})
 }
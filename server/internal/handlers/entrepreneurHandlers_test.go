@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"math/big"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/models"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// proposalRequest is a proposal form of claims' user with every document bundle attached
+func proposalRequest(t *testing.T, f *testFixture, user models.User, txHash string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for field, value := range map[string]string{
+		"offerID":        strconv.FormatUint(uint64(f.offer.ID), 10),
+		"details":        "road works",
+		"price":          "125000",
+		"proposalTxHash": txHash,
+	} {
+		if err := form.WriteField(field, value); err != nil {
+			t.Fatalf("writing %s: %v", field, err)
+		}
+	}
+	for field := range proposalDocumentFields {
+		part, err := form.CreateFormFile(field, field+".pdf")
+		if err != nil {
+			t.Fatalf("adding %s: %v", field, err)
+		}
+		part.Write([]byte("%PDF-1.4"))
+	}
+	if err := form.Close(); err != nil {
+		t.Fatalf("closing form: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+	return withClaims(r, claimsFor(user, models.PermProposalSubmit), nil)
+}
+
+// inTempDir runs the test from a temporary directory, the uploaded documents are written below it
+func inTempDir(t *testing.T) {
+	t.Helper()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("reading working directory: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("changing directory: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+}
+
+func TestPostProposal(t *testing.T) {
+	inTempDir(t)
+	during := submissionStart.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		setup      func(t *testing.T, f *testFixture)
+		event      func(f *testFixture) *blockchain.ProposalSubmittedEvent
+		wantStatus int
+	}{
+		{
+			name:       "submitted during the submission period",
+			now:        during,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "before the submission period",
+			now:        submissionStart.Add(-time.Hour),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "after the submission period",
+			now:        submissionEnd.Add(time.Hour),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "transaction sent from another wallet",
+			now:  during,
+			event: func(f *testFixture) *blockchain.ProposalSubmittedEvent {
+				return submittedBy(f.competitor, big.NewInt(125000))
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "price differing from the chain",
+			now:  during,
+			event: func(f *testFixture) *blockchain.ProposalSubmittedEvent {
+				return submittedBy(f.entrepreneur, big.NewInt(99000))
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "second proposal on the offer",
+			now:  during,
+			setup: func(t *testing.T, f *testFixture) {
+				f.addProposal(t, f.entrepreneur, otherTxHash)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name: "transaction already recorded for another proposal",
+			now:  during,
+			setup: func(t *testing.T, f *testFixture) {
+				f.addProposal(t, f.competitor, testTxHash)
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFixture(t)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+			f.chain.submitted = submittedBy(f.entrepreneur, big.NewInt(125000))
+			if tt.event != nil {
+				f.chain.submitted = tt.event(f)
+			}
+
+			h := NewEntrepreneurHandler(f.handler(tt.now))
+			serve(t, h.PostProposal, proposalRequest(t, f, f.entrepreneur, testTxHash), tt.wantStatus)
+
+			proposal, err := f.store.Proposals().GetByWallet(f.offer.ID, f.entrepreneur.PublicWalletAddress)
+			recorded := err == nil && proposal.ProposalTxHash == testTxHash
+			if recorded != (tt.wantStatus == http.StatusCreated) {
+				t.Errorf("proposal recorded: %t, want %t", recorded, tt.wantStatus == http.StatusCreated)
+			}
+			if tracked := len(f.chain.tracked) > 0; tracked != recorded {
+				t.Errorf("transaction tracked: %t, want %t", tracked, recorded)
+			}
+			if !recorded {
+				return
+			}
+
+			documents, err := f.store.Documents().ListFor("Proposal", proposal.ID)
+			if err != nil {
+				t.Fatalf("listing documents: %v", err)
+			}
+			if len(documents) != len(proposalDocumentFields) {
+				t.Errorf("got %d documents, want %d", len(documents), len(proposalDocumentFields))
+			}
+		})
+	}
+}
+
+func submittedBy(user models.User, price *big.Int) *blockchain.ProposalSubmittedEvent {
+	return &blockchain.ProposalSubmittedEvent{
+		Entrepreneur: common.HexToAddress(user.PublicWalletAddress),
+		Description:  "road works",
+		Price:        price,
+		BlockNumber:  12,
+	}
+}
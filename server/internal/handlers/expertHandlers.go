@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/internal/audit"
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/middleware"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+)
+
+type ExpertHandler struct {
+	*Handler
+}
+
+func NewExpertHandler(h *Handler) *ExpertHandler {
+	return &ExpertHandler{
+		Handler: h,
+	}
+}
+
+// GetReviewProposals lists the proposals the expert can still review, i.e. proposals
+// of offers in their review window the expert is assigned to and has not scored yet.
+func (h *ExpertHandler) GetReviewProposals(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	candidates, err := h.Store.Proposals().ListAwaitingReview(claims.UserID, h.Clock.Now())
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong getting proposals, try again"))
+		return
+	}
+
+	proposals := []models.Proposal{}
+	for _, proposal := range candidates {
+		if auth.CanEvaluate(claims, &proposal) {
+			proposals = append(proposals, proposal)
+		}
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":   "Successfully fetched proposals awaiting review",
+		"proposals": proposals,
+	})
+}
+
+// PostEvaluation records an evaluation once its ProposalReviewed transaction is verified on chain.
+// The score is taken from the chain event, the comment is kept off chain.
+func (h *ExpertHandler) PostEvaluation(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		ProposalID   uint   `json:"proposalID"`
+		Comment      string `json:"comment"`
+		ReviewTxHash string `json:"reviewTxHash"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	payload.Comment = strings.TrimSpace(payload.Comment)
+
+	var validationErrors []middleware.InputValidationError
+	if payload.ProposalID == 0 {
+		validationErrors = append(validationErrors, middleware.InputValidationError{
+			Type: "required",
+			Msg:  "proposalID is required",
+			Path: "proposalID",
+		})
+	}
+	if !middleware.IsValidTxHash(payload.ReviewTxHash) {
+		validationErrors = append(validationErrors, middleware.InputValidationError{
+			Type:  "invalid",
+			Value: payload.ReviewTxHash,
+			Msg:   "invalid transaction hash",
+			Path:  "reviewTxHash",
+		})
+	}
+	if len(validationErrors) > 0 {
+		utils.WriteInputValidationError(w, http.StatusBadRequest, validationErrors)
+		return
+	}
+
+	// Load the proposal with its offer and proposer wallet
+	proposal, err := h.Store.Proposals().Get(payload.ProposalID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("proposal not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch proposal"))
+		}
+		return
+	}
+
+	if proposal.ProposerID == claims.UserID {
+		utils.WriteError(w, http.StatusForbidden, errors.New("you cannot evaluate your own proposal"))
+		return
+	}
+
+	if !auth.CanEvaluate(claims, proposal) {
+		utils.WriteError(w, http.StatusForbidden, errors.New("you are not assigned to review the proposals of this offer"))
+		return
+	}
+
+	// Evaluations are only accepted while the offer is in its review stage
+	offer := proposal.Contract
+	if status := offer.EffectiveStatus(h.Clock.Now()); status != models.OfferStatusReview {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("the review period for this offer is not open (offer is in %s stage)", status))
+		return
+	}
+
+	// One review per expert per proposal, matching the contract
+	exists, err := h.Store.Evaluations().Exists(proposal.ID, claims.UserID, payload.ReviewTxHash)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if exists {
+		utils.WriteError(w, http.StatusConflict, errors.New("you have already evaluated this proposal"))
+		return
+	}
+
+	// Load the caller to get their wallet
+	expert, err := h.Store.Users().Get(claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while fetching user data"))
+		return
+	}
+
+	if expert.PublicWalletAddress == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("you need to link a wallet to your account before evaluating a proposal"))
+		return
+	}
+
+	// Verify the transaction really reviewed this proposal on chain
+	event, err := h.Chain.VerifyProposalReviewed(payload.ReviewTxHash, offer.ContractAddress, proposal.Proposer.PublicWalletAddress, expert.PublicWalletAddress)
+	if err != nil {
+		writeChainError(w, err)
+		return
+	}
+
+	evaluation := models.ExpertEvaluation{
+		ProposalID:   proposal.ID,
+		ExpertID:     expert.ID,
+		Score:        float64(event.Score),
+		Comment:      payload.Comment,
+		ReviewTxHash: payload.ReviewTxHash,
+		BlockNumber:  event.BlockNumber,
+		BlockHash:    event.BlockHash.Hex(),
+	}
+
+	// The review transaction joins the transaction feed with the evaluation, linked to it
+	tracked, err := blockchain.NewChainTransaction(blockchain.TxRef{
+		Hash:   payload.ReviewTxHash,
+		Kind:   models.ChainTxProposalReviewed,
+		From:   expert.PublicWalletAddress,
+		To:     offer.ContractAddress,
+		UserID: &expert.ID,
+	})
+	if err != nil {
+		writeChainError(w, err)
+		return
+	}
+
+	if err := h.Store.Evaluations().CreateWithTracking(&evaluation, tracked); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to save evaluation"))
+		return
+	}
+
+	audit.SetTarget(r, "Evaluation", evaluation.ID)
+	audit.SetChange(r, nil, evaluation)
+	audit.SetTxHash(r, payload.ReviewTxHash)
+
+	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
+		"message":    "Evaluation submitted successfully",
+		"evaluation": evaluation,
+	})
+}
+
+// GetEvaluations returns the paginated evaluation history of the authenticated expert.
+func (h *ExpertHandler) GetEvaluations(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	page := store.PageFromQuery(r.URL.Query())
+
+	evaluations, total, err := h.Store.Evaluations().ListByExpert(claims.UserID, page)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("error fetching evaluations"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"evaluations": evaluations,
+		"pagination":  store.NewPageInfo(page, total),
+	})
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPostEvaluation(t *testing.T) {
+	during := reviewStart.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		expert     func(f *testFixture) models.User
+		setup      func(t *testing.T, f *testFixture, proposal models.Proposal)
+		event      func(f *testFixture) *blockchain.ProposalReviewedEvent
+		wantStatus int
+	}{
+		{
+			name:       "reviewed during the review period",
+			now:        during,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:       "before the review period",
+			now:        reviewStart.Add(-time.Hour),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "after the review period",
+			now:        reviewEnd.Add(time.Hour),
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "expert not assigned to the offer",
+			now:        during,
+			expert:     func(f *testFixture) models.User { return f.outsider },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "review sent from another wallet",
+			now:  during,
+			event: func(f *testFixture) *blockchain.ProposalReviewedEvent {
+				return reviewedBy(f.outsider, f.entrepreneur, 8)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "review of another proposal",
+			now:  during,
+			event: func(f *testFixture) *blockchain.ProposalReviewedEvent {
+				return reviewedBy(f.expert, f.competitor, 8)
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "proposal already evaluated",
+			now:  during,
+			setup: func(t *testing.T, f *testFixture, proposal models.Proposal) {
+				evaluation := models.ExpertEvaluation{ProposalID: proposal.ID, ExpertID: f.expert.ID, Score: 5, ReviewTxHash: otherTxHash}
+				if err := f.store.Evaluations().Create(&evaluation); err != nil {
+					t.Fatalf("creating evaluation: %v", err)
+				}
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFixture(t)
+			proposal := f.addProposal(t, f.entrepreneur, otherTxHash)
+			if tt.setup != nil {
+				tt.setup(t, f, proposal)
+			}
+			expert := f.expert
+			if tt.expert != nil {
+				expert = tt.expert(f)
+			}
+			f.chain.reviewed = reviewedBy(f.expert, f.entrepreneur, 8)
+			if tt.event != nil {
+				f.chain.reviewed = tt.event(f)
+			}
+
+			h := NewExpertHandler(f.handler(tt.now))
+			r := jsonRequest(t, claimsFor(expert, models.PermEvaluationSubmit), nil, map[string]interface{}{
+				"proposalID":   proposal.ID,
+				"comment":      "  solid costing  ",
+				"reviewTxHash": testTxHash,
+			})
+			serve(t, h.PostEvaluation, r, tt.wantStatus)
+
+			evaluations, _, err := f.store.Evaluations().ListByExpert(expert.ID, store.Page{Number: 1, Size: 10})
+			if err != nil {
+				t.Fatalf("listing evaluations: %v", err)
+			}
+			var recorded *models.ExpertEvaluation
+			for i := range evaluations {
+				if evaluations[i].ReviewTxHash == testTxHash {
+					recorded = &evaluations[i]
+				}
+			}
+			if (recorded != nil) != (tt.wantStatus == http.StatusCreated) {
+				t.Fatalf("evaluation recorded: %t, want %t", recorded != nil, tt.wantStatus == http.StatusCreated)
+			}
+			if recorded == nil {
+				return
+			}
+
+			// The score comes from the chain, the comment is trimmed
+			if recorded.Score != 8 || recorded.Comment != "solid costing" {
+				t.Errorf("got score %v and comment %q, want 8 and %q", recorded.Score, recorded.Comment, "solid costing")
+			}
+			if _, err := f.store.ChainTransactions().GetByHash(testTxHash); err != nil {
+				t.Errorf("review transaction is not tracked: %v", err)
+			}
+		})
+	}
+}
+
+func reviewedBy(expert, entrepreneur models.User, score uint8) *blockchain.ProposalReviewedEvent {
+	return &blockchain.ProposalReviewedEvent{
+		Entrepreneur: common.HexToAddress(entrepreneur.PublicWalletAddress),
+		Expert:       common.HexToAddress(expert.PublicWalletAddress),
+		Score:        score,
+		BlockNumber:  40,
+	}
+}
@@ -3,17 +3,19 @@ package handlers
 import (
 	"errors"
 	"fmt"
-	"math"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/Brondont/trust-api/config"
-	"github.com/Brondont/trust-api/db"
+	"github.com/Brondont/trust-api/blockchain"
 	"github.com/Brondont/trust-api/internal/auth"
 	"github.com/Brondont/trust-api/middleware"
 	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
 	"github.com/Brondont/trust-api/utils"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -22,9 +24,9 @@ type GeneralHandler struct {
 	*Handler
 }
 
-func NewGeneralHandler() *GeneralHandler {
+func NewGeneralHandler(h *Handler) *GeneralHandler {
 	return &GeneralHandler{
-		Handler: NewHandler(),
+		Handler: h,
 	}
 }
 
@@ -37,9 +39,8 @@ func (h *GeneralHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get user object from the database
-	var user models.User
-	result := db.DB.DB.Preload("Roles").Where("email = ?", payload.Email).First(&user)
-	if result.Error != nil {
+	user, err := h.Store.Users().GetByEmail(payload.Email)
+	if err != nil {
 		// User does not exist
 		err := middleware.InputValidationError{
 			Type:  "invalid",
@@ -76,16 +77,170 @@ func (h *GeneralHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Password is correct; create a JWT auth token that includes the IsActive flag.
-	token, err := auth.CreateAuthToken(user.ID, user.Roles, user.IsActive)
+	// Password is correct; start a session with a short-lived access token and a refresh token.
+	tokens, err := auth.CreateSession(h.Store.AuthSessions(), h.Config, *user, r.UserAgent(), utils.ClientIP(r))
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to start session, try again"))
 		return
 	}
 
 	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
 		"message":             "User validated",
-		"token":               token,
+		"token":               tokens.AccessToken,
+		"expiresAt":           tokens.AccessExpiresAt,
+		"refreshToken":        tokens.RefreshToken,
+		"refreshExpiresAt":    tokens.RefreshExpiresAt,
+		"publicWalletAddress": user.PublicWalletAddress,
+		"userID":              user.ID,
+	})
+}
+
+// PostRefreshToken exchanges a refresh token for a new token pair. The presented refresh token
+// is consumed, reusing it afterwards revokes the session.
+func (h *GeneralHandler) PostRefreshToken(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	if payload.RefreshToken == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("refresh token is required"))
+		return
+	}
+
+	tokens, err := auth.RefreshSession(h.Store.AuthSessions(), h.Config, payload.RefreshToken, r.UserAgent(), utils.ClientIP(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidRefreshToken),
+			errors.Is(err, auth.ErrRefreshTokenReused),
+			errors.Is(err, auth.ErrSessionRevoked),
+			errors.Is(err, auth.ErrAccountInactive):
+			utils.WriteError(w, http.StatusUnauthorized, err)
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to refresh session, try again"))
+		}
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":          "Session refreshed",
+		"token":            tokens.AccessToken,
+		"expiresAt":        tokens.AccessExpiresAt,
+		"refreshToken":     tokens.RefreshToken,
+		"refreshExpiresAt": tokens.RefreshExpiresAt,
+	})
+}
+
+// GetSIWENonce issues a Sign-In With Ethereum nonce for a wallet, along with the message to sign.
+func (h *GeneralHandler) GetSIWENonce(w http.ResponseWriter, r *http.Request) {
+	address := r.URL.Query().Get("address")
+	if !common.IsHexAddress(address) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("a valid wallet address is required"))
+		return
+	}
+
+	challenge, err := auth.CreateWalletChallenge(h.Store.WalletChallenges(), common.HexToAddress(address), auth.ChallengePurposeSIWE, nil)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to issue nonce, try again"))
+		return
+	}
+
+	message, err := auth.NewSIWEMessage(h.Config, common.HexToAddress(address), challenge.Nonce, challenge.CreatedAt, challenge.ExpiresAt)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to issue nonce, try again"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"nonce":     challenge.Nonce,
+		"expiresAt": challenge.ExpiresAt,
+		"message":   message.String(),
+	})
+}
+
+// PostSIWEVerify logs a user in with a signed Sign-In With Ethereum message. The signer must be
+// the wallet bound to the account, the session issued is the same as for a password login.
+func (h *GeneralHandler) PostSIWEVerify(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Message   string `json:"message"`
+		Signature string `json:"signature"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	if payload.Message == "" || payload.Signature == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("message and signature are required"))
+		return
+	}
+
+	message, err := auth.ParseSIWEMessage(payload.Message)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := message.Validate(h.Config, h.Clock.Now()); err != nil {
+		if errors.Is(err, auth.ErrInvalidSIWEMessage) {
+			utils.WriteError(w, http.StatusBadRequest, err)
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to validate sign-in message"))
+		}
+		return
+	}
+
+	signer, err := auth.RecoverSigner(payload.Message, payload.Signature)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, err)
+		return
+	}
+	if signer != message.Address {
+		utils.WriteError(w, http.StatusUnauthorized, auth.ErrSignerMismatch)
+		return
+	}
+
+	if err := auth.ConsumeWalletChallenge(h.Store.WalletChallenges(), message.Nonce, message.Address, auth.ChallengePurposeSIWE, nil); err != nil {
+		if errors.Is(err, auth.ErrInvalidNonce) {
+			utils.WriteError(w, http.StatusUnauthorized, err)
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to validate nonce"))
+		}
+		return
+	}
+
+	user, err := h.Store.Users().GetByWallet(message.Address.Hex())
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusUnauthorized, errors.New("no account is linked to this wallet"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while fetching user data"))
+		}
+		return
+	}
+
+	if !user.IsActive {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("your account isn't active, check your email for the activation link"))
+		return
+	}
+
+	tokens, err := auth.CreateSession(h.Store.AuthSessions(), h.Config, *user, r.UserAgent(), utils.ClientIP(r))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to start session, try again"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
+		"message":             "User validated",
+		"token":               tokens.AccessToken,
+		"expiresAt":           tokens.AccessExpiresAt,
+		"refreshToken":        tokens.RefreshToken,
+		"refreshExpiresAt":    tokens.RefreshExpiresAt,
 		"publicWalletAddress": user.PublicWalletAddress,
 		"userID":              user.ID,
 	})
@@ -93,18 +248,19 @@ func (h *GeneralHandler) PostLogin(w http.ResponseWriter, r *http.Request) {
 
 func (h *GeneralHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	userID := vars["userID"]
-
-	if userID == "" {
+	userID, err := strconv.ParseUint(vars["userID"], 10, 64)
+	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, errors.New("user id is missing from the url"))
 		return
 	}
 
-	var userProfile models.User
-	result := db.DB.DB.Select("id", "first_name", "last_name", "email", "phone_number", "reputation").Where("id = ?", userID).First(&userProfile)
-	if result.Error != nil {
-		fmt.Println(result.Error)
-		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong with loading user profile, try again later"))
+	userProfile, err := h.Store.Users().Profile(uint(userID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong with loading user profile, try again later"))
+		}
 		return
 	}
 
@@ -136,15 +292,15 @@ func (h *GeneralHandler) ActivateUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse and validate the verification token
-	claims, err := auth.ParseVerificationToken(payload.Token)
+	claims, err := auth.ParseVerificationToken(h.Config, payload.Token)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, errors.New("yoru token is invalid, Please request a new activation token"))
 		return
 	}
 
 	// Find user by email from the token claims
-	var user models.User
-	if err := db.DB.DB.Where("email = ?", claims.Email).First(&user).Error; err != nil {
+	user, err := h.Store.Users().GetByEmail(claims.Email)
+	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, errors.New("user not found"))
 		return
 	}
@@ -161,7 +317,7 @@ func (h *GeneralHandler) ActivateUser(w http.ResponseWriter, r *http.Request) {
 	user.IsActive = true
 
 	// Save changes to database
-	if err := db.DB.DB.Save(&user).Error; err != nil {
+	if err := h.Store.Users().Save(user); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to activate account"))
 		return
 	}
@@ -194,9 +350,8 @@ func (h *GeneralHandler) ForgotPassword(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Find the user with the email
-	var user models.User
-	result := db.DB.DB.Where("email = ?", payload.Email).First(&user)
-	if result.Error != nil {
+	user, err := h.Store.Users().GetByEmail(payload.Email)
+	if err != nil {
 		// User does not exist
 		utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 			"message": "If an account with that email exists, password reset instructions have been sent",
@@ -216,14 +371,14 @@ func (h *GeneralHandler) ForgotPassword(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	passwordResetToken, err := auth.CreatePasswordResetToken(user.Email, user.Password)
+	passwordResetToken, err := auth.CreatePasswordResetToken(h.Config, user.Email, user.Password)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
 	// Create reset URL
-	resetURL := fmt.Sprintf("%s/reset-password?token=%s", config.Envs.FrontendURL, passwordResetToken)
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", h.Config.FrontendURL, passwordResetToken)
 
 	// Send email with reset link
 	emailSubject := "Password Reset Request"
@@ -236,7 +391,7 @@ func (h *GeneralHandler) ForgotPassword(w http.ResponseWriter, r *http.Request)
 			<p>If you didn't request a password reset, please ignore this email or contact support if you have concerns.</p>
 	`, resetURL)
 
-	if err := utils.SendEmail(user.Email, emailSubject, emailBody); err != nil {
+	if err := h.Mailer.Send(user.Email, emailSubject, emailBody); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to send reset email"))
 		return
 	}
@@ -291,15 +446,15 @@ func (h *GeneralHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse the password token
-	claims, err := auth.ParsePasswordResetToken(payload.Token)
+	claims, err := auth.ParsePasswordResetToken(h.Config, payload.Token)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	// Find user by email from the token claims
-	var user models.User
-	if err := db.DB.DB.Where("email = ?", claims.Email).First(&user).Error; err != nil {
+	user, err := h.Store.Users().GetByEmail(claims.Email)
+	if err != nil {
 		utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
 		return
 	}
@@ -323,11 +478,16 @@ func (h *GeneralHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	user.Password = string(hashedPassword)
 
 	// Save changes to database
-	if err := db.DB.DB.Save(&user).Error; err != nil {
+	if err := h.Store.Users().Save(user); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to update password"))
 		return
 	}
 
+	// A reset password means the old one may be compromised, end every session
+	if _, err := auth.RevokeUserSessions(h.Store.AuthSessions(), user.ID); err != nil {
+		log.Printf("Failed to revoke sessions of user %d after password reset: %v", user.ID, err)
+	}
+
 	// Return success response
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 		"message": "Password has been successfully reset",
@@ -335,15 +495,13 @@ func (h *GeneralHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *GeneralHandler) GetSectors(w http.ResponseWriter, r *http.Request) {
-	var sectors []models.Sector
-
-	result := db.DB.DB.Find(&sectors)
-	if result.Error != nil {
+	sectors, err := h.Store.Sectors().List()
+	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("server couldn't fetch the sectors"))
 		return
 	}
 
-	if result.RowsAffected == 0 {
+	if len(sectors) == 0 {
 		utils.WriteError(w, http.StatusNotFound, errors.New("no sectors available"))
 		return
 	}
@@ -355,91 +513,48 @@ func (h *GeneralHandler) GetSectors(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *GeneralHandler) GetOffers(w http.ResponseWriter, r *http.Request) {
-	// 1. Parse query params
 	q := r.URL.Query()
-	page, _ := strconv.Atoi(q.Get("page"))
-	limit, _ := strconv.Atoi(q.Get("limit"))
-	search := strings.TrimSpace(q.Get("search"))
-	sector := strings.TrimSpace(q.Get("sector"))
-	location := strings.TrimSpace(q.Get("location"))
-
-	// 2. Apply defaults
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 {
-		limit = 10
-	}
-
-	// 3. Build base query with preloads
-	baseQuery := db.DB.DB.
-		Model(&models.Offer{}).
-		Preload("Sector").
-		Preload("Creator").Where("status <> ?", "Closed")
-
-	// 4. Apply filters
-	if search != "" {
-		pattern := "%" + strings.ToLower(search) + "%"
-		baseQuery = baseQuery.Where(
-			"LOWER(title) LIKE ?",
-			pattern,
-		)
-	}
-	if sector != "" {
-		// join on sectors.code
-		baseQuery = baseQuery.
-			Joins("JOIN sectors ON sectors.id = offers.sector_id").
-			Where("sectors.code = ?", sector)
-	}
-	if location != "" {
-		// assuming offers have a `location` column
-		baseQuery = baseQuery.Where("LOWER(location) LIKE ?", "%"+strings.ToLower(location)+"%")
-	}
-
-	// 5. Count total items
-	var totalItems int64
-	if err := baseQuery.Count(&totalItems).Error; err != nil {
-		utils.WriteError(w, http.StatusInternalServerError,
-			fmt.Errorf("failed to count offers: %w", err))
-		return
+	page := store.PageFromQuery(q)
+	filter := store.OfferFilter{
+		Search:     strings.TrimSpace(q.Get("search")),
+		SectorCode: strings.TrimSpace(q.Get("sector")),
+		Location:   strings.TrimSpace(q.Get("location")),
 	}
 
-	// 6. Fetch paginated results
-	offset := (page - 1) * limit
-	var offers []models.Offer
-	if err := baseQuery.
-		Order("created_at DESC").
-		Offset(offset).
-		Limit(limit).
-		Find(&offers).Error; err != nil {
+	offers, totalItems, err := h.Store.Offers().List(filter, page)
+	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError,
 			errors.New("something went wrong getting offers, try again"))
 		return
 	}
 
-	// 7. Compute total pages
-	totalPages := int(math.Ceil(float64(totalItems) / float64(limit)))
-
-	// 8. Write JSON response
+	// The offer listing keeps the pagination fields at the top level
+	pageInfo := store.NewPageInfo(page, totalItems)
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
 		"message":      "Successfully fetched offers",
 		"offers":       offers,
-		"currentPage":  page,
-		"totalPages":   totalPages,
-		"totalItems":   totalItems,
-		"itemsPerPage": limit,
+		"currentPage":  pageInfo.CurrentPage,
+		"totalPages":   pageInfo.TotalPages,
+		"totalItems":   pageInfo.TotalItems,
+		"itemsPerPage": pageInfo.ItemsPerPage,
 	})
 }
 
 func (h *GeneralHandler) GetOffer(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	offerID := vars["offerID"]
-
-	var offer models.Offer
+	offerID, err := strconv.ParseUint(vars["offerID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid offer ID"))
+		return
+	}
 
-	result := db.DB.DB.Preload("Documents").Preload("Sector").Where("id = ?", offerID).Find(&offer)
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch requsted offer"))
+	offer, err := h.Store.Offers().Get(uint(offerID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("offer not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch requsted offer"))
+		}
 		return
 	}
 
@@ -448,3 +563,139 @@ func (h *GeneralHandler) GetOffer(w http.ResponseWriter, r *http.Request) {
 		"offer":   offer,
 	})
 }
+
+// offerDiscrepancy is a difference between the DB view of an offer and its contract
+type offerDiscrepancy struct {
+	Field      string      `json:"field"`
+	ProposalID *uint       `json:"proposalID,omitempty"`
+	Wallet     string      `json:"wallet,omitempty"`
+	DB         interface{} `json:"db"`
+	Chain      interface{} `json:"chain"`
+}
+
+// GetOfferChain returns the DB offer merged with the state of its contract, flagging where they disagree.
+// The comparison covers every proposal but only those the caller may read, see auth.CanViewProposal,
+// are returned, the others are only known through the public contract state.
+func (h *GeneralHandler) GetOfferChain(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	offerID, err := strconv.ParseUint(vars["offerID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid offer ID"))
+		return
+	}
+
+	offer, err := h.Store.Offers().Get(uint(offerID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("offer not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch requsted offer"))
+		}
+		return
+	}
+
+	proposals, err := h.Store.Proposals().ListByOffer(offer.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch offer proposals"))
+		return
+	}
+
+	state, err := h.Chain.GetOfferState(offer.ContractAddress)
+	if err != nil {
+		log.Printf("Failed to read offer %d from chain: %v", offer.ID, err)
+		utils.WriteError(w, http.StatusBadGateway, errors.New("failed to read offer from chain, try again later"))
+		return
+	}
+
+	discrepancies := compareOfferWithChain(offer, proposals, state, h.Clock.Now())
+
+	visible := []models.Proposal{}
+	for _, proposal := range proposals {
+		withOffer := proposal
+		withOffer.Contract = *offer
+		if auth.CanViewProposal(claims, &withOffer) {
+			visible = append(visible, proposal)
+		}
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":       "fetched offer chain state successfully",
+		"offer":         offer,
+		"proposals":     visible,
+		"chain":         state,
+		"inSync":        len(discrepancies) == 0,
+		"discrepancies": discrepancies,
+	})
+}
+
+// compareOfferWithChain lists where the DB offer and its proposals disagree with the contract state
+func compareOfferWithChain(offer *models.Offer, proposals []models.Proposal, state *blockchain.OfferState, now time.Time) []offerDiscrepancy {
+	discrepancies := []offerDiscrepancy{}
+
+	// The chain stage follows block time, which may lag the wall clock, so the DB being ahead on
+	// time-driven statuses is expected. Winner and close only happen on chain, the DB cannot lead them.
+	dbStatus := offer.EffectiveStatus(now)
+	chainAhead := models.LaterOfferStatus(dbStatus, state.Status) != dbStatus
+	dbAheadOnChainOnly := (dbStatus == models.OfferStatusWinnerDeclared || dbStatus == models.OfferStatusClosed) && dbStatus != state.Status
+	if chainAhead || dbAheadOnChainOnly {
+		discrepancies = append(discrepancies, offerDiscrepancy{Field: "status", DB: dbStatus, Chain: state.Status})
+	}
+
+	if uint64(len(proposals)) != state.ProposalCount {
+		discrepancies = append(discrepancies, offerDiscrepancy{Field: "proposalCount", DB: len(proposals), Chain: state.ProposalCount})
+	}
+
+	onChain := make(map[string]blockchain.OfferProposalState, len(state.Proposals))
+	for _, proposal := range state.Proposals {
+		onChain[strings.ToLower(proposal.Entrepreneur)] = proposal
+	}
+
+	var winnerWallet string
+	inDB := make(map[string]bool, len(proposals))
+	for _, proposal := range proposals {
+		proposalID := proposal.ID
+		wallet := strings.ToLower(proposal.Proposer.PublicWalletAddress)
+		inDB[wallet] = true
+		if offer.WinningProposalID != nil && *offer.WinningProposalID == proposal.ID {
+			winnerWallet = proposal.Proposer.PublicWalletAddress
+		}
+
+		chainProposal, ok := onChain[wallet]
+		if !ok {
+			discrepancies = append(discrepancies, offerDiscrepancy{Field: "proposal", ProposalID: &proposalID,
+				Wallet: proposal.Proposer.PublicWalletAddress, DB: "present", Chain: "missing"})
+			continue
+		}
+		if proposal.Price != chainProposal.Price {
+			discrepancies = append(discrepancies, offerDiscrepancy{Field: "proposal.price", ProposalID: &proposalID,
+				Wallet: chainProposal.Entrepreneur, DB: proposal.Price, Chain: chainProposal.Price})
+		}
+		if uint64(len(proposal.Evaluations)) != chainProposal.ReviewCount {
+			discrepancies = append(discrepancies, offerDiscrepancy{Field: "proposal.reviewCount", ProposalID: &proposalID,
+				Wallet: chainProposal.Entrepreneur, DB: len(proposal.Evaluations), Chain: chainProposal.ReviewCount})
+		}
+	}
+
+	for _, chainProposal := range state.Proposals {
+		if !inDB[strings.ToLower(chainProposal.Entrepreneur)] {
+			discrepancies = append(discrepancies, offerDiscrepancy{Field: "proposal",
+				Wallet: chainProposal.Entrepreneur, DB: "missing", Chain: "present"})
+		}
+	}
+
+	var chainWinner string
+	if state.WinningEntrepreneur != nil {
+		chainWinner = *state.WinningEntrepreneur
+	}
+	if !strings.EqualFold(winnerWallet, chainWinner) {
+		discrepancies = append(discrepancies, offerDiscrepancy{Field: "winningEntrepreneur", DB: winnerWallet, Chain: chainWinner})
+	}
+
+	return discrepancies
+}
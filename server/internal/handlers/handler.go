@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+)
+
+// Handler holds the dependencies shared by every handler, so they can be swapped for fakes
+type Handler struct {
+	Store  store.Store
+	Chain  blockchain.Chain
+	Mailer utils.Mailer
+	Clock  utils.Clock
+	Config config.Config
+
+	// Relayer is nil unless RELAYER_ENABLED, its endpoints then answer 503
+	Relayer *blockchain.Relayer
+}
+
+func NewHandler(store store.Store, chain blockchain.Chain, mailer utils.Mailer, clock utils.Clock, cfg config.Config, relayer *blockchain.Relayer) *Handler {
+	return &Handler{
+		Store:   store,
+		Chain:   chain,
+		Mailer:  mailer,
+		Clock:   clock,
+		Config:  cfg,
+		Relayer: relayer,
+	}
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/config"
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/mux"
+)
+
+var errNotFaked = errors.New("not implemented by the fake chain")
+
+// fakeChain answers the verifications with the events it is given, checking the wallets the way
+// the contract logs bind them. A nil event is a transaction without that event.
+type fakeChain struct {
+	submitted *blockchain.ProposalSubmittedEvent
+	reviewed  *blockchain.ProposalReviewedEvent
+	winner    *blockchain.WinnerDeclaredEvent
+	closed    *blockchain.OfferClosedEvent
+
+	tracked []blockchain.TxRef
+}
+
+var _ blockchain.Chain = (*fakeChain)(nil)
+
+func (c *fakeChain) VerifyOfferContract(offerAddress string, tenderAddr string, submitted blockchain.OfferWindows) error {
+	return errNotFaked
+}
+
+func (c *fakeChain) VerifyProposalSubmitted(txHash string, offerAddress string, entrepreneurAddr string) (*blockchain.ProposalSubmittedEvent, error) {
+	if c.submitted == nil || !strings.EqualFold(c.submitted.Entrepreneur.Hex(), entrepreneurAddr) {
+		return nil, blockchain.ErrEventNotFound
+	}
+	return c.submitted, nil
+}
+
+func (c *fakeChain) VerifyProposalReviewed(txHash string, offerAddress string, entrepreneurAddr string, expertAddr string) (*blockchain.ProposalReviewedEvent, error) {
+	if c.reviewed == nil ||
+		!strings.EqualFold(c.reviewed.Entrepreneur.Hex(), entrepreneurAddr) ||
+		!strings.EqualFold(c.reviewed.Expert.Hex(), expertAddr) {
+		return nil, blockchain.ErrEventNotFound
+	}
+	return c.reviewed, nil
+}
+
+func (c *fakeChain) VerifyWinnerDeclared(txHash string, offerAddress string) (*blockchain.WinnerDeclaredEvent, error) {
+	if c.winner == nil {
+		return nil, blockchain.ErrEventNotFound
+	}
+	return c.winner, nil
+}
+
+func (c *fakeChain) VerifyOfferClosed(txHash string, offerAddress string) (*blockchain.OfferClosedEvent, error) {
+	if c.closed == nil {
+		return nil, blockchain.ErrEventNotFound
+	}
+	return c.closed, nil
+}
+
+func (c *fakeChain) VerifyRoleGranted(txHash string, roleName string, account string) error {
+	return errNotFaked
+}
+
+func (c *fakeChain) VerifyRoleRevoked(txHash string, roleName string, account string) error {
+	return errNotFaked
+}
+
+func (c *fakeChain) GetOfferState(offerAddress string) (*blockchain.OfferState, error) {
+	return nil, errNotFaked
+}
+
+func (c *fakeChain) TrackTransaction(ref blockchain.TxRef) error {
+	c.tracked = append(c.tracked, ref)
+	return nil
+}
+
+func (c *fakeChain) ReconcileRoles(policy string) (*blockchain.RoleReconciliation, error) {
+	return nil, errNotFaked
+}
+
+func (c *fakeChain) LastRoleReconciliation() *blockchain.RoleReconciliation {
+	return nil
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// The windows of the test offer, one day each
+var (
+	submissionStart = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	submissionEnd   = submissionStart.Add(24 * time.Hour)
+	reviewStart     = submissionEnd.Add(24 * time.Hour)
+	reviewEnd       = reviewStart.Add(24 * time.Hour)
+)
+
+const (
+	testOfferAddress = "0x00000000000000000000000000000000000000a1"
+	testTxHash       = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	otherTxHash      = "0x2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+// testFixture is a memory store holding an offer of tender, with expert assigned to it, and the
+// users taking part in it, each bound to a wallet
+type testFixture struct {
+	store *store.MemoryStore
+	chain *fakeChain
+	offer models.Offer
+
+	tender       models.User
+	entrepreneur models.User
+	competitor   models.User
+	expert       models.User
+	outsider     models.User // an expert not assigned to the offer
+}
+
+func newTestFixture(t *testing.T) *testFixture {
+	t.Helper()
+
+	f := &testFixture{store: store.NewMemory(), chain: &fakeChain{}}
+	for i, user := range []*models.User{&f.tender, &f.entrepreneur, &f.competitor, &f.expert, &f.outsider} {
+		*user = models.User{
+			Email:               []string{"tender", "entrepreneur", "competitor", "expert", "outsider"}[i] + "@example.com",
+			PublicWalletAddress: common.BigToAddress(big.NewInt(int64(i + 1))).Hex(),
+			IsActive:            true,
+		}
+		if err := f.store.Users().Create(user); err != nil {
+			t.Fatalf("creating user: %v", err)
+		}
+	}
+
+	f.offer = models.Offer{
+		Title:           "Road works",
+		ContractAddress: testOfferAddress,
+		ProposalStart:   submissionStart,
+		ProposalEnd:     submissionEnd,
+		ReviewStart:     reviewStart,
+		ReviewEnd:       reviewEnd,
+		Status:          models.OfferStatusNotStarted,
+		CreatedBy:       f.tender.ID,
+	}
+	if err := f.store.Offers().Create(&f.offer); err != nil {
+		t.Fatalf("creating offer: %v", err)
+	}
+	if err := f.store.Offers().AssignExpert(&models.OfferExpert{OfferID: f.offer.ID, ExpertID: f.expert.ID}); err != nil {
+		t.Fatalf("assigning expert: %v", err)
+	}
+
+	return f
+}
+
+// handler returns the handler dependencies with the clock stopped at now
+func (f *testFixture) handler(now time.Time) *Handler {
+	return NewHandler(f.store, f.chain, nil, fixedClock(now), config.Config{}, nil)
+}
+
+// addProposal stores a proposal of proposer on the test offer
+func (f *testFixture) addProposal(t *testing.T, proposer models.User, txHash string) models.Proposal {
+	t.Helper()
+	proposal := models.Proposal{
+		ContractID:     f.offer.ID,
+		ProposerID:     proposer.ID,
+		Details:        "road works",
+		Price:          "125000",
+		Status:         models.ProposalStatusPending,
+		SubmittedAt:    submissionStart.Add(time.Hour),
+		ProposalTxHash: txHash,
+	}
+	f.store.AddProposal(&proposal)
+	return proposal
+}
+
+// setStatus persists status on the test offer, as the scheduler or an earlier request would
+func (f *testFixture) setStatus(t *testing.T, status string) {
+	t.Helper()
+	if _, err := f.store.Offers().MoveStatus(f.offer.ID, f.offer.Status, status); err != nil {
+		t.Fatalf("moving offer to %s: %v", status, err)
+	}
+	f.offer.Status = status
+}
+
+func claimsFor(user models.User, permissions ...string) *auth.AuthClaims {
+	return &auth.AuthClaims{UserID: user.ID, Permissions: permissions}
+}
+
+// withClaims returns r as the authentication middleware passes it on, with vars as the route variables
+func withClaims(r *http.Request, claims *auth.AuthClaims, vars map[string]string) *http.Request {
+	r = r.WithContext(context.WithValue(r.Context(), "claims", claims))
+	return mux.SetURLVars(r, vars)
+}
+
+func jsonRequest(t *testing.T, claims *auth.AuthClaims, vars map[string]string, payload interface{}) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("encoding payload: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return withClaims(r, claims, vars)
+}
+
+// serve runs handler on r and checks the status, returning the decoded body
+func serve(t *testing.T, handler http.HandlerFunc, r *http.Request, wantStatus int) map[string]interface{} {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler(w, r)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding response %q: %v", w.Body.String(), err)
+	}
+	if w.Code != wantStatus {
+		t.Fatalf("got status %d (%s), want %d", w.Code, w.Body.String(), wantStatus)
+	}
+	return body
+}
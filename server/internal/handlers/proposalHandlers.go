@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// ProposalHandler serves proposals to the users allowed to read them, see auth.CanViewProposal
+type ProposalHandler struct {
+	*Handler
+}
+
+func NewProposalHandler(h *Handler) *ProposalHandler {
+	return &ProposalHandler{
+		Handler: h,
+	}
+}
+
+// GetProposal returns a proposal with its documents to its proposer, the tender of the offer and
+// the experts assigned to it. Anyone else gets a not found so proposals cannot be enumerated.
+func (h *ProposalHandler) GetProposal(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	proposalID, err := strconv.ParseUint(mux.Vars(r)["proposalID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid proposal ID"))
+		return
+	}
+
+	proposal, err := h.Store.Proposals().Get(uint(proposalID))
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch proposal"))
+		return
+	}
+
+	if err != nil || !auth.CanViewProposal(claims, proposal) {
+		utils.WriteError(w, http.StatusNotFound, errors.New("proposal not found"))
+		return
+	}
+
+	// Proposals().Get loads the full proposer, keep the password hash out of the response
+	proposer, err := h.Store.Users().Profile(proposal.ProposerID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch the proposer"))
+		return
+	}
+	proposal.Proposer = *proposer
+
+	proposal.Documents, err = h.Store.Documents().ListFor("Proposal", proposal.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch the proposal documents"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"proposal": proposal,
+	})
+}
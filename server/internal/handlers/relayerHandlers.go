@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// RelayerHandler lets users without their own wallet take part on chain through the relayer.
+// The relayer only signs and broadcasts, once a transaction is mined the client records it
+// through the usual endpoints with its hash, exactly like a MetaMask transaction.
+type RelayerHandler struct {
+	*Handler
+}
+
+func NewRelayerHandler(h *Handler) *RelayerHandler {
+	return &RelayerHandler{
+		Handler: h,
+	}
+}
+
+// relayerEnabled writes a 503 when the relayer is not configured
+func (h *RelayerHandler) relayerEnabled(w http.ResponseWriter) bool {
+	if h.Relayer == nil {
+		utils.WriteError(w, http.StatusServiceUnavailable, errors.New("the transaction relayer is not enabled"))
+		return false
+	}
+	return true
+}
+
+// writeRelayerError maps a relayer error to an HTTP response
+func writeRelayerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, blockchain.ErrNoCustodialWallet):
+		utils.WriteError(w, http.StatusNotFound, err)
+	case errors.Is(err, blockchain.ErrCustodialWalletExists),
+		errors.Is(err, blockchain.ErrCustodialWalletUnbound):
+		utils.WriteError(w, http.StatusConflict, err)
+	case errors.Is(err, blockchain.ErrInvalidAddress):
+		utils.WriteError(w, http.StatusBadRequest, err)
+	case errors.Is(err, blockchain.ErrTxWouldRevert):
+		utils.WriteError(w, http.StatusUnprocessableEntity, err)
+	case errors.Is(err, blockchain.ErrNoPlatformSigner),
+		errors.Is(err, blockchain.ErrFeeTooHigh):
+		utils.WriteError(w, http.StatusServiceUnavailable, err)
+	default:
+		log.Printf("Relayer request failed: %v", err)
+		utils.WriteError(w, http.StatusBadGateway, errors.New("failed to relay transaction, try again later"))
+	}
+}
+
+// writeRelayed responds with the relayed transaction, a broadcast failure is reported with the record
+func writeRelayed(w http.ResponseWriter, relayed *models.RelayedTransaction, err error) {
+	if err != nil && relayed == nil {
+		writeRelayerError(w, err)
+		return
+	}
+	if err != nil {
+		log.Printf("Relayed transaction %s failed: %v", relayed.TxHash, err)
+		utils.WriteJson(w, http.StatusBadGateway, map[string]interface{}{
+			"error":       "the node rejected the transaction",
+			"transaction": relayed,
+		})
+		return
+	}
+
+	utils.WriteJson(w, http.StatusAccepted, map[string]interface{}{
+		"message":     "transaction sent, follow its status until it is mined",
+		"transaction": relayed,
+	})
+}
+
+// PostCustodialWallet creates a custodial wallet for the caller and binds it to their account
+func (h *RelayerHandler) PostCustodialWallet(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	wallet, err := h.Relayer.CreateCustodialWallet(claims.UserID)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrCustodialWalletExists) {
+			utils.WriteError(w, http.StatusConflict, err)
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to create wallet, try again"))
+		}
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
+		"message": "custodial wallet created, it needs funds before it can send transactions",
+		"wallet":  wallet,
+	})
+}
+
+// GetCustodialWallet returns the custodial wallet of the caller
+func (h *RelayerHandler) GetCustodialWallet(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	wallet, err := h.Relayer.CustodialWallet(claims.UserID)
+	if err != nil {
+		writeRelayerError(w, err)
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"wallet": wallet,
+	})
+}
+
+// PostRelayProposal submits a proposal to an offer from the caller's custodial wallet
+func (h *RelayerHandler) PostRelayProposal(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		OfferID uint   `json:"offerID"`
+		Details string `json:"details"`
+		Price   string `json:"price"`
+	}
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	payload.Details = strings.TrimSpace(payload.Details)
+	price, ok := new(big.Int).SetString(payload.Price, 10)
+	if payload.Details == "" || !ok || price.Sign() <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("details and a positive integer price are required"))
+		return
+	}
+
+	offer, err := h.Store.Offers().Get(payload.OfferID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("offer not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch offer"))
+		}
+		return
+	}
+
+	if status := offer.EffectiveStatus(h.Clock.Now()); status != models.OfferStatusSubmission {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("the submission period for this offer is not open (offer is in %s stage)", status))
+		return
+	}
+
+	relayed, err := h.Relayer.SubmitProposal(claims.UserID, offer.ContractAddress, payload.Details, price)
+	writeRelayed(w, relayed, err)
+}
+
+// PostRelayReview scores a proposal from the caller's custodial wallet
+func (h *RelayerHandler) PostRelayReview(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		ProposalID uint  `json:"proposalID"`
+		Score      uint8 `json:"score"`
+	}
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	if payload.Score > 10 {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("score must be between 0 and 10"))
+		return
+	}
+
+	proposal, err := h.Store.Proposals().Get(payload.ProposalID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("proposal not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch proposal"))
+		}
+		return
+	}
+
+	if !auth.CanEvaluate(claims, proposal) {
+		utils.WriteError(w, http.StatusForbidden, errors.New("you are not assigned to review the proposals of this offer"))
+		return
+	}
+
+	if status := proposal.Contract.EffectiveStatus(h.Clock.Now()); status != models.OfferStatusReview {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("the review period for this offer is not open (offer is in %s stage)", status))
+		return
+	}
+
+	relayed, err := h.Relayer.ReviewProposal(claims.UserID, proposal.Contract.ContractAddress, proposal.Proposer.PublicWalletAddress, payload.Score)
+	writeRelayed(w, relayed, err)
+}
+
+// PostRelayOffer deploys an offer contract through the OfferFactory from the caller's custodial wallet
+func (h *RelayerHandler) PostRelayOffer(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		ProposalSubmissionStart time.Time `json:"proposalSubmissionStart"`
+		ProposalSubmissionEnd   time.Time `json:"proposalSubmissionEnd"`
+		ProposalReviewStart     time.Time `json:"proposalReviewStart"`
+		ProposalReviewEnd       time.Time `json:"proposalReviewEnd"`
+	}
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format, dates must be RFC 3339"))
+		return
+	}
+
+	if !payload.ProposalSubmissionStart.Before(payload.ProposalSubmissionEnd) ||
+		!payload.ProposalSubmissionEnd.Before(payload.ProposalReviewStart) ||
+		!payload.ProposalReviewStart.Before(payload.ProposalReviewEnd) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("the submission period must come before the review period and each period must end after it starts"))
+		return
+	}
+
+	relayed, err := h.Relayer.CreateOffer(claims.UserID, blockchain.OfferWindows{
+		SubmissionStart: payload.ProposalSubmissionStart,
+		SubmissionEnd:   payload.ProposalSubmissionEnd,
+		ReviewStart:     payload.ProposalReviewStart,
+		ReviewEnd:       payload.ProposalReviewEnd,
+	})
+	writeRelayed(w, relayed, err)
+}
+
+// PostRelayRole grants a role on chain to a user's wallet with the platform signer
+func (h *RelayerHandler) PostRelayRole(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		UserID uint `json:"userID"`
+		RoleID uint `json:"roleID"`
+	}
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	user, err := h.Store.Users().Get(payload.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch user"))
+		}
+		return
+	}
+
+	if user.PublicWalletAddress == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("user has no wallet address bound to their account"))
+		return
+	}
+
+	role, err := h.Store.Roles().Get(payload.RoleID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, fmt.Errorf("role %d not found", payload.RoleID))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch role"))
+		}
+		return
+	}
+
+	relayed, err := h.Relayer.GrantRole(claims.UserID, role.Name, user.PublicWalletAddress)
+	writeRelayed(w, relayed, err)
+}
+
+// GetRelayedTransactions lists the transactions relayed for the caller, newest first
+func (h *RelayerHandler) GetRelayedTransactions(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	page := store.PageFromQuery(r.URL.Query())
+
+	transactions, total, err := h.Relayer.Transactions(claims.UserID, page)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch relayed transactions"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"transactions": transactions,
+		"pagination":   store.NewPageInfo(page, total),
+	})
+}
+
+// GetRelayedTransaction returns the status of a relayed transaction, to its requester or a user with transaction:read
+func (h *RelayerHandler) GetRelayedTransaction(w http.ResponseWriter, r *http.Request) {
+	if !h.relayerEnabled(w) {
+		return
+	}
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	txID, err := strconv.ParseUint(mux.Vars(r)["txID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid transaction ID"))
+		return
+	}
+
+	relayed, err := h.Relayer.Transaction(uint(txID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("transaction not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch transaction"))
+		}
+		return
+	}
+
+	if !auth.CanViewTransaction(claims, &relayed.RequestedBy) {
+		utils.WriteError(w, http.StatusNotFound, errors.New("transaction not found"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"transaction": relayed,
+	})
+}
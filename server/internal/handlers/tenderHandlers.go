@@ -3,22 +3,27 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 
-	"github.com/Brondont/trust-api/db"
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/internal/audit"
 	"github.com/Brondont/trust-api/internal/auth"
 	"github.com/Brondont/trust-api/middleware"
 	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
 	"github.com/Brondont/trust-api/utils"
+	"github.com/gorilla/mux"
 )
 
 type TenderHandler struct {
 	*Handler
 }
 
-func NewTenderHandler() *TenderHandler {
+func NewTenderHandler(h *Handler) *TenderHandler {
 	return &TenderHandler{
-		Handler: NewHandler(),
+		Handler: h,
 	}
 }
 
@@ -42,100 +47,401 @@ func (h *TenderHandler) PostOffer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Start transaction
-	tx := db.DB.DB.Begin()
-	if tx.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, tx.Error)
+	// Load the caller to get their wallet
+	user, err := h.Store.Users().Get(claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while fetching user data"))
+		return
+	}
+
+	if user.PublicWalletAddress == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("you need to link a wallet to your account before creating an offer"))
+		return
+	}
+
+	// An offer contract can only be registered once
+	registered, err := h.Store.Offers().ContractExists(offerForm.ContractAddress)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if registered {
+		utils.WriteError(w, http.StatusConflict, errors.New("this offer contract is already registered"))
+		return
+	}
+
+	// Verify the contract was created by the factory for the caller with the same time windows
+	if err := h.Chain.VerifyOfferContract(offerForm.ContractAddress, user.PublicWalletAddress, blockchain.OfferWindows{
+		SubmissionStart: offerForm.ProposalSubmissionStart,
+		SubmissionEnd:   offerForm.ProposalSubmissionEnd,
+		ReviewStart:     offerForm.ProposalReviewStart,
+		ReviewEnd:       offerForm.ProposalReviewEnd,
+	}); err != nil {
+		writeChainError(w, err)
 		return
 	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
 
 	// Verify sector exists
-	var sectorCount int64
-	if result := tx.Model(&models.Sector{}).Where("id = ?", offerForm.SectorID).Count(&sectorCount); result.Error != nil {
-		tx.Rollback()
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	sectorExists, err := h.Store.Sectors().Exists(offerForm.SectorID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	if sectorCount == 0 {
-		tx.Rollback()
+	if !sectorExists {
 		utils.WriteError(w, http.StatusBadRequest, errors.New("sector not found"))
 		return
 	}
 
-	// Create offer payload
-	offerPayload := models.Offer{
-		Title:                   offerForm.Title,
-		TenderNumber:            offerForm.TenderNumber,
-		Location:                offerForm.Location,
-		Description:             offerForm.Description,
-		Budget:                  offerForm.Budget,
-		Currency:                offerForm.Currency,
-		SectorID:                offerForm.SectorID,
-		ContractAddress:         offerForm.ContractAddress,
-		MinQualificationLevel:   offerForm.MinQualificationLevel,
-		ProposalSubmissionStart: offerForm.ProposalSubmissionStart,
-		ProposalSubmissionEnd:   offerForm.ProposalSubmissionEnd,
-		ProposalReviewStart:     offerForm.ProposalReviewStart,
-		ProposalReviewEnd:       offerForm.ProposalReviewEnd,
-		CreatedBy:               claims.UserID,
-		Status:                  "Open",
-	}
-
-	// Save offer
-	result := tx.Create(&offerPayload)
-	if result.Error != nil {
-		tx.Rollback()
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
-		return
-	}
-
-	// Process uploaded files
-	for _, fileHeader := range formData.Files {
-		documentPath, err := utils.SaveUploadedFile(fileHeader, "/authenticated/offers")
-		if err != nil {
-			tx.Rollback()
-			utils.WriteError(w, http.StatusInternalServerError, err)
-			return
-		}
+	// Everything is validated, write the documents to disk before storing the offer.
+	// The files are removed again if the offer is not recorded.
+	savedPaths, err := utils.SaveUploadedFiles(formData.Files, "/authenticated/offers")
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to save the offer documents"))
+		return
+	}
+
+	documents := make([]models.Document, len(savedPaths))
+	for i, path := range savedPaths {
+		documents[i] = models.Document{DocumentType: models.DocTypeOffer, DocumentPath: path}
+	}
+
+	offer := models.Offer{
+		Title:            offerForm.Title,
+		TenderNumber:     offerForm.TenderNumber,
+		Location:         offerForm.Location,
+		Description:      offerForm.Description,
+		Budget:           offerForm.Budget,
+		Currency:         offerForm.Currency,
+		SectorID:         offerForm.SectorID,
+		ContractAddress:  offerForm.ContractAddress,
+		MinQualification: offerForm.MinQualificationLevel,
+		ProposalStart:    offerForm.ProposalSubmissionStart,
+		ProposalEnd:      offerForm.ProposalSubmissionEnd,
+		ReviewStart:      offerForm.ProposalReviewStart,
+		ReviewEnd:        offerForm.ProposalReviewEnd,
+		CreatedBy:        claims.UserID,
+	}
+	offer.Status = offer.TimeStatus(h.Clock.Now())
 
-		documentPayload := models.Document{
-			DocumentType:     "offer_document",
-			DocumentPath:     documentPath,
-			DocumentableID:   offerPayload.ID,
-			DocumentableType: "Offer",
+	if err := h.Store.Offers().CreateWithDocuments(&offer, documents); err != nil {
+		utils.RemoveUploadedFiles(savedPaths)
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to save offer"))
+		return
+	}
+
+	audit.SetTarget(r, "Offer", offer.ID)
+	audit.SetChange(r, nil, offer)
+
+	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
+		"message": "Offer created successfully",
+		"offer":   offer,
+	})
+}
+
+// loadOwnedOffer loads the offer from the URL and checks the caller may manage it
+func (h *Handler) loadOwnedOffer(w http.ResponseWriter, r *http.Request, claims *auth.AuthClaims) (*models.Offer, bool) {
+	offerID, err := strconv.ParseUint(mux.Vars(r)["offerID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid offer ID"))
+		return nil, false
+	}
+
+	offer, err := h.Store.Offers().Get(uint(offerID))
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("offer not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch offer"))
 		}
+		return nil, false
+	}
+
+	if !auth.CanEditOffer(claims, offer) {
+		utils.WriteError(w, http.StatusForbidden, errors.New("only the tender who created this offer can manage it"))
+		return nil, false
+	}
+
+	return offer, true
+}
+
+// GetOfferExperts lists the experts assigned to review the proposals of an offer
+func (h *TenderHandler) GetOfferExperts(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	offer, ok := h.loadOwnedOffer(w, r, claims)
+	if !ok {
+		return
+	}
+
+	experts, err := h.Store.Offers().ListExperts(offer.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch the offer experts"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"experts": experts,
+	})
+}
 
-		result = tx.Create(&documentPayload)
-		if result.Error != nil {
-			tx.Rollback()
-			utils.WriteError(w, http.StatusInternalServerError, result.Error)
-			return
+// PostOfferExpert assigns an expert to review the proposals of an offer. Only assigned experts
+// can see and evaluate the proposals.
+func (h *TenderHandler) PostOfferExpert(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		ExpertID uint `json:"expertID"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil || payload.ExpertID == 0 {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("an expertID is required"))
+		return
+	}
+
+	offer, ok := h.loadOwnedOffer(w, r, claims)
+	if !ok {
+		return
+	}
+
+	if status := offer.EffectiveStatus(h.Clock.Now()); status == models.OfferStatusWinnerDeclared || status == models.OfferStatusClosed {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("experts can no longer be assigned (offer is in %s stage)", status))
+		return
+	}
+
+	expert, err := h.Store.Users().Get(payload.ExpertID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("expert not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch expert"))
 		}
+		return
+	}
+
+	if !expert.IsActive || !auth.RolesGrant(expert.Roles, models.PermEvaluationSubmit) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("this user is not an active expert"))
+		return
 	}
 
-	// Commit the transaction
-	result = tx.Commit()
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	if expert.ID == offer.CreatedBy {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("the tender of an offer cannot review its proposals"))
 		return
 	}
 
-	// Fetch the complete offer with documents
-	var completeOffer models.Offer
-	result = db.DB.DB.Preload("Documents").First(&completeOffer, offerPayload.ID)
-	if result.Error != nil {
-		utils.WriteError(w, http.StatusInternalServerError, result.Error)
+	assignment := models.OfferExpert{
+		OfferID:    offer.ID,
+		ExpertID:   expert.ID,
+		AssignedBy: claims.UserID,
+	}
+	if err := h.Store.Offers().AssignExpert(&assignment); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to assign the expert"))
 		return
 	}
 
+	audit.SetTarget(r, "Offer", offer.ID)
+	audit.SetChange(r, nil, assignment)
+
 	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
-		"message": "Offer created successfully",
-		"offer":   completeOffer,
+		"message": "Expert assigned successfully",
+	})
+}
+
+// DeleteOfferExpert removes an expert from the reviewers of an offer. Evaluations they already
+// submitted are kept, like on chain.
+func (h *TenderHandler) DeleteOfferExpert(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	expertID, err := strconv.ParseUint(mux.Vars(r)["expertID"], 10, 64)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid expert ID"))
+		return
+	}
+
+	offer, ok := h.loadOwnedOffer(w, r, claims)
+	if !ok {
+		return
+	}
+
+	if err := h.Store.Offers().UnassignExpert(offer.ID, uint(expertID)); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("this expert is not assigned to the offer"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to unassign the expert"))
+		}
+		return
+	}
+
+	audit.SetTarget(r, "Offer", offer.ID)
+	audit.SetChange(r, models.OfferExpert{OfferID: offer.ID, ExpertID: uint(expertID)}, nil)
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message": "Expert unassigned successfully",
+	})
+}
+
+// PostOfferWinner records the winner of an offer once its WinnerDeclared transaction is verified on chain.
+func (h *TenderHandler) PostOfferWinner(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		TxHash string `json:"txHash"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	if !middleware.IsValidTxHash(payload.TxHash) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("a valid txHash of the declareWinner transaction is required"))
+		return
+	}
+
+	offer, ok := h.loadOwnedOffer(w, r, claims)
+	if !ok {
+		return
+	}
+
+	if offer.Status == models.OfferStatusWinnerDeclared || offer.Status == models.OfferStatusClosed {
+		utils.WriteError(w, http.StatusConflict, errors.New("winner already declared"))
+		return
+	}
+
+	// The contract only declares a winner once the review period is over
+	if status := offer.EffectiveStatus(h.Clock.Now()); status != models.OfferStatusEnded {
+		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("a winner can only be declared once the review period has ended (offer is in %s stage)", status))
+		return
+	}
+
+	event, err := h.Chain.VerifyWinnerDeclared(payload.TxHash, offer.ContractAddress)
+	if err != nil {
+		writeChainError(w, err)
+		return
+	}
+
+	before := *offer
+	winningProposal, err := h.Store.Offers().DeclareWinner(offer, event.Entrepreneur.Hex(), payload.TxHash)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to record the offer winner"))
+		return
+	}
+
+	h.auditOfferChange(r, before, payload.TxHash)
+
+	h.trackTransaction(blockchain.TxRef{
+		Hash:       payload.TxHash,
+		Kind:       models.ChainTxWinnerDeclared,
+		To:         offer.ContractAddress,
+		UserID:     &claims.UserID,
+		EntityType: "Offer",
+		EntityID:   &offer.ID,
+	})
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":         "Winner declared successfully",
+		"winningProposal": winningProposal,
+	})
+}
+
+// auditOfferChange records how an offer changed from before for the audit trail
+func (h *TenderHandler) auditOfferChange(r *http.Request, before models.Offer, txHash string) {
+	audit.SetTarget(r, "Offer", before.ID)
+	audit.SetTxHash(r, txHash)
+
+	after, err := h.Store.Offers().Get(before.ID)
+	if err != nil {
+		log.Printf("Failed to reload offer %d for the audit trail: %v", before.ID, err)
+		return
+	}
+	audit.SetChange(r, before, after)
+}
+
+// PostCloseOffer closes an offer once its OfferClosedEvent transaction is verified on chain.
+func (h *TenderHandler) PostCloseOffer(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		TxHash string `json:"txHash"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	if !middleware.IsValidTxHash(payload.TxHash) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("a valid txHash of the closeOffer transaction is required"))
+		return
+	}
+
+	offer, ok := h.loadOwnedOffer(w, r, claims)
+	if !ok {
+		return
+	}
+
+	if offer.Status == models.OfferStatusClosed {
+		utils.WriteError(w, http.StatusConflict, errors.New("this offer is already closed"))
+		return
+	}
+
+	if _, err := h.Chain.VerifyOfferClosed(payload.TxHash, offer.ContractAddress); err != nil {
+		writeChainError(w, err)
+		return
+	}
+
+	// closeOffer() declares the winner in the same transaction once the review period is over
+	winnerWallet := ""
+	winner, err := h.Chain.VerifyWinnerDeclared(payload.TxHash, offer.ContractAddress)
+	switch {
+	case err == nil:
+		winnerWallet = winner.Entrepreneur.Hex()
+	case !errors.Is(err, blockchain.ErrEventNotFound):
+		writeChainError(w, err)
+		return
+	}
+
+	before := *offer
+	winningProposal, err := h.Store.Offers().Close(offer, winnerWallet, payload.TxHash)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to close the offer"))
+		return
+	}
+
+	h.auditOfferChange(r, before, payload.TxHash)
+
+	h.trackTransaction(blockchain.TxRef{
+		Hash:       payload.TxHash,
+		Kind:       models.ChainTxOfferClosed,
+		To:         offer.ContractAddress,
+		UserID:     &claims.UserID,
+		EntityType: "Offer",
+		EntityID:   &offer.ID,
+	})
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":         "Offer closed successfully",
+		"winningProposal": winningProposal,
 	})
 }
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"math/big"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/blockchain"
+	"github.com/Brondont/trust-api/models"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func winnerOf(user models.User) *blockchain.WinnerDeclaredEvent {
+	return &blockchain.WinnerDeclaredEvent{
+		Entrepreneur: common.HexToAddress(user.PublicWalletAddress),
+		TotalScore:   big.NewInt(9),
+		Price:        big.NewInt(125000),
+		BlockNumber:  60,
+	}
+}
+
+// offerRequest is a request of user on the test offer carrying testTxHash
+func offerRequest(t *testing.T, f *testFixture, user models.User) *http.Request {
+	t.Helper()
+	vars := map[string]string{"offerID": strconv.FormatUint(uint64(f.offer.ID), 10)}
+	return jsonRequest(t, claimsFor(user, models.PermOfferManage), vars, map[string]string{"txHash": testTxHash})
+}
+
+// proposalStatuses returns the status of every proposal of the test offer by proposer
+func proposalStatuses(t *testing.T, f *testFixture) map[uint]string {
+	t.Helper()
+	statuses := map[uint]string{}
+	for _, user := range []models.User{f.entrepreneur, f.competitor} {
+		proposal, err := f.store.Proposals().GetByWallet(f.offer.ID, user.PublicWalletAddress)
+		if err != nil {
+			t.Fatalf("loading proposal of user %d: %v", user.ID, err)
+		}
+		statuses[user.ID] = proposal.Status
+	}
+	return statuses
+}
+
+func TestPostOfferWinner(t *testing.T) {
+	after := reviewEnd.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		now        time.Time
+		status     string // persisted status of the offer, NotStarted when empty
+		caller     func(f *testFixture) models.User
+		noEvent    bool
+		wantStatus int
+	}{
+		{name: "declared once the review ended", now: after, wantStatus: http.StatusOK},
+		{name: "during the review period", now: reviewStart.Add(time.Hour), wantStatus: http.StatusForbidden},
+		{name: "winner already declared", now: after, status: models.OfferStatusWinnerDeclared, wantStatus: http.StatusConflict},
+		{name: "offer already closed", now: after, status: models.OfferStatusClosed, wantStatus: http.StatusConflict},
+		{
+			name:       "caller is not the tender of the offer",
+			now:        after,
+			caller:     func(f *testFixture) models.User { return f.competitor },
+			wantStatus: http.StatusForbidden,
+		},
+		{name: "transaction declaring no winner", now: after, noEvent: true, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFixture(t)
+			f.addProposal(t, f.entrepreneur, testTxHash)
+			f.addProposal(t, f.competitor, otherTxHash)
+			if tt.status != "" {
+				f.setStatus(t, tt.status)
+			}
+			caller := f.tender
+			if tt.caller != nil {
+				caller = tt.caller(f)
+			}
+			if !tt.noEvent {
+				f.chain.winner = winnerOf(f.entrepreneur)
+			}
+
+			h := NewTenderHandler(f.handler(tt.now))
+			body := serve(t, h.PostOfferWinner, offerRequest(t, f, caller), tt.wantStatus)
+
+			offer, err := f.store.Offers().Get(f.offer.ID)
+			if err != nil {
+				t.Fatalf("loading offer: %v", err)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if offer.WinnerTxHash != "" {
+					t.Errorf("winner recorded from %s", offer.WinnerTxHash)
+				}
+				return
+			}
+
+			if offer.Status != models.OfferStatusWinnerDeclared || offer.WinnerTxHash != testTxHash {
+				t.Errorf("got offer %s with winner transaction %q, want %s with %s", offer.Status, offer.WinnerTxHash, models.OfferStatusWinnerDeclared, testTxHash)
+			}
+			if body["winningProposal"] == nil {
+				t.Error("the winning proposal is not returned")
+			}
+			statuses := proposalStatuses(t, f)
+			if statuses[f.entrepreneur.ID] != models.ProposalStatusAccepted || statuses[f.competitor.ID] != models.ProposalStatusRejected {
+				t.Errorf("got proposal statuses %v, want the entrepreneur accepted and the competitor rejected", statuses)
+			}
+			if len(f.chain.tracked) != 1 || f.chain.tracked[0].Kind != models.ChainTxWinnerDeclared {
+				t.Errorf("got tracked transactions %+v, want the declareWinner one", f.chain.tracked)
+			}
+		})
+	}
+}
+
+func TestPostCloseOffer(t *testing.T) {
+	tests := []struct {
+		name       string
+		now        time.Time
+		status     string
+		caller     func(f *testFixture) models.User
+		closed     bool
+		winner     bool
+		wantStatus int
+		wantWinner bool
+	}{
+		{name: "closed once the review ended", now: reviewEnd.Add(time.Hour), closed: true, winner: true, wantStatus: http.StatusOK, wantWinner: true},
+		{name: "closed during the submission period", now: submissionStart.Add(time.Hour), closed: true, wantStatus: http.StatusOK},
+		{name: "offer already closed", now: reviewEnd.Add(time.Hour), status: models.OfferStatusClosed, closed: true, wantStatus: http.StatusConflict},
+		{
+			name:       "caller is not the tender of the offer",
+			now:        reviewEnd.Add(time.Hour),
+			caller:     func(f *testFixture) models.User { return f.competitor },
+			closed:     true,
+			wantStatus: http.StatusForbidden,
+		},
+		{name: "transaction closing nothing", now: reviewEnd.Add(time.Hour), wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFixture(t)
+			f.addProposal(t, f.entrepreneur, testTxHash)
+			f.addProposal(t, f.competitor, otherTxHash)
+			if tt.status != "" {
+				f.setStatus(t, tt.status)
+			}
+			caller := f.tender
+			if tt.caller != nil {
+				caller = tt.caller(f)
+			}
+			if tt.closed {
+				f.chain.closed = &blockchain.OfferClosedEvent{BlockNumber: 70}
+			}
+			if tt.winner {
+				f.chain.winner = winnerOf(f.entrepreneur)
+			}
+
+			h := NewTenderHandler(f.handler(tt.now))
+			serve(t, h.PostCloseOffer, offerRequest(t, f, caller), tt.wantStatus)
+
+			offer, err := f.store.Offers().Get(f.offer.ID)
+			if err != nil {
+				t.Fatalf("loading offer: %v", err)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if offer.CloseTxHash != "" {
+					t.Errorf("close recorded from %s", offer.CloseTxHash)
+				}
+				return
+			}
+
+			if offer.Status != models.OfferStatusClosed || offer.CloseTxHash != testTxHash {
+				t.Errorf("got offer %s with close transaction %q, want %s with %s", offer.Status, offer.CloseTxHash, models.OfferStatusClosed, testTxHash)
+			}
+			if (offer.WinningProposalID != nil) != tt.wantWinner {
+				t.Errorf("winner recorded: %t, want %t", offer.WinningProposalID != nil, tt.wantWinner)
+			}
+			statuses := proposalStatuses(t, f)
+			wantEntrepreneur, wantCompetitor := models.ProposalStatusPending, models.ProposalStatusPending
+			if tt.wantWinner {
+				wantEntrepreneur, wantCompetitor = models.ProposalStatusAccepted, models.ProposalStatusRejected
+			}
+			if statuses[f.entrepreneur.ID] != wantEntrepreneur || statuses[f.competitor.ID] != wantCompetitor {
+				t.Errorf("got proposal statuses %v, want %s and %s", statuses, wantEntrepreneur, wantCompetitor)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Brondont/trust-api/internal/auth"
+	"github.com/Brondont/trust-api/middleware"
+	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
+	"github.com/Brondont/trust-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// TransactionHandler serves the chain transactions followed by the transaction watcher
+type TransactionHandler struct {
+	*Handler
+}
+
+func NewTransactionHandler(h *Handler) *TransactionHandler {
+	return &TransactionHandler{
+		Handler: h,
+	}
+}
+
+// GetTransactions returns a page of tracked transactions, newest first. Users see the transactions
+// they sent or reported, users with transaction:read see every transaction and may filter by user.
+func (h *TransactionHandler) GetTransactions(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+	query := r.URL.Query()
+
+	filter := store.ChainTransactionFilter{
+		Kind:       query.Get("kind"),
+		Status:     query.Get("status"),
+		EntityType: query.Get("entityType"),
+	}
+
+	if value := query.Get("entityID"); value != "" {
+		entityID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid entityID"))
+			return
+		}
+		id := uint(entityID)
+		filter.EntityID = &id
+	}
+
+	if auth.HasPermission(claims, models.PermTransactionRead) {
+		if value := query.Get("userID"); value != "" {
+			userID, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				utils.WriteError(w, http.StatusBadRequest, errors.New("invalid userID"))
+				return
+			}
+			id := uint(userID)
+			filter.UserID = &id
+		}
+	} else {
+		filter.UserID = &claims.UserID
+	}
+
+	page := store.PageFromQuery(query)
+
+	transactions, total, err := h.Store.ChainTransactions().List(filter, page)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch transactions"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"transactions": transactions,
+		"pagination":   store.NewPageInfo(page, total),
+	})
+}
+
+// GetTransaction returns a tracked transaction by hash, to the user it belongs to or a user with transaction:read
+func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	hash := mux.Vars(r)["hash"]
+	if !middleware.IsValidTxHash(hash) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid transaction hash"))
+		return
+	}
+
+	transaction, err := h.Store.ChainTransactions().GetByHash(hash)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			utils.WriteError(w, http.StatusNotFound, errors.New("transaction not found"))
+		} else {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to fetch transaction"))
+		}
+		return
+	}
+
+	if !auth.CanViewTransaction(claims, transaction.UserID) {
+		utils.WriteError(w, http.StatusNotFound, errors.New("transaction not found"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"transaction": transaction,
+	})
+}
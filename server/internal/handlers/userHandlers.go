@@ -7,29 +7,22 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/Brondont/trust-api/db"
 	"github.com/Brondont/trust-api/internal/auth"
 	"github.com/Brondont/trust-api/middleware"
 	"github.com/Brondont/trust-api/models"
+	"github.com/Brondont/trust-api/store"
 	"github.com/Brondont/trust-api/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/gorilla/mux"
-	"gorm.io/gorm"
 )
 
-type Handler struct{}
-
-func NewHandler() *Handler {
-	return &Handler{}
-}
-
 type UserHandler struct {
 	*Handler
 }
 
-func NewUserHandler() *UserHandler {
+func NewUserHandler(h *Handler) *UserHandler {
 	return &UserHandler{
-		Handler: NewHandler(),
+		Handler: h,
 	}
 }
 
@@ -56,21 +49,15 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	}
 	userIDuint := uint(userIDInt)
 
-	// Check if the caller is an admin or is the same user.
-	// Note: We allow access if the user is an admin OR the token's userID equals the requested userID.
-	if !auth.HasRole(claims, []string{"admin"}) && claims.UserID != userIDuint {
+	if !auth.CanViewUser(claims, userIDuint) {
 		utils.WriteError(w, http.StatusForbidden, errors.New("insufficient permissions"))
 		return
 	}
 
 	// Fetch user from database
-	var user models.User
-	result := db.DB.DB.Preload("Roles").
-		Select("id", "email", "first_name", "last_name", "phone_number", "public_wallet_address").
-		Where("id = ?", userID).
-		First(&user)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+	user, err := h.Store.Users().Profile(userIDuint)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
 			utils.WriteError(w, http.StatusNotFound, errors.New("user not found"))
 			return
 		}
@@ -118,8 +105,12 @@ func (h *UserHandler) UpdateEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Check if email is already in use
-	var existingUser models.User
-	if err := db.DB.DB.Where("email = ?", payload.Email).First(&existingUser).Error; err == nil {
+	taken, err := h.Store.Users().EmailTaken(payload.Email, claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to update email"))
+		return
+	}
+	if taken {
 		err := middleware.InputValidationError{
 			Type:  "invalid",
 			Value: payload.Email,
@@ -131,7 +122,7 @@ func (h *UserHandler) UpdateEmail(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update user's email
-	if err := db.DB.DB.Model(&models.User{}).Where("id = ?", claims.UserID).Update("email", payload.Email).Error; err != nil {
+	if err := h.Store.Users().SetEmail(claims.UserID, payload.Email); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to update email"))
 		return
 	}
@@ -161,7 +152,7 @@ func (h *UserHandler) UpdatePhoneNumber(w http.ResponseWriter, r *http.Request)
 	payload.PhoneNumber = strings.TrimSpace(payload.PhoneNumber)
 
 	// Update user's phone number
-	if err := db.DB.DB.Model(&models.User{}).Where("id = ?", claims.UserID).Update("phone_number", payload.PhoneNumber).Error; err != nil {
+	if err := h.Store.Users().SetPhoneNumber(claims.UserID, payload.PhoneNumber); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to update phone number"))
 		return
 	}
@@ -169,6 +160,47 @@ func (h *UserHandler) UpdatePhoneNumber(w http.ResponseWriter, r *http.Request)
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{"message": "Phone number updated successfully"})
 }
 
+// GetWalletChallenge issues the message the user signs with personal_sign to prove they control a wallet.
+func (h *UserHandler) GetWalletChallenge(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	address := strings.TrimSpace(r.URL.Query().Get("address"))
+	if !common.IsHexAddress(address) {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid Ethereum wallet address"))
+		return
+	}
+
+	challenge, err := auth.CreateWalletChallenge(h.Store.WalletChallenges(), common.HexToAddress(address), auth.ChallengePurposeWalletBind, &claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to issue wallet challenge, try again"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"nonce":     challenge.Nonce,
+		"expiresAt": challenge.ExpiresAt,
+		"message":   auth.WalletBindMessage(challenge),
+	})
+}
+
+// writeWalletProofError maps a wallet ownership proof error to an HTTP response
+func writeWalletProofError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidNonce),
+		errors.Is(err, auth.ErrInvalidSignature),
+		errors.Is(err, auth.ErrSignerMismatch):
+		utils.WriteError(w, http.StatusUnauthorized, err)
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to verify wallet ownership, try again"))
+	}
+}
+
+// UpdateWallet binds a wallet to the account once the user proved they control it.
+// A bound wallet can only be changed through an admin-approved wallet change request.
 func (h *UserHandler) UpdateWallet(w http.ResponseWriter, r *http.Request) {
 	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
 	if !ok {
@@ -178,6 +210,8 @@ func (h *UserHandler) UpdateWallet(w http.ResponseWriter, r *http.Request) {
 
 	var payload struct {
 		PublicWalletAddress string `json:"publicWalletAddress"`
+		Nonce               string `json:"nonce"`
+		Signature           string `json:"signature"`
 	}
 
 	if err := utils.ParseJson(r, &payload); err != nil {
@@ -198,9 +232,14 @@ func (h *UserHandler) UpdateWallet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if payload.Nonce == "" || payload.Signature == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("a signed wallet challenge is required to link a wallet"))
+		return
+	}
+
 	// Check if the user already has a wallet
-	var existingUser models.User
-	if err := db.DB.DB.Where("id = ?", claims.UserID).First(&existingUser).Error; err != nil {
+	existingUser, err := h.Store.Users().Get(claims.UserID)
+	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while fetching user data"))
 		return
 	}
@@ -210,12 +249,175 @@ func (h *UserHandler) UpdateWallet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	existingUser.PublicWalletAddress = payload.PublicWalletAddress
+	wallet := common.HexToAddress(payload.PublicWalletAddress)
+	if taken, err := h.walletInUse(wallet, existingUser.ID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while checking the wallet"))
+		return
+	} else if taken {
+		utils.WriteError(w, http.StatusConflict, errors.New("this wallet is already linked to another account"))
+		return
+	}
+
+	// The wallet inherits on-chain roles, so the caller must prove they control it
+	if err := auth.VerifyWalletBind(h.Store.WalletChallenges(), payload.Nonce, wallet, existingUser.ID, payload.Signature); err != nil {
+		writeWalletProofError(w, err)
+		return
+	}
 
-	if err := db.DB.DB.Save(&existingUser).Error; err != nil {
+	if err := h.Store.Users().SetWallet(existingUser.ID, wallet.Hex()); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to update wallet address"))
 		return
 	}
 
 	utils.WriteJson(w, http.StatusOK, map[string]interface{}{"message": "wallet address updated successfully"})
 }
+
+// walletInUse reports whether the wallet is bound to an account other than userID
+func (h *Handler) walletInUse(wallet common.Address, userID uint) (bool, error) {
+	return h.Store.Users().WalletInUse(wallet.Hex(), userID)
+}
+
+// PostWalletChangeRequest asks an admin to rotate the bound wallet to a new one or to unbind it.
+// For a rotation the user must already prove they control the new wallet.
+func (h *UserHandler) PostWalletChangeRequest(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	var payload struct {
+		Action     string `json:"action"`
+		NewAddress string `json:"newAddress"`
+		Nonce      string `json:"nonce"`
+		Signature  string `json:"signature"`
+		Reason     string `json:"reason"`
+	}
+
+	if err := utils.ParseJson(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("invalid request format"))
+		return
+	}
+
+	payload.NewAddress = strings.TrimSpace(payload.NewAddress)
+	payload.Reason = strings.TrimSpace(payload.Reason)
+
+	if payload.Action != models.WalletChangeRotate && payload.Action != models.WalletChangeUnbind {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("action must be %s or %s", models.WalletChangeRotate, models.WalletChangeUnbind))
+		return
+	}
+
+	user, err := h.Store.Users().Get(claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while fetching user data"))
+		return
+	}
+
+	if user.PublicWalletAddress == "" {
+		utils.WriteError(w, http.StatusBadRequest, errors.New("no wallet is linked to this account"))
+		return
+	}
+
+	// One open request at a time
+	pending, err := h.Store.WalletChangeRequests().HasPending(user.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if pending {
+		utils.WriteError(w, http.StatusConflict, errors.New("a wallet change request is already pending review"))
+		return
+	}
+
+	request := models.WalletChangeRequest{
+		UserID:     user.ID,
+		Action:     payload.Action,
+		OldAddress: user.PublicWalletAddress,
+		Reason:     payload.Reason,
+		Status:     models.WalletChangePending,
+	}
+
+	if payload.Action == models.WalletChangeRotate {
+		if !common.IsHexAddress(payload.NewAddress) {
+			utils.WriteError(w, http.StatusBadRequest, errors.New("invalid Ethereum wallet address"))
+			return
+		}
+
+		newWallet := common.HexToAddress(payload.NewAddress)
+		if strings.EqualFold(newWallet.Hex(), user.PublicWalletAddress) {
+			utils.WriteError(w, http.StatusBadRequest, errors.New("the new wallet is the one already linked"))
+			return
+		}
+
+		if taken, err := h.walletInUse(newWallet, user.ID); err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, errors.New("something went wrong while checking the wallet"))
+			return
+		} else if taken {
+			utils.WriteError(w, http.StatusConflict, errors.New("this wallet is already linked to another account"))
+			return
+		}
+
+		if err := auth.VerifyWalletBind(h.Store.WalletChallenges(), payload.Nonce, newWallet, user.ID, payload.Signature); err != nil {
+			writeWalletProofError(w, err)
+			return
+		}
+
+		request.NewAddress = newWallet.Hex()
+	}
+
+	if err := h.Store.WalletChangeRequests().Create(&request); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to save wallet change request"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusCreated, map[string]interface{}{
+		"message": "wallet change request submitted for review",
+		"request": request,
+	})
+}
+
+// PostLogout ends the session of the calling token and revokes the token itself.
+func (h *UserHandler) PostLogout(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	if err := auth.RevokeToken(h.Store.AuthSessions(), claims); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to log out, try again"))
+		return
+	}
+
+	if err := auth.RevokeSession(h.Store.AuthSessions(), claims.SessionID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to log out, try again"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{"message": "logged out successfully"})
+}
+
+// PostLogoutAll ends every session of the calling user, on every device.
+func (h *UserHandler) PostLogoutAll(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value("claims").(*auth.AuthClaims)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, errors.New("unable to retrieve authentication claims"))
+		return
+	}
+
+	if err := auth.RevokeToken(h.Store.AuthSessions(), claims); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to log out, try again"))
+		return
+	}
+
+	revoked, err := auth.RevokeUserSessions(h.Store.AuthSessions(), claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, errors.New("failed to log out, try again"))
+		return
+	}
+
+	utils.WriteJson(w, http.StatusOK, map[string]interface{}{
+		"message":         "logged out of all sessions successfully",
+		"revokedSessions": revoked,
+	})
+}
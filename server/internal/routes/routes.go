@@ -4,53 +4,115 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/Brondont/trust-api/internal/audit"
 	"github.com/Brondont/trust-api/internal/auth"
 	"github.com/Brondont/trust-api/internal/handlers"
+	"github.com/Brondont/trust-api/models"
 	"github.com/gorilla/mux"
 )
 
-func SetupRoutes(router *mux.Router) {
-	adminHandler := handlers.NewAdminHandler()
-	generalHandler := handlers.NewGeneralHandler()
-	userHandler := handlers.NewUserHandler()
-	tenderHandler := handlers.NewTenderHandler()
-	entrepreneurHandler := handlers.NewEntrepreneurHandler()
+func SetupRoutes(router *mux.Router, h *handlers.Handler) {
+	adminHandler := handlers.NewAdminHandler(h)
+	generalHandler := handlers.NewGeneralHandler(h)
+	userHandler := handlers.NewUserHandler(h)
+	tenderHandler := handlers.NewTenderHandler(h)
+	entrepreneurHandler := handlers.NewEntrepreneurHandler(h)
+	expertHandler := handlers.NewExpertHandler(h)
+	relayerHandler := handlers.NewRelayerHandler(h)
+	transactionHandler := handlers.NewTransactionHandler(h)
+	proposalHandler := handlers.NewProposalHandler(h)
+	documentHandler := handlers.NewDocumentHandler(h)
 
-	// General Routes (accessible without role restrictions)
+	// Admin and tender actions and evaluations leave an audit event, see internal/audit
+	recorder := audit.NewRecorder(h.Store.AuditEvents())
+	guard := auth.NewGuard(h.Store, h.Config)
+
+	// Nonces are issued without authentication, each one is stored until it expires
+	nonceLimiter, err := auth.NewSIWENonceLimiter(h.Config)
+	if err != nil {
+		log.Fatalf("Failed to configure the SIWE nonce rate limit: %v", err)
+	}
+
+	// General Routes (accessible without authentication)
 	router.HandleFunc("/user-profile/{userID}", generalHandler.GetUserProfile).Methods("GET")
 	router.HandleFunc("/user/activate", generalHandler.ActivateUser).Methods("PUT")
 	router.HandleFunc("/user/forgot-password", generalHandler.ForgotPassword).Methods("POST")
 	router.HandleFunc("/user/reset-password", generalHandler.ResetPassword).Methods("PUT")
 	router.HandleFunc("/login", generalHandler.PostLogin).Methods("POST")
+	router.HandleFunc("/token/refresh", generalHandler.PostRefreshToken).Methods("POST")
+	router.HandleFunc("/siwe/nonce", nonceLimiter.Limit(generalHandler.GetSIWENonce)).Methods("GET")
+	router.HandleFunc("/siwe/verify", generalHandler.PostSIWEVerify).Methods("POST")
 	router.HandleFunc("/sectors", generalHandler.GetSectors).Methods("GET")
 	router.HandleFunc("/offer/{offerID}", generalHandler.GetOffer).Methods("GET")
 	router.HandleFunc("/offers", generalHandler.GetOffers).Methods("GET")
 
-	// User routes that require authentication only without a role
-	router.HandleFunc("/user/{userID}", auth.RequireRole(userHandler.GetUser)).Methods("GET")
-	router.HandleFunc("/user/email", auth.RequireRole(userHandler.UpdateEmail)).Methods("PUT")
-	router.HandleFunc("/user/phone-number", auth.RequireRole(userHandler.UpdatePhoneNumber)).Methods("PUT")
-	router.HandleFunc("/user/wallet", auth.RequireRole(userHandler.UpdateWallet)).Methods("PUT")
+	// User routes that require authentication only without a permission
+	router.HandleFunc("/user/{userID}", guard.RequireRole(userHandler.GetUser)).Methods("GET")
+	router.HandleFunc("/user/email", guard.RequireRole(userHandler.UpdateEmail)).Methods("PUT")
+	router.HandleFunc("/user/phone-number", guard.RequireRole(userHandler.UpdatePhoneNumber)).Methods("PUT")
+	router.HandleFunc("/user/wallet", guard.RequireRole(userHandler.UpdateWallet)).Methods("PUT")
+	router.HandleFunc("/user/wallet/challenge", guard.RequireRole(userHandler.GetWalletChallenge)).Methods("GET")
+	router.HandleFunc("/user/wallet/change-request", guard.RequireRole(userHandler.PostWalletChangeRequest)).Methods("POST")
+	router.HandleFunc("/logout", guard.RequireRole(userHandler.PostLogout)).Methods("POST")
+	router.HandleFunc("/logout-all", guard.RequireRole(userHandler.PostLogoutAll)).Methods("POST")
+	router.HandleFunc("/transactions", guard.RequireRole(transactionHandler.GetTransactions)).Methods("GET")
+	router.HandleFunc("/transactions/{hash}", guard.RequireRole(transactionHandler.GetTransaction)).Methods("GET")
+	router.HandleFunc("/proposal/{proposalID}", guard.RequireRole(proposalHandler.GetProposal)).Methods("GET")
+	router.HandleFunc("/documents/{documentID}", guard.RequireRole(documentHandler.GetDocument)).Methods("GET")
+	router.HandleFunc("/offer/{offerID}/chain", guard.RequireRole(generalHandler.GetOfferChain)).Methods("GET")
+
+	// Admin Routes, guarded by the permissions the built-in "admin" role grants
+	router.HandleFunc("/user/{userID}", guard.RequirePermission(recorder.Audited("user.update", adminHandler.PutUser), models.PermUserManage)).Methods("PUT")
+	router.HandleFunc("/user/{userID}/roles", guard.RequirePermission(recorder.Audited("user.role.grant", adminHandler.PostUserRole), models.PermUserManage)).Methods("POST")
+	router.HandleFunc("/user/{userID}/roles/{roleID}", guard.RequirePermission(recorder.Audited("user.role.revoke", adminHandler.DeleteUserRole), models.PermUserManage)).Methods("DELETE")
+	router.HandleFunc("/user/{userID}/sessions", guard.RequirePermission(recorder.Audited("user.sessions.revoke", adminHandler.DeleteUserSessions), models.PermUserManage)).Methods("DELETE")
+	router.HandleFunc("/user", guard.RequirePermission(recorder.Audited("user.create", adminHandler.PostUser), models.PermUserManage)).Methods("POST")
+	router.HandleFunc("/users", guard.RequirePermission(adminHandler.GetUsers, models.PermUserManage)).Methods("GET")
+	router.HandleFunc("/users/{userID}", guard.RequirePermission(recorder.Audited("user.delete", adminHandler.DeleteUser), models.PermUserManage)).Methods("DELETE")
+
+	router.HandleFunc("/wallet-change-requests", guard.RequirePermission(adminHandler.GetWalletChangeRequests, models.PermUserManage)).Methods("GET")
+	router.HandleFunc("/wallet-change-requests/{requestID}", guard.RequirePermission(recorder.Audited("wallet_change.review", adminHandler.PutWalletChangeRequest), models.PermUserManage)).Methods("PUT")
 
-	// Admin Routes (require "admin" role)
-	router.HandleFunc("/user/{userID}", auth.RequireRole(adminHandler.PutUser, "admin")).Methods("PUT")
-	router.HandleFunc("/user/{userID}/roles", auth.RequireRole(adminHandler.PostUserRole, "admin")).Methods("POST")
-	router.HandleFunc("/user/{userID}/roles/{roleID}", auth.RequireRole(adminHandler.DeleteUserRole, "admin")).Methods("DELETE")
-	router.HandleFunc("/user", auth.RequireRole(adminHandler.PostUser, "admin")).Methods("POST")
-	router.HandleFunc("/users", auth.RequireRole(adminHandler.GetUsers, "admin")).Methods("GET")
-	router.HandleFunc("/users/{userID}", auth.RequireRole(adminHandler.DeleteUser, "admin")).Methods("DELETE")
+	router.HandleFunc("/roles", guard.RequirePermission(adminHandler.GetRoles, models.PermRoleManage)).Methods("GET")
+	router.HandleFunc("/roles/reconciliation", guard.RequirePermission(adminHandler.GetRoleReconciliation, models.PermRoleManage)).Methods("GET")
+	router.HandleFunc("/roles/reconciliation", guard.RequirePermission(recorder.Audited("role.reconcile", adminHandler.PostRoleReconciliation), models.PermRoleManage)).Methods("POST")
+	router.HandleFunc("/roles/audit", guard.RequirePermission(adminHandler.GetRoleAuditLogs, models.PermRoleManage)).Methods("GET")
 
-	router.HandleFunc("/roles", auth.RequireRole(adminHandler.GetRoles, "admin")).Methods("GET")
+	router.HandleFunc("/admin/audit", guard.RequirePermission(adminHandler.GetAuditEvents, models.PermAuditRead)).Methods("GET")
+	router.HandleFunc("/admin/audit/verify", guard.RequirePermission(adminHandler.GetAuditVerification, models.PermAuditRead)).Methods("GET")
+	router.HandleFunc("/admin/audit/export", guard.RequirePermission(recorder.Audited("audit.export", adminHandler.ExportAuditEvents), models.PermAuditRead)).Methods("GET")
 
-	router.HandleFunc("/roles", auth.RequireRole(adminHandler.CreateRole, "admin")).Methods("POST")
-	router.HandleFunc("/roles/{roleName}", auth.RequireRole(adminHandler.UpdateRole, "admin")).Methods("PUT")
-	router.HandleFunc("/roles/{roleName}", auth.RequireRole(adminHandler.DeleteRole, "admin")).Methods("DELETE")
+	router.HandleFunc("/roles", guard.RequirePermission(recorder.Audited("role.create", adminHandler.CreateRole), models.PermRoleManage)).Methods("POST")
+	router.HandleFunc("/roles/{roleName}", guard.RequirePermission(recorder.Audited("role.update", adminHandler.UpdateRole), models.PermRoleManage)).Methods("PUT")
+	router.HandleFunc("/roles/{roleName}", guard.RequirePermission(recorder.Audited("role.delete", adminHandler.DeleteRole), models.PermRoleManage)).Methods("DELETE")
+	router.HandleFunc("/roles/{roleName}/permissions", guard.RequirePermission(recorder.Audited("role.permissions", adminHandler.PutRolePermissions), models.PermRoleManage)).Methods("PUT")
+	router.HandleFunc("/permissions", guard.RequirePermission(adminHandler.GetPermissions, models.PermRoleManage)).Methods("GET")
 
 	// tender routes
-	router.HandleFunc("/tender/offer", auth.RequireRole(tenderHandler.PostOffer, "tender")).Methods("POST")
+	router.HandleFunc("/tender/offer", guard.RequirePermission(recorder.Audited("offer.create", tenderHandler.PostOffer), models.PermOfferCreate)).Methods("POST")
+	router.HandleFunc("/tender/offer/{offerID}/winner", guard.RequirePermission(recorder.Audited("offer.winner", tenderHandler.PostOfferWinner), models.PermOfferManage)).Methods("POST")
+	router.HandleFunc("/tender/offer/{offerID}/close", guard.RequirePermission(recorder.Audited("offer.close", tenderHandler.PostCloseOffer), models.PermOfferManage)).Methods("POST")
+	router.HandleFunc("/tender/offer/{offerID}/experts", guard.RequirePermission(tenderHandler.GetOfferExperts, models.PermOfferManage)).Methods("GET")
+	router.HandleFunc("/tender/offer/{offerID}/experts", guard.RequirePermission(recorder.Audited("offer.expert.assign", tenderHandler.PostOfferExpert), models.PermOfferManage)).Methods("POST")
+	router.HandleFunc("/tender/offer/{offerID}/experts/{expertID}", guard.RequirePermission(recorder.Audited("offer.expert.unassign", tenderHandler.DeleteOfferExpert), models.PermOfferManage)).Methods("DELETE")
 
 	// entrepreneur routes
-	router.HandleFunc("/entrepreneur/proposal", auth.RequireRole(entrepreneurHandler.PostProposal, "entrepreneur")).Methods("POST")
+	router.HandleFunc("/entrepreneur/proposal", guard.RequirePermission(entrepreneurHandler.PostProposal, models.PermProposalSubmit)).Methods("POST")
+
+	// expert routes
+	router.HandleFunc("/expert/proposals", guard.RequirePermission(expertHandler.GetReviewProposals, models.PermEvaluationSubmit)).Methods("GET")
+	router.HandleFunc("/expert/evaluation", guard.RequirePermission(recorder.Audited("evaluation.create", expertHandler.PostEvaluation), models.PermEvaluationSubmit)).Methods("POST")
+	router.HandleFunc("/expert/evaluations", guard.RequirePermission(expertHandler.GetEvaluations, models.PermEvaluationSubmit)).Methods("GET")
+
+	// relayer routes, for users without their own wallet
+	router.HandleFunc("/relayer/wallet", guard.RequireRole(relayerHandler.GetCustodialWallet)).Methods("GET")
+	router.HandleFunc("/relayer/wallet", guard.RequireRole(relayerHandler.PostCustodialWallet)).Methods("POST")
+	router.HandleFunc("/relayer/transactions", guard.RequireRole(relayerHandler.GetRelayedTransactions)).Methods("GET")
+	router.HandleFunc("/relayer/transactions/{txID}", guard.RequireRole(relayerHandler.GetRelayedTransaction)).Methods("GET")
+	router.HandleFunc("/relayer/proposal", guard.RequirePermission(relayerHandler.PostRelayProposal, models.PermProposalSubmit)).Methods("POST")
+	router.HandleFunc("/relayer/review", guard.RequirePermission(relayerHandler.PostRelayReview, models.PermEvaluationSubmit)).Methods("POST")
+	router.HandleFunc("/relayer/offer", guard.RequirePermission(recorder.Audited("relayer.offer", relayerHandler.PostRelayOffer), models.PermOfferCreate)).Methods("POST")
+	router.HandleFunc("/relayer/role", guard.RequirePermission(recorder.Audited("relayer.role", relayerHandler.PostRelayRole), models.PermRoleManage)).Methods("POST")
 }
 
 func SetupStaticRoutes(router *mux.Router) {
@@ -61,12 +123,6 @@ func SetupStaticRoutes(router *mux.Router) {
 	router.PathPrefix("/public/").Handler(http.StripPrefix("/public/", fileServer))
 	log.Println("Public static file server running on /public")
 
-	privateFileServer := http.FileServer(http.Dir("./authenticated/"))
-
-	// For protected static files (authentication required)
-	protectedFileServer := http.StripPrefix("/authenticated/", privateFileServer)
-
-	// This allows any authenticated user to access these files
-	router.PathPrefix("/authenticated/").Handler(auth.RequireRoleHandler(protectedFileServer))
-	log.Println("Protected static file server running on /authenticated")
+	// Uploaded documents in ./authenticated/ are not served as static files, they are downloaded by
+	// ID through /documents/{documentID} which checks the caller may see them
 }
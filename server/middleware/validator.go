@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"math/big"
 	"net/mail"
 	"regexp"
 	"strconv"
@@ -38,6 +39,14 @@ type OfferFormData struct {
 	ProposalReviewEnd       time.Time
 }
 
+// ProposalFormData holds the parsed proposal submission fields
+type ProposalFormData struct {
+	OfferID        uint
+	Details        string
+	Price          *big.Int
+	ProposalTxHash string
+}
+
 type OfferValidationRequest struct {
 	Fields map[string][]string
 }
@@ -171,7 +180,7 @@ func ValidateOfferForm(formData map[string][]string) (OfferFormData, []InputVali
 	result := OfferFormData{}
 
 	// Validate required fields
-	requiredFields := []string{"contractAddress", "title", "budget", "currency", "sectorID", "location",
+	requiredFields := []string{"contractAddress", "title", "tenderNumber", "budget", "currency", "sectorID", "location",
 		"proposalSubmissionStart", "proposalSubmissionEnd", "proposalReviewStart", "proposalReviewEnd"}
 
 	for _, field := range requiredFields {
@@ -190,9 +199,18 @@ func ValidateOfferForm(formData map[string][]string) (OfferFormData, []InputVali
 
 	// Parse and validate individual fields
 	result.ContractAddress = formData["contractAddress"][0]
+	if !regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`).MatchString(result.ContractAddress) {
+		errors = append(errors, InputValidationError{
+			Type:  "invalid",
+			Value: result.ContractAddress,
+			Msg:   "invalid contract address",
+			Path:  "contractAddress",
+		})
+	}
 	result.Title = formData["title"][0]
 	result.Currency = formData["currency"][0]
 	result.Location = formData["location"][0]
+	result.TenderNumber = formData["tenderNumber"][0]
 
 	// Parse budget
 	if budget, err := strconv.ParseFloat(formData["budget"][0], 64); err != nil {
@@ -219,9 +237,6 @@ func ValidateOfferForm(formData map[string][]string) (OfferFormData, []InputVali
 	}
 
 	// Parse optional fields
-	if len(formData["tenderNumber"]) > 0 {
-		result.TenderNumber = formData["tenderNumber"][0]
-	}
 	if len(formData["description"]) > 0 {
 		result.Description = formData["description"][0]
 	}
@@ -308,3 +323,69 @@ func ValidateOfferForm(formData map[string][]string) (OfferFormData, []InputVali
 
 	return result, errors
 }
+
+// IsValidTxHash checks if a string is a 0x-prefixed 32 byte transaction hash.
+func IsValidTxHash(txHash string) bool {
+	re := regexp.MustCompile(`^0x[0-9a-fA-F]{64}$`)
+	return re.MatchString(txHash)
+}
+
+func ValidateProposalForm(formData map[string][]string) (ProposalFormData, []InputValidationError) {
+	var errors []InputValidationError
+	result := ProposalFormData{}
+
+	// Validate required fields
+	requiredFields := []string{"offerID", "details", "price", "proposalTxHash"}
+
+	for _, field := range requiredFields {
+		if len(formData[field]) == 0 || formData[field][0] == "" {
+			errors = append(errors, InputValidationError{
+				Type: "required",
+				Msg:  fmt.Sprintf("%s is required", field),
+				Path: field,
+			})
+		}
+	}
+
+	if len(errors) > 0 {
+		return result, errors
+	}
+
+	result.Details = formData["details"][0]
+	result.ProposalTxHash = formData["proposalTxHash"][0]
+
+	// Parse offer ID
+	if offerID, err := strconv.ParseUint(formData["offerID"][0], 10, 64); err != nil {
+		errors = append(errors, InputValidationError{
+			Type:  "invalid",
+			Value: formData["offerID"][0],
+			Msg:   "invalid offer ID format",
+			Path:  "offerID",
+		})
+	} else {
+		result.OfferID = uint(offerID)
+	}
+
+	// Price is a uint256 on chain so it must be a positive integer
+	if price, ok := new(big.Int).SetString(formData["price"][0], 10); !ok || price.Sign() <= 0 {
+		errors = append(errors, InputValidationError{
+			Type:  "invalid",
+			Value: formData["price"][0],
+			Msg:   "price must be a positive integer",
+			Path:  "price",
+		})
+	} else {
+		result.Price = price
+	}
+
+	if !IsValidTxHash(result.ProposalTxHash) {
+		errors = append(errors, InputValidationError{
+			Type:  "invalid",
+			Value: result.ProposalTxHash,
+			Msg:   "invalid transaction hash",
+			Path:  "proposalTxHash",
+		})
+	}
+
+	return result, errors
+}
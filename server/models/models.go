@@ -1,19 +1,44 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-// Role remains unchanged
+// Role groups the permissions granted to its users. Built-in roles are seeded by migrations and
+// mirror the contract roles, so they cannot be renamed or deleted.
 type Role struct {
 	gorm.Model
-	Name       string `json:"name" gorm:"type:varchar(50);unique;not null"`
-	RoleTxHash string `json:"roleTxHash" gorm:"type:varchar(66);index"`
-	Users      []User `gorm:"many2many:user_roles;"`
+	Name        string       `json:"name" gorm:"type:varchar(50);unique;not null"`
+	RoleTxHash  string       `json:"roleTxHash" gorm:"type:varchar(66);index"`
+	BuiltIn     bool         `json:"builtIn" gorm:"not null;default:false"`
+	Permissions []Permission `json:"permissions" gorm:"many2many:role_permissions;"`
+	Users       []User       `gorm:"many2many:user_roles;"`
 }
 
+// Permission is an action a role allows, routes are guarded by permissions rather than role names
+type Permission struct {
+	ID          uint   `json:"id" gorm:"primaryKey"`
+	Name        string `json:"name" gorm:"type:varchar(50);not null;unique"`
+	Description string `json:"description" gorm:"type:text"`
+}
+
+// Permissions, defined by migrations
+const (
+	PermUserManage       = "user:manage"
+	PermRoleManage       = "role:manage"
+	PermAuditRead        = "audit:read"
+	PermTransactionRead  = "transaction:read" // transactions of every user, not only the caller's
+	PermOfferCreate      = "offer:create"
+	PermOfferManage      = "offer:manage" // declare the winner of and close own offers
+	PermProposalSubmit   = "proposal:submit"
+	PermEvaluationSubmit = "evaluation:submit"
+)
+
 // User with no Document/CID fields here (handled on Proposal and Evaluation)
 type User struct {
 	gorm.Model
@@ -38,19 +63,32 @@ type User struct {
 type Offer struct {
 	gorm.Model
 	TenderNumber     string     `json:"tenderNumber" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Title            string     `json:"title" gorm:"type:varchar(255);not null;default:''"`
+	Description      string     `json:"description" gorm:"type:text"`
+	Budget           float64    `json:"budget" gorm:"type:numeric(20,2)"`
+	Currency         string     `json:"currency" gorm:"type:varchar(10)"`
+	Location         string     `json:"location" gorm:"type:varchar(255)"`
 	ContractAddress  string     `json:"contractAddress" gorm:"type:varchar(42);not null;uniqueIndex"`
 	ProposalStart    time.Time  `json:"proposalSubmissionStart" gorm:"not null"`
 	ProposalEnd      time.Time  `json:"proposalSubmissionEnd" gorm:"not null"`
 	ReviewStart      time.Time  `json:"proposalReviewStart" gorm:"not null"`
 	ReviewEnd        time.Time  `json:"proposalReviewEnd" gorm:"not null"`
 	MinQualification string     `json:"minQualificationLevel" gorm:"type:varchar(100)"`
-	Status           string     `json:"status" gorm:"type:varchar(50);default:'Open';index"`
+	Status           string     `json:"status" gorm:"type:varchar(50);default:'NotStarted';index"`
 	CreatedBy        uint       `gorm:"not null;index"`
 	Creator          User       `gorm:"foreignKey:CreatedBy;constraint:OnDelete:RESTRICT"`
-	SectorID         uint       `gorm:"not null;index"`
+	SectorID         uint       `json:"sectorID" gorm:"not null;index"`
 	Sector           Sector     `gorm:"foreignKey:SectorID;constraint:OnDelete:RESTRICT"`
 	Documents        []Document `gorm:"polymorphic:Documentable;polymorphicValue:Offer"`
 	Proposals        []Proposal `gorm:"foreignKey:ContractID;constraint:OnDelete:CASCADE"`
+
+	// Experts the tender assigned to review the proposals, kept out of responses so bidders
+	// cannot tell who reviews them
+	ExpertAssignments []OfferExpert `json:"-" gorm:"foreignKey:OfferID;constraint:OnDelete:CASCADE"`
+
+	WinningProposalID *uint  `json:"winningProposalID" gorm:"index"`
+	WinnerTxHash      string `json:"winnerTxHash" gorm:"type:varchar(66)"` // on-chain declareWinner tx hash
+	CloseTxHash       string `json:"closeTxHash" gorm:"type:varchar(66)"`  // on-chain closeOffer tx hash
 }
 
 // Proposal with on-chain metadata
@@ -61,9 +99,13 @@ type Proposal struct {
 	ProposerID     uint               `json:"proposerID" gorm:"not null;index"`
 	Proposer       User               `gorm:"foreignKey:ProposerID;constraint:OnDelete:CASCADE"`
 	Details        string             `json:"details" gorm:"type:text"`
+	Price          string             `json:"price" gorm:"type:numeric(78,0)"` // uint256 as submitted on chain
 	Status         string             `json:"status" gorm:"type:varchar(50);default:'pending';index"`
 	SubmittedAt    time.Time          `json:"submittedAt" gorm:"not null;index"`
 	ProposalTxHash string             `json:"proposalTxHash" gorm:"type:varchar(66);index"` // on-chain tx hash
+	BlockNumber    uint64             `json:"blockNumber" gorm:"index"`                     // block of the tx, re-checked after a reorg
+	BlockHash      string             `json:"blockHash" gorm:"type:varchar(66)"`
+	IndexedOnly    bool               `json:"-" gorm:"not null;default:false"` // mirrored by the indexer, not submitted through the API yet
 	Documents      []Document         `gorm:"polymorphic:Documentable;polymorphicValue:Proposal"`
 	Evaluations    []ExpertEvaluation `gorm:"foreignKey:ProposalID;constraint:OnDelete:CASCADE"`
 }
@@ -71,13 +113,33 @@ type Proposal struct {
 // ExpertEvaluation with on-chain metadata
 type ExpertEvaluation struct {
 	gorm.Model
-	ProposalID   uint     `json:"proposalID" gorm:"not null;index"`
+	ProposalID   uint     `json:"proposalID" gorm:"not null;index;uniqueIndex:idx_evaluation_proposal_expert,where:deleted_at IS NULL"`
 	Proposal     Proposal `gorm:"foreignKey:ProposalID;constraint:OnDelete:CASCADE"`
-	ExpertID     uint     `json:"expertID" gorm:"not null;index"`
+	ExpertID     uint     `json:"expertID" gorm:"not null;index;uniqueIndex:idx_evaluation_proposal_expert,where:deleted_at IS NULL"` // one review per expert, as on chain
 	Expert       User     `gorm:"foreignKey:ExpertID;constraint:OnDelete:CASCADE"`
 	Score        float64  `json:"score" gorm:"not null"`
 	Comment      string   `json:"comment" gorm:"type:text"`
 	ReviewTxHash string   `json:"reviewTxHash" gorm:"type:varchar(66);index"` // on-chain tx hash
+	BlockNumber  uint64   `json:"blockNumber" gorm:"index"`                   // block of the tx, re-checked after a reorg
+	BlockHash    string   `json:"blockHash" gorm:"type:varchar(66)"`
+	IndexedOnly  bool     `json:"-" gorm:"not null;default:false"` // mirrored by the indexer, not submitted through the API yet
+}
+
+// UserRole is the user_roles join table, recording the transaction that granted the role
+type UserRole struct {
+	UserID     uint      `json:"userID" gorm:"primaryKey"`
+	RoleID     uint      `json:"roleID" gorm:"primaryKey"`
+	RoleTxHash string    `json:"roleTxHash" gorm:"type:varchar(66);index"` // on-chain grant tx hash
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// OfferExpert assigns an expert to review the proposals of an offer
+type OfferExpert struct {
+	OfferID    uint      `json:"offerID" gorm:"primaryKey"`
+	ExpertID   uint      `json:"expertID" gorm:"primaryKey;index"`
+	Expert     User      `json:"expert" gorm:"foreignKey:ExpertID;constraint:OnDelete:CASCADE"`
+	AssignedBy uint      `json:"assignedBy" gorm:"not null"`
+	CreatedAt  time.Time `json:"createdAt"`
 }
 
 // Qualification unchanged
@@ -115,3 +177,234 @@ type Document struct {
 	DocumentableID   uint   `json:"documentableID"`
 	DocumentableType string `json:"documentableType"`
 }
+
+// Document types
+const (
+	DocTypeOffer     = "offer_document"
+	DocTypeAdmin     = "administrative" // Dossier de candidature
+	DocTypeTechnical = "technical"      // Offre technique
+	DocTypeFinancial = "financial"      // Offre financière, sealed until the review period opens
+)
+
+// ChainCursor tracks how far the indexer has read the logs of a contract
+type ChainCursor struct {
+	gorm.Model
+	ContractAddress string `json:"contractAddress" gorm:"type:varchar(42);not null;uniqueIndex"`
+	ContractType    string `json:"contractType" gorm:"type:varchar(50);not null;index"` // OfferFactory or Offer
+	NextBlock       uint64 `json:"nextBlock" gorm:"not null;default:0"`                 // first block not yet processed
+	LastBlockHash   string `json:"lastBlockHash" gorm:"type:varchar(66)"`               // hash of NextBlock-1, used to detect reorgs
+	Finished        bool   `json:"finished" gorm:"default:false"`                       // no more events expected (offer closed)
+}
+
+// AuthSession is a login session, its refresh tokens rotate within it
+type AuthSession struct {
+	gorm.Model
+	UserID     uint       `json:"userID" gorm:"not null;index"`
+	User       User       `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	UserAgent  string     `json:"userAgent" gorm:"type:text"`
+	IPAddress  string     `json:"ipAddress" gorm:"type:varchar(45)"`
+	ExpiresAt  time.Time  `json:"expiresAt" gorm:"not null;index"`
+	LastUsedAt time.Time  `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt" gorm:"index"`
+}
+
+// RefreshToken is a single-use refresh token, only its SHA-256 hash is stored
+type RefreshToken struct {
+	gorm.Model
+	SessionID uint        `json:"sessionID" gorm:"not null;index"`
+	Session   AuthSession `json:"-" gorm:"foreignKey:SessionID;constraint:OnDelete:CASCADE"`
+	TokenHash string      `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time   `json:"expiresAt" gorm:"not null"`
+	UsedAt    *time.Time  `json:"usedAt"` // set when rotated, presenting it again revokes the session
+}
+
+// RevokedToken is an access token revoked before its expiry, keyed by its jti
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"type:varchar(64);primaryKey"`
+	UserID    uint      `json:"userID" gorm:"not null;index"`
+	ExpiresAt time.Time `json:"expiresAt" gorm:"not null;index"` // the row can be purged after this
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WalletChallenge is a single-use nonce signed by a wallet to prove it is controlled by the caller
+type WalletChallenge struct {
+	gorm.Model
+	Nonce     string     `json:"nonce" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Address   string     `json:"address" gorm:"type:varchar(42);not null;index"`
+	Purpose   string     `json:"purpose" gorm:"type:varchar(20);not null"` // siwe or wallet-bind
+	UserID    *uint      `json:"userID" gorm:"index"`                      // caller the challenge was issued to, if authenticated
+	ExpiresAt time.Time  `json:"expiresAt" gorm:"not null;index"`
+	UsedAt    *time.Time `json:"usedAt"`
+}
+
+const (
+	WalletChangeRotate = "rotate"
+	WalletChangeUnbind = "unbind"
+
+	WalletChangePending  = "pending"
+	WalletChangeApproved = "approved"
+	WalletChangeRejected = "rejected"
+)
+
+// WalletChangeRequest asks an admin to rotate or unbind the wallet bound to an account
+type WalletChangeRequest struct {
+	gorm.Model
+	UserID     uint       `json:"userID" gorm:"not null;index"`
+	User       User       `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Action     string     `json:"action" gorm:"type:varchar(10);not null"` // rotate or unbind
+	OldAddress string     `json:"oldAddress" gorm:"type:varchar(42);not null"`
+	NewAddress string     `json:"newAddress" gorm:"type:varchar(42)"` // ownership proven when the request was made
+	Reason     string     `json:"reason" gorm:"type:text"`
+	Status     string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	ReviewedBy *uint      `json:"reviewedBy"`
+	ReviewedAt *time.Time `json:"reviewedAt"`
+	ReviewNote string     `json:"reviewNote" gorm:"type:text"`
+}
+
+// RoleAuditLog records a role change applied to a user outside of an admin request
+type RoleAuditLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"userID" gorm:"not null;index"`
+	RoleID    uint      `json:"roleID" gorm:"not null"`
+	RoleName  string    `json:"roleName" gorm:"type:varchar(50);not null"`
+	Action    string    `json:"action" gorm:"type:varchar(20);not null"` // granted or revoked
+	Source    string    `json:"source" gorm:"type:varchar(50);not null"` // component that applied the change
+	Policy    string    `json:"policy" gorm:"type:varchar(30)"`
+	Reason    string    `json:"reason" gorm:"type:text"`
+	CreatedAt time.Time `json:"createdAt" gorm:"index"`
+}
+
+// CustodialWallet is a wallet whose key the server holds for a user without their own wallet,
+// the key is stored as an encrypted Web3 Secret Storage keystore
+type CustodialWallet struct {
+	gorm.Model
+	UserID   uint   `json:"userID" gorm:"not null;uniqueIndex"`
+	User     User   `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Address  string `json:"address" gorm:"type:varchar(42);not null;uniqueIndex"`
+	Keystore string `json:"-" gorm:"type:text;not null"`
+}
+
+const (
+	RelayedTxPending  = "pending"
+	RelayedTxMined    = "mined"
+	RelayedTxReverted = "reverted"
+	RelayedTxFailed   = "failed" // never accepted by the node, the nonce was not used
+)
+
+// RelayedTransaction is a contract transaction the relayer signed and broadcast on behalf of a user
+type RelayedTransaction struct {
+	gorm.Model
+	RequestedBy uint       `json:"requestedBy" gorm:"not null;index"`
+	Action      string     `json:"action" gorm:"type:varchar(30);not null"` // contract method called
+	Signer      string     `json:"signer" gorm:"type:varchar(42);not null;index:idx_relayed_transactions_signer_nonce"`
+	To          string     `json:"to" gorm:"type:varchar(42);not null"`
+	Nonce       uint64     `json:"nonce" gorm:"not null;index:idx_relayed_transactions_signer_nonce"`
+	GasLimit    uint64     `json:"gasLimit" gorm:"not null"`
+	GasTipCap   string     `json:"gasTipCap" gorm:"type:numeric(78,0)"`
+	GasFeeCap   string     `json:"gasFeeCap" gorm:"type:numeric(78,0)"`
+	TxHash      string     `json:"txHash" gorm:"type:varchar(66);not null;uniqueIndex"`
+	RawTx       string     `json:"-" gorm:"type:text;not null"` // signed transaction, rebroadcast if the node drops it
+	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	Error       string     `json:"error" gorm:"type:text"`
+	Broadcasts  int        `json:"broadcasts" gorm:"not null;default:0"`
+	BlockNumber *uint64    `json:"blockNumber"`
+	GasUsed     *uint64    `json:"gasUsed"`
+	MinedAt     *time.Time `json:"minedAt"`
+}
+
+// Chain transaction kinds
+const (
+	ChainTxOfferCreated      = "offer_created"
+	ChainTxProposalSubmitted = "proposal_submitted"
+	ChainTxProposalReviewed  = "proposal_reviewed"
+	ChainTxWinnerDeclared    = "winner_declared"
+	ChainTxOfferClosed       = "offer_closed"
+	ChainTxRoleGranted       = "role_granted"
+	ChainTxRoleRevoked       = "role_revoked"
+	ChainTxAuditAnchor       = "audit_anchor" // head hash of the audit trail, sent by the platform to itself
+)
+
+// Chain transaction statuses
+const (
+	ChainTxPending  = "pending"
+	ChainTxMined    = "mined"
+	ChainTxReverted = "reverted"
+	ChainTxDropped  = "dropped" // never mined and no longer known to the node
+)
+
+// ChainTransaction is a transaction of the platform contracts, followed by the transaction watcher
+// until it has enough confirmations
+type ChainTransaction struct {
+	gorm.Model
+	Hash          string     `json:"hash" gorm:"type:varchar(66);not null;uniqueIndex"`
+	Kind          string     `json:"kind" gorm:"type:varchar(30);not null;index"`
+	From          string     `json:"from" gorm:"type:varchar(42);index"`
+	To            string     `json:"to" gorm:"type:varchar(42)"`
+	UserID        *uint      `json:"userID" gorm:"index"`                                                    // user who sent or reported it
+	EntityType    string     `json:"entityType" gorm:"type:varchar(30);index:idx_chain_transactions_entity"` // related record, e.g. Proposal
+	EntityID      *uint      `json:"entityID" gorm:"index:idx_chain_transactions_entity"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	BlockNumber   *uint64    `json:"blockNumber"`
+	BlockHash     string     `json:"blockHash" gorm:"type:varchar(66)"`
+	Confirmations uint64     `json:"confirmations" gorm:"not null;default:0"`
+	RevertError   string     `json:"revertError" gorm:"type:varchar(100)"` // custom error name, e.g. InvalidStage
+	RevertReason  string     `json:"revertReason" gorm:"type:text"`        // decoded error with its arguments
+	MinedAt       *time.Time `json:"minedAt"`
+}
+
+// JSON is a JSON document stored in a jsonb column and rendered as is in API responses
+type JSON json.RawMessage
+
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return string(j), nil
+}
+
+func (j *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append(JSON(nil), v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", value)
+	}
+	return nil
+}
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return j, nil
+}
+
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	*j = append(JSON(nil), data...)
+	return nil
+}
+
+// AuditEvent records an action taken through the API by an admin, a tender or an expert. Rows are never
+// updated or deleted, the database rejects both, and each one is chained to the one before it by hash.
+type AuditEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ActorID    *uint     `json:"actorID" gorm:"index"`
+	Action     string    `json:"action" gorm:"type:varchar(50);not null;index"` // e.g. user.update
+	Method     string    `json:"method" gorm:"type:varchar(10);not null"`
+	Path       string    `json:"path" gorm:"type:text;not null"`
+	Status     int       `json:"status" gorm:"not null"`
+	TargetType string    `json:"targetType" gorm:"type:varchar(30);index:idx_audit_events_target"` // e.g. User
+	TargetID   *uint     `json:"targetID" gorm:"index:idx_audit_events_target"`
+	Before     JSON      `json:"before" gorm:"type:jsonb"` // changed fields before the action
+	After      JSON      `json:"after" gorm:"type:jsonb"`  // changed fields after the action
+	IP         string    `json:"ip" gorm:"type:varchar(45)"`
+	UserAgent  string    `json:"userAgent" gorm:"type:text"`
+	TxHash     string    `json:"txHash" gorm:"type:varchar(66)"`
+	CreatedAt  time.Time `json:"createdAt" gorm:"index"`
+	PrevHash   string    `json:"prevHash" gorm:"type:varchar(64)"` // Hash of the event before, empty for the first one
+	Hash       string    `json:"hash" gorm:"type:varchar(64)"`     // SHA-256 of the event content and PrevHash
+}
@@ -0,0 +1,119 @@
+package models
+
+import "time"
+
+// Offer lifecycle statuses, in the order an offer moves through them
+const (
+	OfferStatusNotStarted     = "NotStarted"
+	OfferStatusSubmission     = "Submission"
+	OfferStatusAwaitingReview = "AwaitingReview"
+	OfferStatusReview         = "Review"
+	OfferStatusEnded          = "Ended"
+	OfferStatusWinnerDeclared = "WinnerDeclared"
+	OfferStatusClosed         = "Closed"
+)
+
+// Proposal statuses, a proposal stays pending until the winner of its offer is declared
+const (
+	ProposalStatusPending  = "pending"
+	ProposalStatusAccepted = "accepted"
+	ProposalStatusRejected = "rejected"
+)
+
+// Offer stages, as returned by Offer.getStage(). The contract returns OfferStageEnded once closed
+// too, OfferStageClosed is never returned by it and is told apart through isClosed, see OfferStage.
+const (
+	OfferStageNotStarted uint8 = iota
+	OfferStageSubmission
+	OfferStageAwaitingReview
+	OfferStageReview
+	OfferStageEnded
+	OfferStageClosed
+)
+
+var offerStatusRank = map[string]int{
+	OfferStatusNotStarted:     0,
+	OfferStatusSubmission:     1,
+	OfferStatusAwaitingReview: 2,
+	OfferStatusReview:         3,
+	OfferStatusEnded:          4,
+	OfferStatusWinnerDeclared: 5,
+	OfferStatusClosed:         6,
+}
+
+// OfferStatusFromWindows computes the status implied by the time windows alone.
+// It can never return WinnerDeclared or Closed, those only come from the chain.
+func OfferStatusFromWindows(now, proposalStart, proposalEnd, reviewStart, reviewEnd time.Time) string {
+	switch {
+	case now.Before(proposalStart):
+		return OfferStatusNotStarted
+	case now.Before(proposalEnd):
+		return OfferStatusSubmission
+	case now.Before(reviewStart):
+		return OfferStatusAwaitingReview
+	case now.Before(reviewEnd):
+		return OfferStatusReview
+	default:
+		return OfferStatusEnded
+	}
+}
+
+// OfferStage returns the stage of an offer from Offer.getStage() and Offer.isClosed(), getStage()
+// answers OfferStageEnded both after the review period and once closed
+func OfferStage(stage uint8, isClosed bool) uint8 {
+	if isClosed {
+		return OfferStageClosed
+	}
+	return stage
+}
+
+// OfferStatusFromStage maps a stage, as returned by OfferStage, plus the winner flag to a status.
+// The winner is declared within OfferStageEnded, the stage alone does not tell.
+func OfferStatusFromStage(stage uint8, winnerDeclared bool) string {
+	switch {
+	case stage == OfferStageClosed:
+		return OfferStatusClosed
+	case winnerDeclared:
+		return OfferStatusWinnerDeclared
+	}
+
+	switch stage {
+	case OfferStageNotStarted:
+		return OfferStatusNotStarted
+	case OfferStageSubmission:
+		return OfferStatusSubmission
+	case OfferStageAwaitingReview:
+		return OfferStatusAwaitingReview
+	case OfferStageReview:
+		return OfferStatusReview
+	default:
+		return OfferStatusEnded
+	}
+}
+
+// LaterOfferStatus returns whichever status is further along the lifecycle.
+// The lifecycle only moves forward, so a lagging source (e.g. a chain whose latest
+// block timestamp is behind the wall clock) never moves an offer back.
+// Unknown statuses rank below NotStarted.
+func LaterOfferStatus(a, b string) string {
+	rankA, okA := offerStatusRank[a]
+	rankB, okB := offerStatusRank[b]
+	if !okA {
+		return b
+	}
+	if !okB || rankA >= rankB {
+		return a
+	}
+	return b
+}
+
+// TimeStatus computes the status of the offer from its time windows
+func (o *Offer) TimeStatus(now time.Time) string {
+	return OfferStatusFromWindows(now, o.ProposalStart, o.ProposalEnd, o.ReviewStart, o.ReviewEnd)
+}
+
+// EffectiveStatus combines the persisted status with the time windows, so handlers
+// see transitions the scheduler has not persisted yet.
+func (o *Offer) EffectiveStatus(now time.Time) string {
+	return LaterOfferStatus(o.Status, o.TimeStatus(now))
+}
@@ -0,0 +1,1285 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userPublicColumns are the user columns safe to return from listings
+var userPublicColumns = []string{
+	"id", "email", "first_name", "last_name", "created_at", "phone_number", "public_wallet_address",
+}
+
+// likePattern builds a case-insensitive LIKE pattern, to be compared against LOWER(column)
+func likePattern(search string) string {
+	return "%" + strings.ToLower(search) + "%"
+}
+
+type gormUsers struct {
+	db *gorm.DB
+}
+
+func (r *gormUsers) Get(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Preload("Roles.Permissions").First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUsers) Profile(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.Select(userPublicColumns).Preload("Roles").First(&user, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUsers) GetByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Preload("Roles").Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUsers) GetByWallet(wallet string) (*models.User, error) {
+	var user models.User
+	err := r.db.Preload("Roles").
+		Where("LOWER(public_wallet_address) = ?", strings.ToLower(wallet)).
+		First(&user).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUsers) List(filter UserFilter, page Page) ([]models.User, int64, error) {
+	query := r.db.Model(&models.User{})
+
+	if filter.Search != "" {
+		pattern := likePattern(filter.Search)
+		query = query.Where(
+			"LOWER(last_name) LIKE ? OR LOWER(first_name) LIKE ? OR LOWER(email) LIKE ? OR phone_number LIKE ?"+
+				" OR LOWER(first_name || ' ' || last_name) LIKE ?",
+			pattern, pattern, pattern, pattern, pattern,
+		)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := query.Select(userPublicColumns).Preload("Roles").
+		Order("id").Limit(page.Size).Offset(page.Offset()).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (r *gormUsers) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+// Save writes the user itself, roles are changed through GrantRole and RevokeRole
+func (r *gormUsers) Save(user *models.User) error {
+	return r.db.Omit(clause.Associations).Save(user).Error
+}
+
+func (r *gormUsers) Delete(user *models.User) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("documentable_id = ? AND documentable_type = ?", user.ID, "User").Delete(&models.Document{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(user).Error
+	})
+}
+
+func (r *gormUsers) EmailTaken(email string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).
+		Where("email = ? AND id <> ?", email, exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormUsers) WalletInUse(wallet string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).
+		Where("LOWER(public_wallet_address) = ? AND id <> ?", strings.ToLower(wallet), exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormUsers) GrantRole(userRole *models.UserRole) error {
+	return r.db.Create(userRole).Error
+}
+
+func (r *gormUsers) RevokeRole(userID uint, roleID uint) error {
+	result := r.db.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&models.UserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormUsers) SetEmail(id uint, email string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("email", email).Error
+}
+
+func (r *gormUsers) SetPhoneNumber(id uint, phoneNumber string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("phone_number", phoneNumber).Error
+}
+
+func (r *gormUsers) SetWallet(id uint, wallet string) error {
+	var value interface{} = gorm.Expr("NULL")
+	if wallet != "" {
+		value = wallet
+	}
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("public_wallet_address", value).Error
+}
+
+func (r *gormUsers) ListWithRoles() ([]models.User, error) {
+	var users []models.User
+	if err := r.db.Select(userPublicColumns).Preload("Roles").Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *gormUsers) ApplyRoleChange(entry *models.RoleAuditLog, txHash string) (bool, error) {
+	changed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var result *gorm.DB
+		if entry.Action == "granted" {
+			userRole := models.UserRole{UserID: entry.UserID, RoleID: entry.RoleID, RoleTxHash: txHash}
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&userRole)
+		} else {
+			result = tx.Where("user_id = ? AND role_id = ?", entry.UserID, entry.RoleID).Delete(&models.UserRole{})
+		}
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		changed = true
+		return tx.Create(entry).Error
+	})
+	return changed, err
+}
+
+func (r *gormUsers) GetQualification(id uint) (*models.UserQualification, error) {
+	var qualification models.UserQualification
+	if err := r.db.First(&qualification, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &qualification, nil
+}
+
+type gormRoles struct {
+	db *gorm.DB
+}
+
+func (r *gormRoles) List() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Preload("Permissions").Order("id").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *gormRoles) Get(id uint) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").First(&role, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &role, nil
+}
+
+func (r *gormRoles) GetByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &role, nil
+}
+
+func (r *gormRoles) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+// Save writes the role itself, permissions are changed through SetPermissions
+func (r *gormRoles) Save(role *models.Role) error {
+	return r.db.Omit(clause.Associations).Save(role).Error
+}
+
+func (r *gormRoles) Delete(role *models.Role) error {
+	return r.db.Unscoped().Delete(role).Error
+}
+
+func (r *gormRoles) CountUsers(roleID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserRole{}).Where("role_id = ?", roleID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormRoles) SetPermissions(role *models.Role, permissions []models.Permission) error {
+	if err := r.db.Model(role).Association("Permissions").Replace(permissions); err != nil {
+		return err
+	}
+	role.Permissions = permissions
+	return nil
+}
+
+type gormPermissions struct {
+	db *gorm.DB
+}
+
+func (r *gormPermissions) List() ([]models.Permission, error) {
+	var permissions []models.Permission
+	if err := r.db.Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *gormPermissions) GetByNames(names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	if err := r.db.Where("name IN ?", names).Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	requested := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		requested[name] = struct{}{}
+	}
+	if len(permissions) != len(requested) {
+		return nil, ErrNotFound
+	}
+	return permissions, nil
+}
+
+type gormOffers struct {
+	db *gorm.DB
+}
+
+func (r *gormOffers) Get(id uint) (*models.Offer, error) {
+	var offer models.Offer
+	if err := r.db.Preload("Documents").Preload("Sector").Preload("ExpertAssignments").First(&offer, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &offer, nil
+}
+
+func (r *gormOffers) List(filter OfferFilter, page Page) ([]models.Offer, int64, error) {
+	query := r.db.Model(&models.Offer{})
+
+	if !filter.IncludeClosed {
+		query = query.Where("offers.status <> ?", models.OfferStatusClosed)
+	}
+	if filter.Search != "" {
+		query = query.Where("LOWER(offers.title) LIKE ?", likePattern(filter.Search))
+	}
+	if filter.Location != "" {
+		query = query.Where("LOWER(offers.location) LIKE ?", likePattern(filter.Location))
+	}
+	if filter.SectorCode != "" {
+		query = query.Joins("JOIN sectors ON sectors.id = offers.sector_id").
+			Where("sectors.code = ?", filter.SectorCode)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var offers []models.Offer
+	err := query.Preload("Sector").
+		Preload("Creator", func(db *gorm.DB) *gorm.DB { return db.Select(userPublicColumns) }).
+		Order("offers.created_at DESC").Limit(page.Size).Offset(page.Offset()).
+		Find(&offers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return offers, total, nil
+}
+
+func (r *gormOffers) Create(offer *models.Offer) error {
+	return r.db.Create(offer).Error
+}
+
+func (r *gormOffers) CreateWithDocuments(offer *models.Offer, documents []models.Document) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(offer).Error; err != nil {
+			return err
+		}
+		return createDocuments(tx, "Offer", offer.ID, documents)
+	})
+	if err != nil {
+		return err
+	}
+	offer.Documents = documents
+	return nil
+}
+
+// createDocuments attaches documents to a record inside tx
+func createDocuments(tx *gorm.DB, documentableType string, documentableID uint, documents []models.Document) error {
+	for i := range documents {
+		documents[i].DocumentableType = documentableType
+		documents[i].DocumentableID = documentableID
+		if err := tx.Create(&documents[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *gormOffers) ContractExists(contractAddress string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Offer{}).
+		Where("LOWER(contract_address) = ?", strings.ToLower(contractAddress)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormOffers) GetByContract(contractAddress string) (*models.Offer, error) {
+	var offer models.Offer
+	err := r.db.Where("LOWER(contract_address) = ?", strings.ToLower(contractAddress)).First(&offer).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &offer, nil
+}
+
+func (r *gormOffers) ContractAddresses() ([]string, error) {
+	var addresses []string
+	err := r.db.Model(&models.Offer{}).Order("id").Pluck("contract_address", &addresses).Error
+	return addresses, err
+}
+
+func (r *gormOffers) SetWindows(id uint, proposalStart, proposalEnd, reviewStart, reviewEnd time.Time) error {
+	return r.db.Model(&models.Offer{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"proposal_start": proposalStart,
+		"proposal_end":   proposalEnd,
+		"review_start":   reviewStart,
+		"review_end":     reviewEnd,
+	}).Error
+}
+
+func (r *gormOffers) ListOpen() ([]models.Offer, error) {
+	var offers []models.Offer
+	if err := r.db.Where("status <> ?", models.OfferStatusClosed).Order("id").Find(&offers).Error; err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+func (r *gormOffers) MoveStatus(id uint, current string, next string) (bool, error) {
+	result := r.db.Model(&models.Offer{}).
+		Where("id = ? AND status = ?", id, current).
+		Update("status", next)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *gormOffers) ListExperts(offerID uint) ([]models.OfferExpert, error) {
+	var assignments []models.OfferExpert
+	err := r.db.Preload("Expert", func(db *gorm.DB) *gorm.DB { return db.Select(userPublicColumns) }).
+		Where("offer_id = ?", offerID).
+		Order("created_at, expert_id").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *gormOffers) AssignExpert(assignment *models.OfferExpert) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(assignment).Error
+}
+
+func (r *gormOffers) UnassignExpert(offerID uint, expertID uint) error {
+	result := r.db.Where("offer_id = ? AND expert_id = ?", offerID, expertID).Delete(&models.OfferExpert{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormOffers) DeclareWinner(offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error) {
+	var proposal *models.Proposal
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		proposal, err = applyWinner(tx, offer, winnerWallet, txHash)
+		return err
+	})
+	return proposal, err
+}
+
+func (r *gormOffers) Close(offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error) {
+	var proposal *models.Proposal
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if winnerWallet != "" {
+			var err error
+			if proposal, err = applyWinner(tx, offer, winnerWallet, txHash); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(offer).Updates(map[string]interface{}{
+			"status":        models.OfferStatusClosed,
+			"close_tx_hash": txHash,
+		}).Error
+	})
+	return proposal, err
+}
+
+// applyWinner records the winner of offer inside tx, see OfferRepository.DeclareWinner
+func applyWinner(tx *gorm.DB, offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error) {
+	var proposal models.Proposal
+	err := tx.Joins("JOIN users ON users.id = proposals.proposer_id").
+		Where("proposals.contract_id = ? AND LOWER(users.public_wallet_address) = ?", offer.ID, strings.ToLower(winnerWallet)).
+		First(&proposal).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"winner_tx_hash": txHash}
+	if offer.Status != models.OfferStatusClosed {
+		updates["status"] = models.OfferStatusWinnerDeclared
+	}
+
+	if err == nil {
+		if err := tx.Model(&models.Proposal{}).Where("contract_id = ? AND id <> ?", offer.ID, proposal.ID).
+			Update("status", models.ProposalStatusRejected).Error; err != nil {
+			return nil, err
+		}
+		if err := tx.Model(&proposal).Update("status", models.ProposalStatusAccepted).Error; err != nil {
+			return nil, err
+		}
+		updates["winning_proposal_id"] = proposal.ID
+	} else {
+		log.Printf("Winner %s of offer %d has no registered proposal", winnerWallet, offer.ID)
+	}
+
+	if err := tx.Model(offer).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	if proposal.ID == 0 {
+		return nil, nil
+	}
+	return &proposal, nil
+}
+
+type gormProposals struct {
+	db *gorm.DB
+}
+
+func (r *gormProposals) Get(id uint) (*models.Proposal, error) {
+	var proposal models.Proposal
+	if err := r.db.Preload("Contract.ExpertAssignments").Preload("Proposer").First(&proposal, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &proposal, nil
+}
+
+func (r *gormProposals) Exists(offerID uint, proposerID uint, txHash string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Proposal{}).
+		Where("(contract_id = ? AND proposer_id = ?) OR LOWER(proposal_tx_hash) = LOWER(?)", offerID, proposerID, txHash).
+		Where("NOT (indexed_only AND LOWER(proposal_tx_hash) = LOWER(?))", txHash).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormProposals) CreateWithDocuments(proposal *models.Proposal, documents []models.Document) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var indexed models.Proposal
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("contract_id = ? AND proposer_id = ? AND indexed_only AND LOWER(proposal_tx_hash) = LOWER(?)",
+				proposal.ContractID, proposal.ProposerID, proposal.ProposalTxHash).
+			First(&indexed).Error
+		switch {
+		case err == nil:
+			adoptProposal(proposal, &indexed)
+			err = tx.Omit(clause.Associations).Save(proposal).Error
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			err = tx.Omit(clause.Associations).Create(proposal).Error
+		}
+		if err != nil {
+			return err
+		}
+		return createDocuments(tx, "Proposal", proposal.ID, documents)
+	})
+	if err != nil {
+		return err
+	}
+	proposal.Documents = documents
+	return nil
+}
+
+func (r *gormProposals) GetByWallet(offerID uint, wallet string) (*models.Proposal, error) {
+	var proposal models.Proposal
+	err := r.db.Joins("JOIN users ON users.id = proposals.proposer_id").
+		Where("proposals.contract_id = ? AND LOWER(users.public_wallet_address) = ?", offerID, strings.ToLower(wallet)).
+		First(&proposal).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &proposal, nil
+}
+
+func (r *gormProposals) UpsertIndexed(proposal *models.Proposal) error {
+	return r.db.Where(models.Proposal{ContractID: proposal.ContractID, ProposerID: proposal.ProposerID}).
+		Assign(models.Proposal{
+			Details:        proposal.Details,
+			Price:          proposal.Price,
+			ProposalTxHash: proposal.ProposalTxHash,
+			BlockNumber:    proposal.BlockNumber,
+			BlockHash:      proposal.BlockHash,
+		}).
+		Attrs(models.Proposal{Status: proposal.Status, SubmittedAt: proposal.SubmittedAt, IndexedOnly: true}).
+		FirstOrCreate(proposal).Error
+}
+
+func (r *gormProposals) ListByOffer(offerID uint) ([]models.Proposal, error) {
+	var proposals []models.Proposal
+	err := r.db.Preload("Proposer", func(db *gorm.DB) *gorm.DB { return db.Select(userPublicColumns) }).
+		Preload("Evaluations").
+		Where("contract_id = ?", offerID).
+		Order("submitted_at, id").
+		Find(&proposals).Error
+	return proposals, err
+}
+
+func (r *gormProposals) ListAwaitingReview(expertID uint, now time.Time) ([]models.Proposal, error) {
+	var proposals []models.Proposal
+	err := r.db.Preload("Documents").
+		Preload("Contract.ExpertAssignments").
+		Joins("JOIN offers ON offers.id = proposals.contract_id").
+		Where("offers.review_start <= ? AND offers.review_end > ? AND offers.status NOT IN ?", now, now,
+			[]string{models.OfferStatusWinnerDeclared, models.OfferStatusClosed}).
+		Where("proposals.proposer_id <> ?", expertID).
+		Where("NOT EXISTS (SELECT 1 FROM expert_evaluations WHERE expert_evaluations.proposal_id = proposals.id AND expert_evaluations.expert_id = ? AND expert_evaluations.deleted_at IS NULL)", expertID).
+		Order("offers.review_end ASC").
+		Find(&proposals).Error
+	return proposals, err
+}
+
+func (r *gormProposals) ListFromBlock(offerID uint, block uint64) ([]models.Proposal, error) {
+	var proposals []models.Proposal
+	err := r.db.Where("contract_id = ? AND block_number >= ? AND block_number > 0", offerID, block).Order("block_number, id").Find(&proposals).Error
+	return proposals, err
+}
+
+func (r *gormProposals) SetBlock(id uint, number uint64, hash string) error {
+	return r.db.Model(&models.Proposal{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"block_number": number, "block_hash": hash}).Error
+}
+
+func (r *gormProposals) Delete(id uint) error {
+	return r.db.Delete(&models.Proposal{}, id).Error
+}
+
+// adoptProposal makes proposal the API submission of the proposal the indexer mirrored
+func adoptProposal(proposal *models.Proposal, indexed *models.Proposal) {
+	proposal.ID, proposal.CreatedAt = indexed.ID, indexed.CreatedAt
+	// The winner may have been declared from the indexed proposal already
+	proposal.Status = indexed.Status
+	proposal.SubmittedAt = indexed.SubmittedAt
+	proposal.IndexedOnly = false
+}
+
+type gormEvaluations struct {
+	db *gorm.DB
+}
+
+func (r *gormEvaluations) Exists(proposalID uint, expertID uint, txHash string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ExpertEvaluation{}).
+		Where("(proposal_id = ? AND expert_id = ?) OR LOWER(review_tx_hash) = LOWER(?)", proposalID, expertID, txHash).
+		Where("NOT (indexed_only AND LOWER(review_tx_hash) = LOWER(?))", txHash).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormEvaluations) Create(evaluation *models.ExpertEvaluation) error {
+	return r.db.Create(evaluation).Error
+}
+
+func (r *gormEvaluations) UpsertIndexed(evaluation *models.ExpertEvaluation) error {
+	return r.db.Where(models.ExpertEvaluation{ProposalID: evaluation.ProposalID, ExpertID: evaluation.ExpertID}).
+		Assign(models.ExpertEvaluation{
+			Score:        evaluation.Score,
+			ReviewTxHash: evaluation.ReviewTxHash,
+			BlockNumber:  evaluation.BlockNumber,
+			BlockHash:    evaluation.BlockHash,
+		}).
+		Attrs(models.ExpertEvaluation{IndexedOnly: true}).
+		FirstOrCreate(evaluation).Error
+}
+
+func (r *gormEvaluations) CreateWithTracking(evaluation *models.ExpertEvaluation, tracked *models.ChainTransaction) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var indexed models.ExpertEvaluation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("proposal_id = ? AND expert_id = ? AND indexed_only AND LOWER(review_tx_hash) = LOWER(?)",
+				evaluation.ProposalID, evaluation.ExpertID, evaluation.ReviewTxHash).
+			First(&indexed).Error
+		switch {
+		case err == nil:
+			evaluation.ID, evaluation.CreatedAt = indexed.ID, indexed.CreatedAt
+			evaluation.IndexedOnly = false
+			err = tx.Omit(clause.Associations).Save(evaluation).Error
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			err = tx.Create(evaluation).Error
+		}
+		if err != nil {
+			return err
+		}
+		tracked.EntityType = "Evaluation"
+		tracked.EntityID = &evaluation.ID
+		return trackTransaction(tx, tracked)
+	})
+}
+
+func (r *gormEvaluations) ListByExpert(expertID uint, page Page) ([]models.ExpertEvaluation, int64, error) {
+	query := r.db.Model(&models.ExpertEvaluation{}).Where("expert_id = ?", expertID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var evaluations []models.ExpertEvaluation
+	err := query.Preload("Proposal").Preload("Proposal.Contract").
+		Order("created_at DESC").Limit(page.Size).Offset(page.Offset()).
+		Find(&evaluations).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return evaluations, total, nil
+}
+
+func (r *gormEvaluations) ListFromBlock(offerID uint, block uint64) ([]models.ExpertEvaluation, error) {
+	var evaluations []models.ExpertEvaluation
+	err := r.db.Joins("JOIN proposals ON proposals.id = expert_evaluations.proposal_id").
+		Where("proposals.contract_id = ? AND expert_evaluations.block_number >= ? AND expert_evaluations.block_number > 0", offerID, block).
+		Order("expert_evaluations.block_number, expert_evaluations.id").
+		Find(&evaluations).Error
+	return evaluations, err
+}
+
+func (r *gormEvaluations) SetBlock(id uint, number uint64, hash string) error {
+	return r.db.Model(&models.ExpertEvaluation{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"block_number": number, "block_hash": hash}).Error
+}
+
+func (r *gormEvaluations) Delete(id uint) error {
+	return r.db.Delete(&models.ExpertEvaluation{}, id).Error
+}
+
+type gormSectors struct {
+	db *gorm.DB
+}
+
+func (r *gormSectors) List() ([]models.Sector, error) {
+	var sectors []models.Sector
+	if err := r.db.Order("code").Find(&sectors).Error; err != nil {
+		return nil, err
+	}
+	return sectors, nil
+}
+
+func (r *gormSectors) Exists(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Sector{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+type gormDocuments struct {
+	db *gorm.DB
+}
+
+func (r *gormDocuments) Get(id uint) (*models.Document, error) {
+	var document models.Document
+	if err := r.db.First(&document, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &document, nil
+}
+
+func (r *gormDocuments) Create(document *models.Document) error {
+	return r.db.Create(document).Error
+}
+
+func (r *gormDocuments) ListFor(documentableType string, documentableID uint) ([]models.Document, error) {
+	var documents []models.Document
+	err := r.db.Where("documentable_type = ? AND documentable_id = ?", documentableType, documentableID).
+		Order("id").Find(&documents).Error
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+type gormChainTransactions struct {
+	db *gorm.DB
+}
+
+func (r *gormChainTransactions) List(filter ChainTransactionFilter, page Page) ([]models.ChainTransaction, int64, error) {
+	query := r.db.Model(&models.ChainTransaction{})
+
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var transactions []models.ChainTransaction
+	err := query.Order("created_at DESC, id DESC").
+		Offset(page.Offset()).Limit(page.Size).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
+
+func (r *gormChainTransactions) Track(transaction *models.ChainTransaction) error {
+	return trackTransaction(r.db, transaction)
+}
+
+// trackTransaction upserts a tracked transaction on its hash, see ChainTransactionRepository.Track
+func trackTransaction(db *gorm.DB, transaction *models.ChainTransaction) error {
+	transaction.Hash = strings.ToLower(transaction.Hash)
+	if transaction.Status == "" {
+		transaction.Status = models.ChainTxPending
+	}
+
+	updates := []string{"kind", "updated_at"}
+	if transaction.UserID != nil {
+		updates = append(updates, "user_id")
+	}
+	if transaction.EntityType != "" {
+		updates = append(updates, "entity_type", "entity_id")
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hash"}},
+		DoUpdates: clause.AssignmentColumns(updates),
+	}).Create(transaction).Error
+}
+
+func (r *gormChainTransactions) GetByHash(hash string) (*models.ChainTransaction, error) {
+	var transaction models.ChainTransaction
+	if err := r.db.Where("hash = ?", strings.ToLower(hash)).First(&transaction).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &transaction, nil
+}
+
+func (r *gormChainTransactions) ListUnconfirmed(confirmations uint64) ([]models.ChainTransaction, error) {
+	var transactions []models.ChainTransaction
+	err := r.db.
+		Where("status = ? OR (status IN ? AND confirmations < ?)",
+			models.ChainTxPending, []string{models.ChainTxMined, models.ChainTxReverted}, confirmations).
+		Order("id").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *gormChainTransactions) Save(transaction *models.ChainTransaction) error {
+	return r.db.Save(transaction).Error
+}
+
+type gormChainCursors struct {
+	db *gorm.DB
+}
+
+func (r *gormChainCursors) Ensure(contractAddress string, contractType string, startBlock uint64) (*models.ChainCursor, error) {
+	var cursor models.ChainCursor
+	err := r.db.Where(models.ChainCursor{ContractAddress: contractAddress}).
+		Attrs(models.ChainCursor{ContractType: contractType, NextBlock: startBlock}).
+		FirstOrCreate(&cursor).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func (r *gormChainCursors) ListActive(contractType string) ([]models.ChainCursor, error) {
+	var cursors []models.ChainCursor
+	err := r.db.Where("contract_type = ? AND finished = ?", contractType, false).Order("id").Find(&cursors).Error
+	return cursors, err
+}
+
+func (r *gormChainCursors) Save(cursor *models.ChainCursor) error {
+	return r.db.Save(cursor).Error
+}
+
+type gormWalletChangeRequests struct {
+	db *gorm.DB
+}
+
+func (r *gormWalletChangeRequests) List(status string, page Page) ([]models.WalletChangeRequest, int64, error) {
+	query := r.db.Model(&models.WalletChangeRequest{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var requests []models.WalletChangeRequest
+	err := query.Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "email", "first_name", "last_name", "public_wallet_address")
+	}).
+		Order("created_at ASC").Limit(page.Size).Offset(page.Offset()).
+		Find(&requests).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
+}
+
+func (r *gormWalletChangeRequests) HasPending(userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.WalletChangeRequest{}).
+		Where("user_id = ? AND status = ?", userID, models.WalletChangePending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormWalletChangeRequests) Create(request *models.WalletChangeRequest) error {
+	return r.db.Omit(clause.Associations).Create(request).Error
+}
+
+func (r *gormWalletChangeRequests) Review(id uint, review func(request *models.WalletChangeRequest, user *models.User) error) (*models.WalletChangeRequest, *models.User, error) {
+	var request models.WalletChangeRequest
+	var user models.User
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&request, id).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Preload("Roles").First(&user, request.UserID).Error; err != nil {
+			return err
+		}
+
+		if err := review(&request, &user); err != nil {
+			return err
+		}
+
+		if request.Status == models.WalletChangeApproved {
+			var wallet interface{} = gorm.Expr("NULL")
+			if request.NewAddress != "" {
+				wallet = request.NewAddress
+			}
+			if err := tx.Model(&user).Update("public_wallet_address", wallet).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit(clause.Associations).Save(&request).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &request, &user, nil
+}
+
+type gormRoleAuditLogs struct {
+	db *gorm.DB
+}
+
+func (r *gormRoleAuditLogs) List(userID *uint, page Page) ([]models.RoleAuditLog, int64, error) {
+	query := r.db.Model(&models.RoleAuditLog{})
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.RoleAuditLog
+	if err := query.Order("created_at DESC").Limit(page.Size).Offset(page.Offset()).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+type gormAuthSessions struct {
+	db *gorm.DB
+}
+
+func (r *gormAuthSessions) Create(session *models.AuthSession, token *models.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Omit(clause.Associations).Create(token).Error
+	})
+}
+
+func (r *gormAuthSessions) Rotate(tokenHash string, rotate func(token *models.RefreshToken, session *models.AuthSession, user *models.User) (*models.RefreshToken, error)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the token so two concurrent refreshes cannot both consume it
+		var token models.RefreshToken
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", tokenHash).
+			First(&token).Error
+		if err != nil {
+			return notFound(err)
+		}
+
+		var session models.AuthSession
+		if err := tx.First(&session, token.SessionID).Error; err != nil {
+			return notFound(err)
+		}
+
+		var user models.User
+		if err := tx.Preload("Roles").First(&user, session.UserID).Error; err != nil {
+			return notFound(err)
+		}
+
+		next, err := rotate(&token, &session, &user)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&token).Update("used_at", token.UsedAt).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&session).Updates(map[string]interface{}{
+			"expires_at":   session.ExpiresAt,
+			"last_used_at": session.LastUsedAt,
+			"user_agent":   session.UserAgent,
+			"ip_address":   session.IPAddress,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		next.SessionID = session.ID
+		return tx.Omit(clause.Associations).Create(next).Error
+	})
+}
+
+func (r *gormAuthSessions) Get(id uint) (*models.AuthSession, error) {
+	var session models.AuthSession
+	if err := r.db.First(&session, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &session, nil
+}
+
+func (r *gormAuthSessions) Revoke(id uint, at time.Time) error {
+	return r.db.Model(&models.AuthSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *gormAuthSessions) RevokeAll(userID uint, at time.Time) (int64, error) {
+	result := r.db.Model(&models.AuthSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", at)
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormAuthSessions) RevokeToken(revoked *models.RevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(revoked).Error
+}
+
+func (r *gormAuthSessions) TokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormAuthSessions) PurgeExpired(now time.Time, sessionCutoff time.Time) error {
+	if err := r.db.Where("expires_at < ?", now).Delete(&models.RevokedToken{}).Error; err != nil {
+		return fmt.Errorf("purging revoked tokens: %w", err)
+	}
+	if err := r.db.Unscoped().Where("expires_at < ?", sessionCutoff).Delete(&models.AuthSession{}).Error; err != nil {
+		return fmt.Errorf("purging sessions: %w", err)
+	}
+	if err := r.db.Unscoped().Where("expires_at < ?", sessionCutoff).Delete(&models.RefreshToken{}).Error; err != nil {
+		return fmt.Errorf("purging refresh tokens: %w", err)
+	}
+	return nil
+}
+
+type gormWalletChallenges struct {
+	db *gorm.DB
+}
+
+func (r *gormWalletChallenges) Create(challenge *models.WalletChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *gormWalletChallenges) GetUsable(nonce string, purpose string, userID uint, now time.Time) (*models.WalletChallenge, error) {
+	var challenge models.WalletChallenge
+	err := r.db.
+		Where("nonce = ? AND purpose = ? AND user_id = ?", nonce, purpose, userID).
+		Where("used_at IS NULL AND expires_at > ?", now).
+		First(&challenge).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &challenge, nil
+}
+
+func (r *gormWalletChallenges) Consume(nonce string, wallet string, purpose string, userID *uint, now time.Time) error {
+	query := r.db.Model(&models.WalletChallenge{}).
+		Where("nonce = ? AND LOWER(address) = ? AND purpose = ?", nonce, strings.ToLower(wallet), purpose).
+		Where("used_at IS NULL AND expires_at > ?", now)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	} else {
+		query = query.Where("user_id IS NULL")
+	}
+
+	result := query.Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormWalletChallenges) PurgeExpired(now time.Time) error {
+	return r.db.Unscoped().Where("expires_at < ?", now).Delete(&models.WalletChallenge{}).Error
+}
+
+type gormCustodialWallets struct {
+	db *gorm.DB
+}
+
+func (r *gormCustodialWallets) GetByUser(userID uint) (*models.CustodialWallet, error) {
+	var wallet models.CustodialWallet
+	if err := r.db.Where("user_id = ?", userID).First(&wallet).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &wallet, nil
+}
+
+func (r *gormCustodialWallets) Create(wallet *models.CustodialWallet) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "public_wallet_address").First(&user, wallet.UserID).Error
+		if err != nil {
+			return notFound(err)
+		}
+
+		var count int64
+		if err := tx.Model(&models.CustodialWallet{}).Where("user_id = ?", wallet.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if user.PublicWalletAddress != "" || count > 0 {
+			return ErrConflict
+		}
+
+		if err := tx.Create(wallet).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update("public_wallet_address", wallet.Address).Error
+	})
+}
+
+type gormRelayedTransactions struct {
+	db *gorm.DB
+}
+
+func (r *gormRelayedTransactions) Get(id uint) (*models.RelayedTransaction, error) {
+	var transaction models.RelayedTransaction
+	if err := r.db.First(&transaction, id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &transaction, nil
+}
+
+func (r *gormRelayedTransactions) ListByRequester(userID uint, page Page) ([]models.RelayedTransaction, int64, error) {
+	query := r.db.Model(&models.RelayedTransaction{}).Where("requested_by = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var transactions []models.RelayedTransaction
+	err := query.Order("created_at DESC, id DESC").
+		Offset(page.Offset()).Limit(page.Size).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
+
+func (r *gormRelayedTransactions) ListPending() ([]models.RelayedTransaction, error) {
+	var transactions []models.RelayedTransaction
+	err := r.db.Where("status = ?", models.RelayedTxPending).Order("signer, nonce").Find(&transactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+func (r *gormRelayedTransactions) HighestPendingNonce(signer string) (uint64, bool, error) {
+	var highest sql.NullInt64
+	err := r.db.Model(&models.RelayedTransaction{}).
+		Where("signer = ? AND status = ?", signer, models.RelayedTxPending).
+		Select("MAX(nonce)").Scan(&highest).Error
+	if err != nil {
+		return 0, false, err
+	}
+	return uint64(highest.Int64), highest.Valid, nil
+}
+
+func (r *gormRelayedTransactions) Create(transaction *models.RelayedTransaction) error {
+	return r.db.Create(transaction).Error
+}
+
+func (r *gormRelayedTransactions) Save(transaction *models.RelayedTransaction) error {
+	return r.db.Save(transaction).Error
+}
+
+type gormAuditEvents struct {
+	db *gorm.DB
+}
+
+// auditChainLock is the advisory lock key serializing audit appends
+const auditChainLock = 0x61756469
+
+func (r *gormAuditEvents) Append(event *models.AuditEvent, seal func(event *models.AuditEvent, prevHash string)) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+
+		var last models.AuditEvent
+		err := tx.Select("id", "hash").Order("id DESC").Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		seal(event, last.Hash)
+		return tx.Create(event).Error
+	})
+}
+
+func (r *gormAuditEvents) Head() (*models.AuditEvent, error) {
+	var event models.AuditEvent
+	if err := r.db.Order("id DESC").Take(&event).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &event, nil
+}
+
+// filtered applies filter to a query on the audit events
+func (r *gormAuditEvents) filtered(filter AuditEventFilter) *gorm.DB {
+	query := r.db.Model(&models.AuditEvent{})
+
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	return query
+}
+
+func (r *gormAuditEvents) List(filter AuditEventFilter, page Page) ([]models.AuditEvent, int64, error) {
+	query := r.filtered(filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []models.AuditEvent
+	err := query.Order("id DESC").Offset(page.Offset()).Limit(page.Size).Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+func (r *gormAuditEvents) Each(filter AuditEventFilter, fn func(event models.AuditEvent) error) error {
+	var events []models.AuditEvent
+	var fnErr error
+	err := r.filtered(filter).Order("id").FindInBatches(&events, 500, func(tx *gorm.DB, batch int) error {
+		for _, event := range events {
+			if fnErr = fn(event); fnErr != nil {
+				return fnErr
+			}
+		}
+		return nil
+	}).Error
+	if fnErr != nil {
+		return fnErr
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+//go:build integration
+
+package store
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Brondont/trust-api/db"
+	"github.com/Brondont/trust-api/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TestGormStore runs the repository contracts against the PostgreSQL database STORE_TEST_DSN points
+// to, e.g. go test -tags integration ./store with
+// STORE_TEST_DSN="host=localhost user=trust password=trust dbname=trust_test sslmode=disable".
+// Every test runs in a transaction rolled back once it ends.
+func TestGormStore(t *testing.T) {
+	dsn := os.Getenv("STORE_TEST_DSN")
+	if dsn == "" {
+		t.Skip("STORE_TEST_DSN is not set")
+	}
+
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	if err := conn.SetupJoinTable(&models.User{}, "Roles", &models.UserRole{}); err != nil {
+		t.Fatalf("setting up user roles: %v", err)
+	}
+	if err := conn.SetupJoinTable(&models.Role{}, "Users", &models.UserRole{}); err != nil {
+		t.Fatalf("setting up role users: %v", err)
+	}
+	if _, err := db.MigrateUp(conn); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+
+	testRepositories(t, func(t *testing.T) Store {
+		tx := conn.Begin()
+		if tx.Error != nil {
+			t.Fatalf("beginning transaction: %v", tx.Error)
+		}
+		t.Cleanup(func() { tx.Rollback() })
+
+		if err := tx.Create(&models.Sector{Code: "construction"}).Error; err != nil {
+			t.Fatalf("creating sector: %v", err)
+		}
+		return New(tx)
+	})
+}
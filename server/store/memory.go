@@ -0,0 +1,1823 @@
+package store
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+	"gorm.io/gorm"
+)
+
+// MemoryStore is a Store kept in process memory, for tests and local tooling.
+// Relations are resolved on read the way the GORM repositories preload them.
+type MemoryStore struct {
+	mu          sync.RWMutex
+	lastID      uint
+	users       map[uint]models.User
+	roles       map[uint]models.Role
+	permissions map[uint]models.Permission
+	offers      map[uint]models.Offer
+	proposals   map[uint]models.Proposal
+	evaluations map[uint]models.ExpertEvaluation
+	sectors     map[uint]models.Sector
+	documents   map[uint]models.Document
+	chainTxs    map[uint]models.ChainTransaction
+	walletReqs  map[uint]models.WalletChangeRequest
+	sessions    map[uint]models.AuthSession
+	refreshes   map[uint]models.RefreshToken
+	revoked     map[string]models.RevokedToken
+	challenges  map[uint]models.WalletChallenge
+	qualifs     map[uint]models.UserQualification
+	custodial   map[uint]models.CustodialWallet
+	relayed     map[uint]models.RelayedTransaction
+	cursors     map[uint]models.ChainCursor
+	roleLogs    []models.RoleAuditLog
+	auditEvents []models.AuditEvent
+	experts     []models.OfferExpert
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemory returns an empty in-memory Store
+func NewMemory() *MemoryStore {
+	return &MemoryStore{
+		users:       map[uint]models.User{},
+		roles:       map[uint]models.Role{},
+		permissions: map[uint]models.Permission{},
+		offers:      map[uint]models.Offer{},
+		proposals:   map[uint]models.Proposal{},
+		evaluations: map[uint]models.ExpertEvaluation{},
+		sectors:     map[uint]models.Sector{},
+		documents:   map[uint]models.Document{},
+		chainTxs:    map[uint]models.ChainTransaction{},
+		walletReqs:  map[uint]models.WalletChangeRequest{},
+		sessions:    map[uint]models.AuthSession{},
+		refreshes:   map[uint]models.RefreshToken{},
+		revoked:     map[string]models.RevokedToken{},
+		challenges:  map[uint]models.WalletChallenge{},
+		qualifs:     map[uint]models.UserQualification{},
+		custodial:   map[uint]models.CustodialWallet{},
+		relayed:     map[uint]models.RelayedTransaction{},
+		cursors:     map[uint]models.ChainCursor{},
+	}
+}
+
+func (s *MemoryStore) Users() UserRepository             { return memoryUsers{s} }
+func (s *MemoryStore) Roles() RoleRepository             { return memoryRoles{s} }
+func (s *MemoryStore) Permissions() PermissionRepository { return memoryPermissions{s} }
+func (s *MemoryStore) Offers() OfferRepository           { return memoryOffers{s} }
+func (s *MemoryStore) Proposals() ProposalRepository     { return memoryProposals{s} }
+func (s *MemoryStore) Evaluations() EvaluationRepository { return memoryEvaluations{s} }
+func (s *MemoryStore) Sectors() SectorRepository         { return memorySectors{s} }
+func (s *MemoryStore) Documents() DocumentRepository     { return memoryDocuments{s} }
+func (s *MemoryStore) ChainTransactions() ChainTransactionRepository {
+	return memoryChainTransactions{s}
+}
+func (s *MemoryStore) ChainCursors() ChainCursorRepository { return memoryChainCursors{s} }
+func (s *MemoryStore) WalletChangeRequests() WalletChangeRequestRepository {
+	return memoryWalletChangeRequests{s}
+}
+func (s *MemoryStore) RoleAuditLogs() RoleAuditLogRepository { return memoryRoleAuditLogs{s} }
+func (s *MemoryStore) AuthSessions() AuthSessionRepository   { return memoryAuthSessions{s} }
+func (s *MemoryStore) WalletChallenges() WalletChallengeRepository {
+	return memoryWalletChallenges{s}
+}
+func (s *MemoryStore) CustodialWallets() CustodialWalletRepository {
+	return memoryCustodialWallets{s}
+}
+func (s *MemoryStore) RelayedTransactions() RelayedTransactionRepository {
+	return memoryRelayedTransactions{s}
+}
+func (s *MemoryStore) AuditEvents() AuditEventRepository { return memoryAuditEvents{s} }
+
+// AddPermission stores a permission, permissions are seeded by migrations in the SQL store
+func (s *MemoryStore) AddPermission(permission *models.Permission) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if permission.ID == 0 {
+		s.lastID++
+		permission.ID = s.lastID
+	}
+	s.permissions[permission.ID] = *permission
+}
+
+// AddSector stores a sector, sectors are seeded by migrations in the SQL store
+func (s *MemoryStore) AddSector(sector *models.Sector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stamp(&sector.Model)
+	s.sectors[sector.ID] = *sector
+}
+
+// AddProposal stores a proposal without documents, e.g. one mirrored by the indexer
+func (s *MemoryStore) AddProposal(proposal *models.Proposal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stamp(&proposal.Model)
+	s.proposals[proposal.ID] = *proposal
+}
+
+// AddChainTransaction stores a tracked transaction, the transaction watcher records them in the SQL store
+func (s *MemoryStore) AddChainTransaction(transaction *models.ChainTransaction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stamp(&transaction.Model)
+	s.chainTxs[transaction.ID] = *transaction
+}
+
+// AddQualification stores a qualification of a user, they are created outside of the API
+func (s *MemoryStore) AddQualification(qualification *models.UserQualification) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stamp(&qualification.Model)
+	s.qualifs[qualification.ID] = *qualification
+}
+
+// AddRoleAuditLog records a role change, the role reconciler writes them in the SQL store
+func (s *MemoryStore) AddRoleAuditLog(entry *models.RoleAuditLog) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addRoleAuditLog(entry)
+}
+
+// addRoleAuditLog appends a role change to the log, the caller holds the lock
+func (s *MemoryStore) addRoleAuditLog(entry *models.RoleAuditLog) {
+	entry.ID = uint(len(s.roleLogs)) + 1
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	s.roleLogs = append(s.roleLogs, *entry)
+}
+
+// stamp assigns an ID to new records and sets their timestamps, the caller holds the lock
+func (s *MemoryStore) stamp(model *gorm.Model) {
+	now := time.Now()
+	if model.ID == 0 {
+		s.lastID++
+		model.ID = s.lastID
+	} else if model.ID > s.lastID {
+		s.lastID = model.ID
+	}
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = now
+	}
+	model.UpdatedAt = now
+}
+
+// sortedValues returns the values of m ordered by ID
+func sortedValues[T any](m map[uint]T) []T {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	values := make([]T, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, m[id])
+	}
+	return values
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// withRoles returns the user with its roles read from the role table, the caller holds the lock
+func (s *MemoryStore) withRoles(user models.User) models.User {
+	roles := make([]models.Role, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		if current, ok := s.roles[role.ID]; ok {
+			roles = append(roles, current)
+		}
+	}
+	user.Roles = roles
+	return user
+}
+
+// publicUser strips the fields the GORM store does not select for listings
+func publicUser(user models.User) models.User {
+	return models.User{
+		Model:               gorm.Model{ID: user.ID, CreatedAt: user.CreatedAt},
+		FirstName:           user.FirstName,
+		LastName:            user.LastName,
+		Email:               user.Email,
+		PhoneNumber:         user.PhoneNumber,
+		PublicWalletAddress: user.PublicWalletAddress,
+		Roles:               user.Roles,
+	}
+}
+
+type memoryUsers struct{ s *MemoryStore }
+
+func (r memoryUsers) Get(id uint) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	user = r.s.withRoles(user)
+	return &user, nil
+}
+
+func (r memoryUsers) Profile(id uint) (*models.User, error) {
+	user, err := r.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	profile := publicUser(*user)
+	return &profile, nil
+}
+
+func (r memoryUsers) find(match func(models.User) bool) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range sortedValues(r.s.users) {
+		if match(user) {
+			user = r.s.withRoles(user)
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryUsers) GetByEmail(email string) (*models.User, error) {
+	return r.find(func(u models.User) bool { return u.Email == email })
+}
+
+func (r memoryUsers) GetByWallet(wallet string) (*models.User, error) {
+	return r.find(func(u models.User) bool {
+		return u.PublicWalletAddress != "" && strings.EqualFold(u.PublicWalletAddress, wallet)
+	})
+}
+
+func (r memoryUsers) List(filter UserFilter, page Page) ([]models.User, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []models.User
+	for _, user := range sortedValues(r.s.users) {
+		if filter.Search != "" &&
+			!containsFold(user.FirstName, filter.Search) &&
+			!containsFold(user.LastName, filter.Search) &&
+			!containsFold(user.Email, filter.Search) &&
+			!strings.Contains(user.PhoneNumber, filter.Search) &&
+			!containsFold(user.FirstName+" "+user.LastName, filter.Search) {
+			continue
+		}
+		matches = append(matches, publicUser(r.s.withRoles(user)))
+	}
+
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+func (r memoryUsers) Create(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&user.Model)
+	r.s.users[user.ID] = *user
+	return nil
+}
+
+func (r memoryUsers) Save(user *models.User) error {
+	return r.Create(user)
+}
+
+func (r memoryUsers) Delete(user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, document := range r.s.documents {
+		if document.DocumentableType == "User" && document.DocumentableID == user.ID {
+			delete(r.s.documents, id)
+		}
+	}
+	delete(r.s.users, user.ID)
+	return nil
+}
+
+func (r memoryUsers) EmailTaken(email string, exceptID uint) (bool, error) {
+	user, err := r.GetByEmail(email)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	return user != nil && user.ID != exceptID, err
+}
+
+func (r memoryUsers) WalletInUse(wallet string, exceptID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if user.ID != exceptID && strings.EqualFold(user.PublicWalletAddress, wallet) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryUsers) GrantRole(userRole *models.UserRole) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[userRole.UserID]
+	if !ok {
+		return ErrNotFound
+	}
+	for _, role := range user.Roles {
+		if role.ID == userRole.RoleID {
+			return nil
+		}
+	}
+	user.Roles = append(append([]models.Role(nil), user.Roles...), models.Role{Model: gorm.Model{ID: userRole.RoleID}})
+	r.s.users[user.ID] = user
+	return nil
+}
+
+func (r memoryUsers) RevokeRole(userID uint, roleID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[userID]
+	if !ok {
+		return ErrNotFound
+	}
+	roles := make([]models.Role, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		if role.ID != roleID {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == len(user.Roles) {
+		return ErrNotFound
+	}
+	user.Roles = roles
+	r.s.users[user.ID] = user
+	return nil
+}
+
+// update applies change to the stored user, ErrNotFound if it does not exist
+func (r memoryUsers) update(id uint, change func(user *models.User)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return ErrNotFound
+	}
+	change(&user)
+	r.s.stamp(&user.Model)
+	r.s.users[id] = user
+	return nil
+}
+
+func (r memoryUsers) SetEmail(id uint, email string) error {
+	return r.update(id, func(user *models.User) { user.Email = email })
+}
+
+func (r memoryUsers) SetPhoneNumber(id uint, phoneNumber string) error {
+	return r.update(id, func(user *models.User) { user.PhoneNumber = phoneNumber })
+}
+
+func (r memoryUsers) SetWallet(id uint, wallet string) error {
+	return r.update(id, func(user *models.User) { user.PublicWalletAddress = wallet })
+}
+
+func (r memoryUsers) ListWithRoles() ([]models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range sortedValues(r.s.users) {
+		users = append(users, publicUser(r.s.withRoles(user)))
+	}
+	return users, nil
+}
+
+func (r memoryUsers) ApplyRoleChange(entry *models.RoleAuditLog, txHash string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[entry.UserID]
+	if !ok {
+		return false, ErrNotFound
+	}
+
+	held := false
+	roles := make([]models.Role, 0, len(user.Roles)+1)
+	for _, role := range user.Roles {
+		if role.ID == entry.RoleID {
+			held = true
+			continue
+		}
+		roles = append(roles, role)
+	}
+
+	granted := entry.Action == "granted"
+	if held == granted {
+		return false, nil
+	}
+	if granted {
+		roles = append(roles, models.Role{Model: gorm.Model{ID: entry.RoleID}})
+	}
+	user.Roles = roles
+	r.s.users[user.ID] = user
+	r.s.addRoleAuditLog(entry)
+	return true, nil
+}
+
+func (r memoryUsers) GetQualification(id uint) (*models.UserQualification, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	qualification, ok := r.s.qualifs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &qualification, nil
+}
+
+type memoryRoles struct{ s *MemoryStore }
+
+func (r memoryRoles) List() ([]models.Role, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return sortedValues(r.s.roles), nil
+}
+
+func (r memoryRoles) Get(id uint) (*models.Role, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	role, ok := r.s.roles[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &role, nil
+}
+
+func (r memoryRoles) GetByName(name string) (*models.Role, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, role := range r.s.roles {
+		if role.Name == name {
+			return &role, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryRoles) Create(role *models.Role) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&role.Model)
+	r.s.roles[role.ID] = *role
+	return nil
+}
+
+func (r memoryRoles) Save(role *models.Role) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&role.Model)
+
+	saved := *role
+	saved.Permissions = r.s.roles[role.ID].Permissions
+	r.s.roles[role.ID] = saved
+	return nil
+}
+
+func (r memoryRoles) Delete(role *models.Role) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.roles, role.ID)
+	return nil
+}
+
+func (r memoryRoles) CountUsers(roleID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, user := range r.s.users {
+		for _, role := range user.Roles {
+			if role.ID == roleID {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+func (r memoryRoles) SetPermissions(role *models.Role, permissions []models.Permission) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.roles[role.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.Permissions = append([]models.Permission(nil), permissions...)
+	r.s.roles[role.ID] = stored
+	role.Permissions = permissions
+	return nil
+}
+
+type memoryPermissions struct{ s *MemoryStore }
+
+func (r memoryPermissions) List() ([]models.Permission, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	permissions := sortedValues(r.s.permissions)
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].Name < permissions[j].Name })
+	return permissions, nil
+}
+
+func (r memoryPermissions) GetByNames(names []string) ([]models.Permission, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	byName := make(map[string]models.Permission, len(r.s.permissions))
+	for _, permission := range r.s.permissions {
+		byName[permission.Name] = permission
+	}
+
+	requested := make(map[string]struct{}, len(names))
+	permissions := []models.Permission{}
+	for _, name := range names {
+		if _, seen := requested[name]; seen {
+			continue
+		}
+		requested[name] = struct{}{}
+
+		permission, ok := byName[name]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		permissions = append(permissions, permission)
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i].Name < permissions[j].Name })
+	return permissions, nil
+}
+
+type memoryOffers struct{ s *MemoryStore }
+
+func (r memoryOffers) Get(id uint) (*models.Offer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	offer, ok := r.s.offers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	offer.Sector = r.s.sectors[offer.SectorID]
+	offer.Documents = r.s.documentsFor("Offer", offer.ID)
+	offer.ExpertAssignments = r.s.expertsFor(offer.ID)
+	return &offer, nil
+}
+
+func (r memoryOffers) List(filter OfferFilter, page Page) ([]models.Offer, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []models.Offer
+	for _, offer := range sortedValues(r.s.offers) {
+		sector := r.s.sectors[offer.SectorID]
+		switch {
+		case !filter.IncludeClosed && offer.Status == models.OfferStatusClosed,
+			filter.Search != "" && !containsFold(offer.Title, filter.Search),
+			filter.Location != "" && !containsFold(offer.Location, filter.Location),
+			filter.SectorCode != "" && sector.Code != filter.SectorCode:
+			continue
+		}
+
+		offer.Sector = sector
+		offer.Creator = publicUser(r.s.users[offer.CreatedBy])
+		offer.Creator.Roles = nil
+		matches = append(matches, offer)
+	}
+
+	// Newest first, as the GORM store orders them
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].CreatedAt.After(matches[j].CreatedAt) })
+
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+func (r memoryOffers) Create(offer *models.Offer) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&offer.Model)
+	r.s.offers[offer.ID] = *offer
+	return nil
+}
+
+func (r memoryOffers) CreateWithDocuments(offer *models.Offer, documents []models.Document) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&offer.Model)
+	r.s.offers[offer.ID] = *offer
+	r.s.addDocuments("Offer", offer.ID, documents)
+	offer.Documents = documents
+	return nil
+}
+
+// addDocuments attaches documents to a record, the caller holds the lock
+func (s *MemoryStore) addDocuments(documentableType string, documentableID uint, documents []models.Document) {
+	for i := range documents {
+		documents[i].DocumentableType = documentableType
+		documents[i].DocumentableID = documentableID
+		s.stamp(&documents[i].Model)
+		s.documents[documents[i].ID] = documents[i]
+	}
+}
+
+func (r memoryOffers) ContractExists(contractAddress string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, offer := range r.s.offers {
+		if strings.EqualFold(offer.ContractAddress, contractAddress) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryOffers) GetByContract(contractAddress string) (*models.Offer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, offer := range sortedValues(r.s.offers) {
+		if strings.EqualFold(offer.ContractAddress, contractAddress) {
+			return &offer, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryOffers) ContractAddresses() ([]string, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	addresses := []string{}
+	for _, offer := range sortedValues(r.s.offers) {
+		addresses = append(addresses, offer.ContractAddress)
+	}
+	return addresses, nil
+}
+
+func (r memoryOffers) SetWindows(id uint, proposalStart, proposalEnd, reviewStart, reviewEnd time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	offer, ok := r.s.offers[id]
+	if !ok {
+		return nil
+	}
+	offer.ProposalStart, offer.ProposalEnd = proposalStart, proposalEnd
+	offer.ReviewStart, offer.ReviewEnd = reviewStart, reviewEnd
+	r.s.stamp(&offer.Model)
+	r.s.offers[id] = offer
+	return nil
+}
+
+func (r memoryOffers) ListOpen() ([]models.Offer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	offers := []models.Offer{}
+	for _, offer := range sortedValues(r.s.offers) {
+		if offer.Status != models.OfferStatusClosed {
+			offers = append(offers, offer)
+		}
+	}
+	return offers, nil
+}
+
+func (r memoryOffers) MoveStatus(id uint, current string, next string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	offer, ok := r.s.offers[id]
+	if !ok || offer.Status != current {
+		return false, nil
+	}
+	offer.Status = next
+	r.s.stamp(&offer.Model)
+	r.s.offers[id] = offer
+	return true, nil
+}
+
+// expertsFor returns the expert assignments of an offer, the caller holds the lock
+func (s *MemoryStore) expertsFor(offerID uint) []models.OfferExpert {
+	var assignments []models.OfferExpert
+	for _, assignment := range s.experts {
+		if assignment.OfferID == offerID {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments
+}
+
+func (r memoryOffers) ListExperts(offerID uint) ([]models.OfferExpert, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	assignments := r.s.expertsFor(offerID)
+	for i := range assignments {
+		assignments[i].Expert = publicUser(r.s.users[assignments[i].ExpertID])
+		assignments[i].Expert.Roles = nil
+	}
+	return assignments, nil
+}
+
+func (r memoryOffers) AssignExpert(assignment *models.OfferExpert) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.experts {
+		if existing.OfferID == assignment.OfferID && existing.ExpertID == assignment.ExpertID {
+			return nil
+		}
+	}
+	if assignment.CreatedAt.IsZero() {
+		assignment.CreatedAt = time.Now()
+	}
+	stored := *assignment
+	stored.Expert = models.User{}
+	r.s.experts = append(r.s.experts, stored)
+	return nil
+}
+
+func (r memoryOffers) UnassignExpert(offerID uint, expertID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i, existing := range r.s.experts {
+		if existing.OfferID == offerID && existing.ExpertID == expertID {
+			r.s.experts = append(r.s.experts[:i], r.s.experts[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r memoryOffers) DeclareWinner(offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.applyWinner(offer, winnerWallet, txHash)
+}
+
+func (r memoryOffers) Close(offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var proposal *models.Proposal
+	if winnerWallet != "" {
+		var err error
+		if proposal, err = r.s.applyWinner(offer, winnerWallet, txHash); err != nil {
+			return nil, err
+		}
+	}
+
+	stored, ok := r.s.offers[offer.ID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	stored.Status = models.OfferStatusClosed
+	stored.CloseTxHash = txHash
+	r.s.offers[offer.ID] = stored
+	offer.Status, offer.CloseTxHash = stored.Status, stored.CloseTxHash
+	return proposal, nil
+}
+
+// applyWinner records the winner of offer, see OfferRepository.DeclareWinner. The caller holds the lock.
+func (s *MemoryStore) applyWinner(offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error) {
+	stored, ok := s.offers[offer.ID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	var winner *models.Proposal
+	for _, proposal := range sortedValues(s.proposals) {
+		if proposal.ContractID == offer.ID && strings.EqualFold(s.users[proposal.ProposerID].PublicWalletAddress, winnerWallet) {
+			winner = &proposal
+			break
+		}
+	}
+
+	stored.WinnerTxHash = txHash
+	if stored.Status != models.OfferStatusClosed {
+		stored.Status = models.OfferStatusWinnerDeclared
+	}
+	if winner != nil {
+		for id, proposal := range s.proposals {
+			if proposal.ContractID != offer.ID {
+				continue
+			}
+			proposal.Status = models.ProposalStatusRejected
+			if id == winner.ID {
+				proposal.Status = models.ProposalStatusAccepted
+			}
+			s.proposals[id] = proposal
+		}
+		winner.Status = models.ProposalStatusAccepted
+		stored.WinningProposalID = &winner.ID
+	}
+
+	s.offers[offer.ID] = stored
+	offer.Status, offer.WinnerTxHash, offer.WinningProposalID = stored.Status, stored.WinnerTxHash, stored.WinningProposalID
+	return winner, nil
+}
+
+type memoryProposals struct{ s *MemoryStore }
+
+func (r memoryProposals) Get(id uint) (*models.Proposal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	proposal, ok := r.s.proposals[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	proposal.Contract = r.s.offers[proposal.ContractID]
+	proposal.Contract.ExpertAssignments = r.s.expertsFor(proposal.ContractID)
+	proposal.Proposer = r.s.users[proposal.ProposerID]
+	return &proposal, nil
+}
+
+func (r memoryProposals) Exists(offerID uint, proposerID uint, txHash string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, proposal := range r.s.proposals {
+		sameTx := strings.EqualFold(proposal.ProposalTxHash, txHash)
+		if proposal.IndexedOnly && sameTx {
+			continue
+		}
+		if (proposal.ContractID == offerID && proposal.ProposerID == proposerID) || sameTx {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryProposals) CreateWithDocuments(proposal *models.Proposal, documents []models.Document) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, indexed := range sortedValues(r.s.proposals) {
+		if indexed.IndexedOnly && indexed.ContractID == proposal.ContractID && indexed.ProposerID == proposal.ProposerID &&
+			strings.EqualFold(indexed.ProposalTxHash, proposal.ProposalTxHash) {
+			adoptProposal(proposal, &indexed)
+			break
+		}
+	}
+
+	r.s.stamp(&proposal.Model)
+	r.s.proposals[proposal.ID] = *proposal
+	r.s.addDocuments("Proposal", proposal.ID, documents)
+	proposal.Documents = documents
+	return nil
+}
+
+func (r memoryProposals) GetByWallet(offerID uint, wallet string) (*models.Proposal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, proposal := range sortedValues(r.s.proposals) {
+		proposer := r.s.users[proposal.ProposerID]
+		if proposal.ContractID == offerID && proposer.PublicWalletAddress != "" && strings.EqualFold(proposer.PublicWalletAddress, wallet) {
+			return &proposal, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryProposals) UpsertIndexed(proposal *models.Proposal) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := *proposal
+	stored.IndexedOnly = true
+	for _, existing := range sortedValues(r.s.proposals) {
+		if existing.ContractID == proposal.ContractID && existing.ProposerID == proposal.ProposerID {
+			stored = existing
+			stored.Details, stored.Price = proposal.Details, proposal.Price
+			stored.ProposalTxHash = proposal.ProposalTxHash
+			stored.BlockNumber, stored.BlockHash = proposal.BlockNumber, proposal.BlockHash
+			break
+		}
+	}
+
+	r.s.stamp(&stored.Model)
+	r.s.proposals[stored.ID] = stored
+	*proposal = stored
+	return nil
+}
+
+func (r memoryProposals) ListByOffer(offerID uint) ([]models.Proposal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	proposals := []models.Proposal{}
+	for _, proposal := range r.s.proposals {
+		if proposal.ContractID != offerID {
+			continue
+		}
+		proposal.Proposer = r.s.users[proposal.ProposerID]
+		for _, evaluation := range r.s.evaluations {
+			if evaluation.ProposalID == proposal.ID {
+				proposal.Evaluations = append(proposal.Evaluations, evaluation)
+			}
+		}
+		proposals = append(proposals, proposal)
+	}
+	sort.SliceStable(proposals, func(i, j int) bool {
+		if !proposals[i].SubmittedAt.Equal(proposals[j].SubmittedAt) {
+			return proposals[i].SubmittedAt.Before(proposals[j].SubmittedAt)
+		}
+		return proposals[i].ID < proposals[j].ID
+	})
+	return proposals, nil
+}
+
+func (r memoryProposals) ListAwaitingReview(expertID uint, now time.Time) ([]models.Proposal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	evaluated := make(map[uint]bool)
+	for _, evaluation := range r.s.evaluations {
+		if evaluation.ExpertID == expertID {
+			evaluated[evaluation.ProposalID] = true
+		}
+	}
+
+	proposals := []models.Proposal{}
+	for _, proposal := range sortedValues(r.s.proposals) {
+		offer := r.s.offers[proposal.ContractID]
+		switch {
+		case offer.ReviewStart.After(now), !offer.ReviewEnd.After(now),
+			offer.Status == models.OfferStatusWinnerDeclared, offer.Status == models.OfferStatusClosed,
+			proposal.ProposerID == expertID, evaluated[proposal.ID]:
+			continue
+		}
+
+		proposal.Contract = offer
+		proposal.Contract.ExpertAssignments = r.s.expertsFor(offer.ID)
+		proposal.Documents = r.s.documentsFor("Proposal", proposal.ID)
+		proposals = append(proposals, proposal)
+	}
+
+	sort.SliceStable(proposals, func(i, j int) bool {
+		return proposals[i].Contract.ReviewEnd.Before(proposals[j].Contract.ReviewEnd)
+	})
+	return proposals, nil
+}
+
+func (r memoryProposals) ListFromBlock(offerID uint, block uint64) ([]models.Proposal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	proposals := []models.Proposal{}
+	for _, proposal := range sortedValues(r.s.proposals) {
+		if proposal.ContractID == offerID && proposal.BlockNumber != 0 && proposal.BlockNumber >= block {
+			proposals = append(proposals, proposal)
+		}
+	}
+	sort.SliceStable(proposals, func(i, j int) bool { return proposals[i].BlockNumber < proposals[j].BlockNumber })
+	return proposals, nil
+}
+
+func (r memoryProposals) SetBlock(id uint, number uint64, hash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	proposal, ok := r.s.proposals[id]
+	if !ok {
+		return nil
+	}
+	proposal.BlockNumber, proposal.BlockHash = number, hash
+	r.s.proposals[id] = proposal
+	return nil
+}
+
+func (r memoryProposals) Delete(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.proposals, id)
+	return nil
+}
+
+type memoryEvaluations struct{ s *MemoryStore }
+
+func (r memoryEvaluations) Exists(proposalID uint, expertID uint, txHash string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, evaluation := range r.s.evaluations {
+		sameTx := strings.EqualFold(evaluation.ReviewTxHash, txHash)
+		if evaluation.IndexedOnly && sameTx {
+			continue
+		}
+		if (evaluation.ProposalID == proposalID && evaluation.ExpertID == expertID) || sameTx {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryEvaluations) Create(evaluation *models.ExpertEvaluation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&evaluation.Model)
+	r.s.evaluations[evaluation.ID] = *evaluation
+	return nil
+}
+
+func (r memoryEvaluations) UpsertIndexed(evaluation *models.ExpertEvaluation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored := *evaluation
+	stored.IndexedOnly = true
+	for _, existing := range sortedValues(r.s.evaluations) {
+		if existing.ProposalID == evaluation.ProposalID && existing.ExpertID == evaluation.ExpertID {
+			stored = existing
+			stored.Score, stored.ReviewTxHash = evaluation.Score, evaluation.ReviewTxHash
+			stored.BlockNumber, stored.BlockHash = evaluation.BlockNumber, evaluation.BlockHash
+			break
+		}
+	}
+
+	r.s.stamp(&stored.Model)
+	r.s.evaluations[stored.ID] = stored
+	*evaluation = stored
+	return nil
+}
+
+func (r memoryEvaluations) CreateWithTracking(evaluation *models.ExpertEvaluation, tracked *models.ChainTransaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, indexed := range r.s.evaluations {
+		if indexed.IndexedOnly && indexed.ProposalID == evaluation.ProposalID && indexed.ExpertID == evaluation.ExpertID &&
+			strings.EqualFold(indexed.ReviewTxHash, evaluation.ReviewTxHash) {
+			evaluation.ID, evaluation.CreatedAt = indexed.ID, indexed.CreatedAt
+			evaluation.IndexedOnly = false
+			break
+		}
+	}
+
+	r.s.stamp(&evaluation.Model)
+	r.s.evaluations[evaluation.ID] = *evaluation
+
+	tracked.EntityType = "Evaluation"
+	tracked.EntityID = &evaluation.ID
+	r.s.trackTransaction(tracked)
+	return nil
+}
+
+func (r memoryEvaluations) ListByExpert(expertID uint, page Page) ([]models.ExpertEvaluation, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []models.ExpertEvaluation
+	for _, evaluation := range sortedValues(r.s.evaluations) {
+		if evaluation.ExpertID != expertID {
+			continue
+		}
+		evaluation.Proposal = r.s.proposals[evaluation.ProposalID]
+		evaluation.Proposal.Contract = r.s.offers[evaluation.Proposal.ContractID]
+		matches = append(matches, evaluation)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].CreatedAt.After(matches[j].CreatedAt) })
+
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+func (r memoryEvaluations) ListFromBlock(offerID uint, block uint64) ([]models.ExpertEvaluation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	evaluations := []models.ExpertEvaluation{}
+	for _, evaluation := range sortedValues(r.s.evaluations) {
+		if r.s.proposals[evaluation.ProposalID].ContractID == offerID && evaluation.BlockNumber != 0 && evaluation.BlockNumber >= block {
+			evaluations = append(evaluations, evaluation)
+		}
+	}
+	sort.SliceStable(evaluations, func(i, j int) bool { return evaluations[i].BlockNumber < evaluations[j].BlockNumber })
+	return evaluations, nil
+}
+
+func (r memoryEvaluations) SetBlock(id uint, number uint64, hash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	evaluation, ok := r.s.evaluations[id]
+	if !ok {
+		return nil
+	}
+	evaluation.BlockNumber, evaluation.BlockHash = number, hash
+	r.s.evaluations[id] = evaluation
+	return nil
+}
+
+func (r memoryEvaluations) Delete(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.evaluations, id)
+	return nil
+}
+
+type memorySectors struct{ s *MemoryStore }
+
+func (r memorySectors) List() ([]models.Sector, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	sectors := sortedValues(r.s.sectors)
+	sort.SliceStable(sectors, func(i, j int) bool { return sectors[i].Code < sectors[j].Code })
+	return sectors, nil
+}
+
+func (r memorySectors) Exists(id uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, ok := r.s.sectors[id]
+	return ok, nil
+}
+
+type memoryDocuments struct{ s *MemoryStore }
+
+func (r memoryDocuments) Get(id uint) (*models.Document, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	document, ok := r.s.documents[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &document, nil
+}
+
+func (r memoryDocuments) Create(document *models.Document) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&document.Model)
+	r.s.documents[document.ID] = *document
+	return nil
+}
+
+func (r memoryDocuments) ListFor(documentableType string, documentableID uint) ([]models.Document, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.documentsFor(documentableType, documentableID), nil
+}
+
+// documentsFor returns the documents attached to a record, the caller holds the lock
+func (s *MemoryStore) documentsFor(documentableType string, documentableID uint) []models.Document {
+	documents := []models.Document{}
+	for _, document := range sortedValues(s.documents) {
+		if document.DocumentableType == documentableType && document.DocumentableID == documentableID {
+			documents = append(documents, document)
+		}
+	}
+	return documents
+}
+
+type memoryChainTransactions struct{ s *MemoryStore }
+
+func (r memoryChainTransactions) List(filter ChainTransactionFilter, page Page) ([]models.ChainTransaction, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []models.ChainTransaction
+	for _, transaction := range sortedValues(r.s.chainTxs) {
+		switch {
+		case filter.UserID != nil && (transaction.UserID == nil || *transaction.UserID != *filter.UserID),
+			filter.Kind != "" && transaction.Kind != filter.Kind,
+			filter.Status != "" && transaction.Status != filter.Status,
+			filter.EntityType != "" && transaction.EntityType != filter.EntityType,
+			filter.EntityID != nil && (transaction.EntityID == nil || *transaction.EntityID != *filter.EntityID):
+			continue
+		}
+		matches = append(matches, transaction)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].CreatedAt.After(matches[j].CreatedAt) })
+
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+func (r memoryChainTransactions) Track(transaction *models.ChainTransaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.trackTransaction(transaction)
+	return nil
+}
+
+// trackTransaction upserts a tracked transaction on its hash, the caller holds the lock
+func (s *MemoryStore) trackTransaction(transaction *models.ChainTransaction) {
+	transaction.Hash = strings.ToLower(transaction.Hash)
+	if transaction.Status == "" {
+		transaction.Status = models.ChainTxPending
+	}
+
+	for id, existing := range s.chainTxs {
+		if existing.Hash != transaction.Hash {
+			continue
+		}
+		existing.Kind = transaction.Kind
+		if transaction.UserID != nil {
+			existing.UserID = transaction.UserID
+		}
+		if transaction.EntityType != "" {
+			existing.EntityType, existing.EntityID = transaction.EntityType, transaction.EntityID
+		}
+		s.stamp(&existing.Model)
+		s.chainTxs[id] = existing
+		*transaction = existing
+		return
+	}
+
+	s.stamp(&transaction.Model)
+	s.chainTxs[transaction.ID] = *transaction
+}
+
+func (r memoryChainTransactions) GetByHash(hash string) (*models.ChainTransaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, transaction := range r.s.chainTxs {
+		if strings.EqualFold(transaction.Hash, hash) {
+			return &transaction, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryChainTransactions) ListUnconfirmed(confirmations uint64) ([]models.ChainTransaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transactions := []models.ChainTransaction{}
+	for _, transaction := range sortedValues(r.s.chainTxs) {
+		switch transaction.Status {
+		case models.ChainTxPending:
+		case models.ChainTxMined, models.ChainTxReverted:
+			if transaction.Confirmations >= confirmations {
+				continue
+			}
+		default:
+			continue
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+func (r memoryChainTransactions) Save(transaction *models.ChainTransaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&transaction.Model)
+	r.s.chainTxs[transaction.ID] = *transaction
+	return nil
+}
+
+type memoryChainCursors struct{ s *MemoryStore }
+
+func (r memoryChainCursors) Ensure(contractAddress string, contractType string, startBlock uint64) (*models.ChainCursor, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, cursor := range r.s.cursors {
+		if cursor.ContractAddress == contractAddress {
+			return &cursor, nil
+		}
+	}
+	cursor := models.ChainCursor{ContractAddress: contractAddress, ContractType: contractType, NextBlock: startBlock}
+	r.s.stamp(&cursor.Model)
+	r.s.cursors[cursor.ID] = cursor
+	return &cursor, nil
+}
+
+func (r memoryChainCursors) ListActive(contractType string) ([]models.ChainCursor, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	cursors := []models.ChainCursor{}
+	for _, cursor := range sortedValues(r.s.cursors) {
+		if cursor.ContractType == contractType && !cursor.Finished {
+			cursors = append(cursors, cursor)
+		}
+	}
+	return cursors, nil
+}
+
+func (r memoryChainCursors) Save(cursor *models.ChainCursor) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&cursor.Model)
+	r.s.cursors[cursor.ID] = *cursor
+	return nil
+}
+
+type memoryWalletChangeRequests struct{ s *MemoryStore }
+
+func (r memoryWalletChangeRequests) List(status string, page Page) ([]models.WalletChangeRequest, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []models.WalletChangeRequest
+	for _, request := range sortedValues(r.s.walletReqs) {
+		if status != "" && request.Status != status {
+			continue
+		}
+		user := r.s.users[request.UserID]
+		request.User = models.User{
+			Model:               gorm.Model{ID: user.ID},
+			Email:               user.Email,
+			FirstName:           user.FirstName,
+			LastName:            user.LastName,
+			PublicWalletAddress: user.PublicWalletAddress,
+		}
+		matches = append(matches, request)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].CreatedAt.Before(matches[j].CreatedAt) })
+
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+func (r memoryWalletChangeRequests) HasPending(userID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, request := range r.s.walletReqs {
+		if request.UserID == userID && request.Status == models.WalletChangePending {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r memoryWalletChangeRequests) Create(request *models.WalletChangeRequest) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&request.Model)
+	stored := *request
+	stored.User = models.User{}
+	r.s.walletReqs[request.ID] = stored
+	return nil
+}
+
+func (r memoryWalletChangeRequests) Review(id uint, review func(request *models.WalletChangeRequest, user *models.User) error) (*models.WalletChangeRequest, *models.User, error) {
+	// review may read the store, it runs on copies and the decision is only kept if no other review
+	// was saved in the meantime
+	r.s.mu.RLock()
+	request, ok := r.s.walletReqs[id]
+	user := r.s.withRoles(r.s.users[request.UserID])
+	r.s.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	status := request.Status
+
+	if err := review(&request, &user); err != nil {
+		return nil, nil, err
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.walletReqs[id].Status != status {
+		return nil, nil, ErrConflict
+	}
+	if request.Status == models.WalletChangeApproved {
+		stored := r.s.users[user.ID]
+		stored.PublicWalletAddress = request.NewAddress
+		r.s.users[user.ID] = stored
+		user.PublicWalletAddress = request.NewAddress
+	}
+	r.s.stamp(&request.Model)
+	r.s.walletReqs[id] = request
+	return &request, &user, nil
+}
+
+type memoryRoleAuditLogs struct{ s *MemoryStore }
+
+func (r memoryRoleAuditLogs) List(userID *uint, page Page) ([]models.RoleAuditLog, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []models.RoleAuditLog
+	for i := len(r.s.roleLogs) - 1; i >= 0; i-- {
+		if userID == nil || r.s.roleLogs[i].UserID == *userID {
+			matches = append(matches, r.s.roleLogs[i])
+		}
+	}
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+type memoryAuthSessions struct{ s *MemoryStore }
+
+func (r memoryAuthSessions) Create(session *models.AuthSession, token *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.stamp(&session.Model)
+	r.s.sessions[session.ID] = *session
+	token.SessionID = session.ID
+	r.s.stamp(&token.Model)
+	r.s.refreshes[token.ID] = *token
+	return nil
+}
+
+func (r memoryAuthSessions) Rotate(tokenHash string, rotate func(token *models.RefreshToken, session *models.AuthSession, user *models.User) (*models.RefreshToken, error)) error {
+	// Holding the lock for the whole rotation serializes them the way the row lock does
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var token models.RefreshToken
+	found := false
+	for _, stored := range r.s.refreshes {
+		if stored.TokenHash == tokenHash {
+			token, found = stored, true
+			break
+		}
+	}
+	if !found {
+		return ErrNotFound
+	}
+
+	session, ok := r.s.sessions[token.SessionID]
+	if !ok {
+		return ErrNotFound
+	}
+	user, ok := r.s.users[session.UserID]
+	if !ok {
+		return ErrNotFound
+	}
+	user = r.s.withRoles(user)
+
+	next, err := rotate(&token, &session, &user)
+	if err != nil {
+		return err
+	}
+
+	r.s.refreshes[token.ID] = token
+	r.s.stamp(&session.Model)
+	r.s.sessions[session.ID] = session
+	next.SessionID = session.ID
+	r.s.stamp(&next.Model)
+	r.s.refreshes[next.ID] = *next
+	return nil
+}
+
+func (r memoryAuthSessions) Get(id uint) (*models.AuthSession, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	session, ok := r.s.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &session, nil
+}
+
+func (r memoryAuthSessions) Revoke(id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if session, ok := r.s.sessions[id]; ok && session.RevokedAt == nil {
+		session.RevokedAt = &at
+		r.s.sessions[id] = session
+	}
+	return nil
+}
+
+func (r memoryAuthSessions) RevokeAll(userID uint, at time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var count int64
+	for id, session := range r.s.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+			r.s.sessions[id] = session
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r memoryAuthSessions) RevokeToken(revoked *models.RevokedToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.revoked[revoked.JTI]; ok {
+		return nil
+	}
+	if revoked.CreatedAt.IsZero() {
+		revoked.CreatedAt = time.Now()
+	}
+	r.s.revoked[revoked.JTI] = *revoked
+	return nil
+}
+
+func (r memoryAuthSessions) TokenRevoked(jti string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, ok := r.s.revoked[jti]
+	return ok, nil
+}
+
+func (r memoryAuthSessions) PurgeExpired(now time.Time, sessionCutoff time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for jti, revoked := range r.s.revoked {
+		if revoked.ExpiresAt.Before(now) {
+			delete(r.s.revoked, jti)
+		}
+	}
+	for id, session := range r.s.sessions {
+		if session.ExpiresAt.Before(sessionCutoff) {
+			delete(r.s.sessions, id)
+		}
+	}
+	for id, token := range r.s.refreshes {
+		if token.ExpiresAt.Before(sessionCutoff) {
+			delete(r.s.refreshes, id)
+		}
+	}
+	return nil
+}
+
+type memoryWalletChallenges struct{ s *MemoryStore }
+
+func (r memoryWalletChallenges) Create(challenge *models.WalletChallenge) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&challenge.Model)
+	r.s.challenges[challenge.ID] = *challenge
+	return nil
+}
+
+// usable reports whether challenge can still be consumed at now
+func usable(challenge models.WalletChallenge, now time.Time) bool {
+	return challenge.UsedAt == nil && challenge.ExpiresAt.After(now)
+}
+
+func (r memoryWalletChallenges) GetUsable(nonce string, purpose string, userID uint, now time.Time) (*models.WalletChallenge, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, challenge := range r.s.challenges {
+		if challenge.Nonce == nonce && challenge.Purpose == purpose &&
+			challenge.UserID != nil && *challenge.UserID == userID && usable(challenge, now) {
+			return &challenge, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryWalletChallenges) Consume(nonce string, wallet string, purpose string, userID *uint, now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, challenge := range r.s.challenges {
+		sameUser := (userID == nil && challenge.UserID == nil) ||
+			(userID != nil && challenge.UserID != nil && *challenge.UserID == *userID)
+		if challenge.Nonce != nonce || !strings.EqualFold(challenge.Address, wallet) ||
+			challenge.Purpose != purpose || !sameUser || !usable(challenge, now) {
+			continue
+		}
+		challenge.UsedAt = &now
+		r.s.challenges[id] = challenge
+		return nil
+	}
+	return ErrNotFound
+}
+
+func (r memoryWalletChallenges) PurgeExpired(now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, challenge := range r.s.challenges {
+		if challenge.ExpiresAt.Before(now) {
+			delete(r.s.challenges, id)
+		}
+	}
+	return nil
+}
+
+type memoryCustodialWallets struct{ s *MemoryStore }
+
+func (r memoryCustodialWallets) GetByUser(userID uint) (*models.CustodialWallet, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, wallet := range r.s.custodial {
+		if wallet.UserID == userID {
+			return &wallet, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r memoryCustodialWallets) Create(wallet *models.CustodialWallet) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[wallet.UserID]
+	if !ok {
+		return ErrNotFound
+	}
+	if user.PublicWalletAddress != "" {
+		return ErrConflict
+	}
+	for _, existing := range r.s.custodial {
+		if existing.UserID == wallet.UserID {
+			return ErrConflict
+		}
+	}
+
+	r.s.stamp(&wallet.Model)
+	r.s.custodial[wallet.ID] = *wallet
+	user.PublicWalletAddress = wallet.Address
+	r.s.users[user.ID] = user
+	return nil
+}
+
+type memoryRelayedTransactions struct{ s *MemoryStore }
+
+func (r memoryRelayedTransactions) Get(id uint) (*models.RelayedTransaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transaction, ok := r.s.relayed[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &transaction, nil
+}
+
+func (r memoryRelayedTransactions) ListByRequester(userID uint, page Page) ([]models.RelayedTransaction, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []models.RelayedTransaction
+	for _, transaction := range sortedValues(r.s.relayed) {
+		if transaction.RequestedBy == userID {
+			matches = append(matches, transaction)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].CreatedAt.After(matches[j].CreatedAt) })
+
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+func (r memoryRelayedTransactions) ListPending() ([]models.RelayedTransaction, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	transactions := []models.RelayedTransaction{}
+	for _, transaction := range sortedValues(r.s.relayed) {
+		if transaction.Status == models.RelayedTxPending {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if transactions[i].Signer != transactions[j].Signer {
+			return transactions[i].Signer < transactions[j].Signer
+		}
+		return transactions[i].Nonce < transactions[j].Nonce
+	})
+	return transactions, nil
+}
+
+func (r memoryRelayedTransactions) HighestPendingNonce(signer string) (uint64, bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var highest uint64
+	found := false
+	for _, transaction := range r.s.relayed {
+		if transaction.Signer != signer || transaction.Status != models.RelayedTxPending {
+			continue
+		}
+		if !found || transaction.Nonce > highest {
+			highest, found = transaction.Nonce, true
+		}
+	}
+	return highest, found, nil
+}
+
+func (r memoryRelayedTransactions) Create(transaction *models.RelayedTransaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.relayed {
+		if existing.TxHash == transaction.TxHash {
+			return ErrConflict
+		}
+	}
+	r.s.stamp(&transaction.Model)
+	r.s.relayed[transaction.ID] = *transaction
+	return nil
+}
+
+func (r memoryRelayedTransactions) Save(transaction *models.RelayedTransaction) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.stamp(&transaction.Model)
+	r.s.relayed[transaction.ID] = *transaction
+	return nil
+}
+
+type memoryAuditEvents struct{ s *MemoryStore }
+
+func (r memoryAuditEvents) Append(event *models.AuditEvent, seal func(event *models.AuditEvent, prevHash string)) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	prevHash := ""
+	if len(r.s.auditEvents) > 0 {
+		prevHash = r.s.auditEvents[len(r.s.auditEvents)-1].Hash
+	}
+	seal(event, prevHash)
+
+	// Audit events have their own sequence in the SQL store, they are appended in ID order
+	event.ID = uint(len(r.s.auditEvents)) + 1
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	r.s.auditEvents = append(r.s.auditEvents, *event)
+	return nil
+}
+
+func (r memoryAuditEvents) Head() (*models.AuditEvent, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if len(r.s.auditEvents) == 0 {
+		return nil, ErrNotFound
+	}
+	event := r.s.auditEvents[len(r.s.auditEvents)-1]
+	return &event, nil
+}
+
+// matching returns the events matching filter oldest first, the caller holds the lock
+func (r memoryAuditEvents) matching(filter AuditEventFilter) []models.AuditEvent {
+	var matches []models.AuditEvent
+	for _, event := range r.s.auditEvents {
+		switch {
+		case filter.ActorID != nil && (event.ActorID == nil || *event.ActorID != *filter.ActorID),
+			filter.Action != "" && event.Action != filter.Action,
+			filter.TargetType != "" && event.TargetType != filter.TargetType,
+			filter.TargetID != nil && (event.TargetID == nil || *event.TargetID != *filter.TargetID),
+			filter.From != nil && event.CreatedAt.Before(*filter.From),
+			filter.To != nil && !event.CreatedAt.Before(*filter.To):
+			continue
+		}
+		matches = append(matches, event)
+	}
+	return matches
+}
+
+func (r memoryAuditEvents) List(filter AuditEventFilter, page Page) ([]models.AuditEvent, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	matches := r.matching(filter)
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+
+	return paginate(matches, page), int64(len(matches)), nil
+}
+
+func (r memoryAuditEvents) Each(filter AuditEventFilter, fn func(event models.AuditEvent) error) error {
+	r.s.mu.RLock()
+	matches := r.matching(filter)
+	r.s.mu.RUnlock()
+
+	for _, event := range matches {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
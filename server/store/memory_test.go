@@ -0,0 +1,15 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/Brondont/trust-api/models"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testRepositories(t, func(t *testing.T) Store {
+		s := NewMemory()
+		s.AddSector(&models.Sector{Code: "construction"})
+		return s
+	})
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"math"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// Page selects a slice of a listing, Number starts at 1
+type Page struct {
+	Number int
+	Size   int
+}
+
+// PageFromQuery reads the page and limit query parameters, falling back to the defaults
+func PageFromQuery(query url.Values) Page {
+	number, _ := strconv.Atoi(query.Get("page"))
+	size, _ := strconv.Atoi(query.Get("limit"))
+
+	if number < 1 {
+		number = 1
+	}
+	if size < 1 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+
+	return Page{Number: number, Size: size}
+}
+
+// Offset is the number of records before the page
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// PageInfo describes a page of a listing in API responses
+type PageInfo struct {
+	CurrentPage  int   `json:"currentPage"`
+	TotalPages   int   `json:"totalPages"`
+	TotalItems   int64 `json:"totalItems"`
+	ItemsPerPage int   `json:"itemsPerPage"`
+}
+
+// NewPageInfo describes page within a listing of total records
+func NewPageInfo(page Page, total int64) PageInfo {
+	return PageInfo{
+		CurrentPage:  page.Number,
+		TotalPages:   int(math.Ceil(float64(total) / float64(page.Size))),
+		TotalItems:   total,
+		ItemsPerPage: page.Size,
+	}
+}
+
+// paginate returns the records of page within items
+func paginate[T any](items []T, page Page) []T {
+	start := page.Offset()
+	if start >= len(items) {
+		return []T{}
+	}
+	end := start + page.Size
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
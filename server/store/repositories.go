@@ -0,0 +1,321 @@
+package store
+
+import (
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+)
+
+// UserFilter narrows a user listing
+type UserFilter struct {
+	Search string // matched against names, email and phone number
+}
+
+// OfferFilter narrows an offer listing
+type OfferFilter struct {
+	Search        string // matched against the title
+	SectorCode    string
+	Location      string
+	IncludeClosed bool
+}
+
+// ChainTransactionFilter narrows a chain transaction listing, zero values match everything
+type ChainTransactionFilter struct {
+	UserID     *uint
+	Kind       string
+	Status     string
+	EntityType string
+	EntityID   *uint
+}
+
+// AuditEventFilter narrows an audit event listing, zero values match everything
+type AuditEventFilter struct {
+	ActorID    *uint
+	Action     string
+	TargetType string
+	TargetID   *uint
+	From       *time.Time // inclusive
+	To         *time.Time // exclusive
+}
+
+// UserRepository reads and writes users
+type UserRepository interface {
+	// Get returns the user with its roles and their permissions
+	Get(id uint) (*models.User, error)
+	// Profile returns the public fields of the user with its roles, without the password hash
+	Profile(id uint) (*models.User, error)
+	GetByEmail(email string) (*models.User, error)
+	// GetByWallet matches the wallet case-insensitively
+	GetByWallet(wallet string) (*models.User, error)
+	// List returns a page of users with their roles, without password hashes
+	List(filter UserFilter, page Page) ([]models.User, int64, error)
+	Create(user *models.User) error
+	// Save writes the user without its roles
+	Save(user *models.User) error
+	// Delete removes the user for good, with their role assignments and documents
+	Delete(user *models.User) error
+	EmailTaken(email string, exceptID uint) (bool, error)
+	WalletInUse(wallet string, exceptID uint) (bool, error)
+	// ListWithRoles returns every user with their roles, oldest first
+	ListWithRoles() ([]models.User, error)
+	// GrantRole assigns a role, recording the transaction that granted it on chain
+	GrantRole(userRole *models.UserRole) error
+	// RevokeRole removes a role assignment, ErrNotFound if the user did not hold the role
+	RevokeRole(userID uint, roleID uint) error
+	SetEmail(id uint, email string) error
+	SetPhoneNumber(id uint, phoneNumber string) error
+	// SetWallet binds the wallet to the user, an empty wallet unbinds it
+	SetWallet(id uint, wallet string) error
+	// ApplyRoleChange grants or revokes, as entry.Action says, the role of entry to its user and
+	// records entry in the role audit log in one transaction. txHash is the transaction that granted
+	// the role on chain, if known. It reports whether the role changed, nothing is recorded when the
+	// user already was in the requested state.
+	ApplyRoleChange(entry *models.RoleAuditLog, txHash string) (bool, error)
+	// GetQualification returns a qualification of a user without its relations
+	GetQualification(id uint) (*models.UserQualification, error)
+}
+
+// RoleRepository reads and writes roles
+type RoleRepository interface {
+	List() ([]models.Role, error)
+	Get(id uint) (*models.Role, error)
+	GetByName(name string) (*models.Role, error)
+	Create(role *models.Role) error
+	Save(role *models.Role) error
+	// Delete removes the role for good
+	Delete(role *models.Role) error
+	// CountUsers returns how many users hold the role
+	CountUsers(roleID uint) (int64, error)
+	// SetPermissions replaces the permissions the role grants
+	SetPermissions(role *models.Role, permissions []models.Permission) error
+}
+
+// PermissionRepository reads permissions, they are defined by migrations
+type PermissionRepository interface {
+	List() ([]models.Permission, error)
+	// GetByNames returns the named permissions, ErrNotFound if one of them does not exist
+	GetByNames(names []string) ([]models.Permission, error)
+}
+
+// OfferRepository reads and writes offers
+type OfferRepository interface {
+	// Get returns the offer with its documents, sector and expert assignments
+	Get(id uint) (*models.Offer, error)
+	// List returns a page of offers with their sector and creator, newest first
+	List(filter OfferFilter, page Page) ([]models.Offer, int64, error)
+	Create(offer *models.Offer) error
+	// CreateWithDocuments stores the offer and its documents in one transaction
+	CreateWithDocuments(offer *models.Offer, documents []models.Document) error
+	ContractExists(contractAddress string) (bool, error)
+	// GetByContract returns the offer deployed at the address, matched case-insensitively, without its relations
+	GetByContract(contractAddress string) (*models.Offer, error)
+	// ContractAddresses returns the contract address of every offer
+	ContractAddresses() ([]string, error)
+	// SetWindows records the time windows of the offer as its contract defines them
+	SetWindows(id uint, proposalStart, proposalEnd, reviewStart, reviewEnd time.Time) error
+	// ListOpen returns the offers that are not closed, without their relations
+	ListOpen() ([]models.Offer, error)
+	// MoveStatus sets the status of the offer to next if it still is current, reporting whether it moved
+	MoveStatus(id uint, current string, next string) (bool, error)
+	// ListExperts returns the experts assigned to the offer, without password hashes
+	ListExperts(offerID uint) ([]models.OfferExpert, error)
+	// AssignExpert assigns an expert to the offer, assigning them twice is a no-op
+	AssignExpert(assignment *models.OfferExpert) error
+	// UnassignExpert removes an assignment, ErrNotFound if the expert was not assigned
+	UnassignExpert(offerID uint, expertID uint) error
+	// DeclareWinner accepts the proposal of the winning wallet, rejects the other proposals and records
+	// the winner on the offer. It returns the winning proposal, nil if the winner never registered it.
+	DeclareWinner(offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error)
+	// Close marks the offer closed. closeOffer() declares the winner itself once the review period is
+	// over, a non-empty winnerWallet is applied as DeclareWinner does in the same transaction.
+	Close(offer *models.Offer, winnerWallet string, txHash string) (*models.Proposal, error)
+}
+
+// ProposalRepository reads and writes proposals
+type ProposalRepository interface {
+	// Get returns the proposal with its offer, the offer's expert assignments and its proposer
+	Get(id uint) (*models.Proposal, error)
+	// Exists reports whether the proposer already submitted to the offer or the transaction is already
+	// recorded. The proposal the indexer mirrored from txHash does not count, it is adopted on creation.
+	Exists(offerID uint, proposerID uint, txHash string) (bool, error)
+	// CreateWithDocuments stores the proposal and its documents in one transaction. The proposal the
+	// indexer mirrored from the same transaction is adopted instead of creating another one, it keeps
+	// its ID, status and submission time and proposal is filled with them.
+	CreateWithDocuments(proposal *models.Proposal, documents []models.Document) error
+	// GetByWallet returns the proposal of the offer submitted by the user bound to the wallet, matched
+	// case-insensitively
+	GetByWallet(offerID uint, wallet string) (*models.Proposal, error)
+	// UpsertIndexed stores a proposal read from the chain by the indexer. The proposal of the same
+	// proposer on the offer gets its details, price, transaction and block, a new one is created as
+	// indexed only with its status and submission time.
+	UpsertIndexed(proposal *models.Proposal) error
+	// ListByOffer returns the proposals of an offer with their proposer and evaluations, oldest first
+	ListByOffer(offerID uint) ([]models.Proposal, error)
+	// ListAwaitingReview returns the proposals the expert may still score at now, i.e. proposals of
+	// offers in their review window the expert has not evaluated yet, except their own. They come with
+	// their documents and offer with its expert assignments, closest review deadline first. Whether
+	// the expert is assigned is left to auth.CanEvaluate.
+	ListAwaitingReview(expertID uint, now time.Time) ([]models.Proposal, error)
+	// ListFromBlock returns the proposals of the offer whose transaction was mined at block or later
+	ListFromBlock(offerID uint, block uint64) ([]models.Proposal, error)
+	// SetBlock records the block the transaction of the proposal was mined in
+	SetBlock(id uint, number uint64, hash string) error
+	// Delete removes a proposal whose transaction left the chain
+	Delete(id uint) error
+}
+
+// EvaluationRepository reads and writes expert evaluations
+type EvaluationRepository interface {
+	// Exists reports whether the expert already evaluated the proposal or the transaction is already
+	// recorded. The evaluation the indexer mirrored from txHash does not count, it is adopted on creation.
+	Exists(proposalID uint, expertID uint, txHash string) (bool, error)
+	Create(evaluation *models.ExpertEvaluation) error
+	// UpsertIndexed stores an evaluation read from the chain by the indexer. The evaluation of the same
+	// expert on the proposal gets its score, transaction and block, a new one is created as indexed only.
+	UpsertIndexed(evaluation *models.ExpertEvaluation) error
+	// CreateWithTracking stores the evaluation and starts tracking its review transaction in one
+	// transaction, tracked is linked to the new evaluation. The evaluation the indexer mirrored from
+	// the same transaction is adopted instead of creating another one, it keeps its ID.
+	CreateWithTracking(evaluation *models.ExpertEvaluation, tracked *models.ChainTransaction) error
+	// ListByExpert returns a page of the evaluations of an expert with their proposal and offer, newest first
+	ListByExpert(expertID uint, page Page) ([]models.ExpertEvaluation, int64, error)
+	// ListFromBlock returns the evaluations of proposals of the offer whose transaction was mined at block or later
+	ListFromBlock(offerID uint, block uint64) ([]models.ExpertEvaluation, error)
+	// SetBlock records the block the transaction of the evaluation was mined in
+	SetBlock(id uint, number uint64, hash string) error
+	// Delete removes an evaluation whose transaction left the chain
+	Delete(id uint) error
+}
+
+// SectorRepository reads sectors
+type SectorRepository interface {
+	List() ([]models.Sector, error)
+	Exists(id uint) (bool, error)
+}
+
+// DocumentRepository reads and writes documents
+type DocumentRepository interface {
+	Get(id uint) (*models.Document, error)
+	Create(document *models.Document) error
+	// ListFor returns the documents attached to a record, e.g. ("Proposal", 3)
+	ListFor(documentableType string, documentableID uint) ([]models.Document, error)
+}
+
+// ChainTransactionRepository reads and tracks chain transactions, the transaction watcher follows them
+type ChainTransactionRepository interface {
+	// Track starts tracking a transaction. Tracking a known hash again updates its kind and fills in
+	// the user and the related record when they are set.
+	Track(transaction *models.ChainTransaction) error
+	// List returns a page of tracked transactions, newest first
+	List(filter ChainTransactionFilter, page Page) ([]models.ChainTransaction, int64, error)
+	// GetByHash matches the hash case-insensitively, hashes are stored lowercase
+	GetByHash(hash string) (*models.ChainTransaction, error)
+	// ListUnconfirmed returns the pending transactions and the mined or reverted ones with fewer than
+	// confirmations confirmations, oldest first
+	ListUnconfirmed(confirmations uint64) ([]models.ChainTransaction, error)
+	// Save writes what the watcher learned about a tracked transaction
+	Save(transaction *models.ChainTransaction) error
+}
+
+// ChainCursorRepository reads and moves the indexer cursors, one per indexed contract
+type ChainCursorRepository interface {
+	// Ensure returns the cursor of the contract, creating it at startBlock if missing
+	Ensure(contractAddress string, contractType string, startBlock uint64) (*models.ChainCursor, error)
+	// ListActive returns the cursors of the contract type that are not finished, oldest first
+	ListActive(contractType string) ([]models.ChainCursor, error)
+	Save(cursor *models.ChainCursor) error
+}
+
+// WalletChangeRequestRepository reads and reviews wallet change requests
+type WalletChangeRequestRepository interface {
+	// List returns a page of requests with the public fields of their user, oldest first.
+	// An empty status matches every request.
+	List(status string, page Page) ([]models.WalletChangeRequest, int64, error)
+	// HasPending reports whether the user has a request awaiting review
+	HasPending(userID uint) (bool, error)
+	Create(request *models.WalletChangeRequest) error
+	// Review locks the request and calls review with it and its user while other reviews wait, then
+	// saves the decision review made. An approved request applies its new wallet to the user, an
+	// empty one unbinds it. ErrNotFound if the request does not exist.
+	Review(id uint, review func(request *models.WalletChangeRequest, user *models.User) error) (*models.WalletChangeRequest, *models.User, error)
+}
+
+// RoleAuditLogRepository reads the role changes applied outside of admin requests
+type RoleAuditLogRepository interface {
+	// List returns a page of role changes, newest first. A nil userID matches every user.
+	List(userID *uint, page Page) ([]models.RoleAuditLog, int64, error)
+}
+
+// AuthSessionRepository reads and writes login sessions, their refresh tokens and revoked access tokens
+type AuthSessionRepository interface {
+	// Create stores a session with its first refresh token
+	Create(session *models.AuthSession, token *models.RefreshToken) error
+	// Rotate locks the refresh token with the hash and calls rotate with it, its session and the
+	// user of the session with its roles while other rotations of the token wait. The used_at of the
+	// token and the session are then saved and the token rotate returned is stored. Nothing is saved
+	// when rotate fails. ErrNotFound if the token, its session or the user does not exist.
+	Rotate(tokenHash string, rotate func(token *models.RefreshToken, session *models.AuthSession, user *models.User) (*models.RefreshToken, error)) error
+	// Get returns the session
+	Get(id uint) (*models.AuthSession, error)
+	// Revoke ends a session, ending it again is a no-op
+	Revoke(id uint, at time.Time) error
+	// RevokeAll ends every active session of a user and returns how many there were
+	RevokeAll(userID uint, at time.Time) (int64, error)
+	// RevokeToken adds an access token to the revocation list, revoking it again is a no-op
+	RevokeToken(revoked *models.RevokedToken) error
+	// TokenRevoked reports whether the access token with the jti was revoked
+	TokenRevoked(jti string) (bool, error)
+	// PurgeExpired deletes the revoked tokens expired at now, and the sessions and refresh tokens
+	// that expired before sessionCutoff
+	PurgeExpired(now time.Time, sessionCutoff time.Time) error
+}
+
+// WalletChallengeRepository stores and consumes the single-use nonces signed by wallets
+type WalletChallengeRepository interface {
+	Create(challenge *models.WalletChallenge) error
+	// GetUsable returns the challenge with the nonce and purpose issued to the user if it is neither
+	// used nor expired at now, ErrNotFound otherwise
+	GetUsable(nonce string, purpose string, userID uint, now time.Time) (*models.WalletChallenge, error)
+	// Consume marks the challenge used if it was issued for the wallet, the purpose and the user (nil
+	// when issued without a session) and is still usable at now, ErrNotFound otherwise.
+	// The wallet is matched case-insensitively.
+	Consume(nonce string, wallet string, purpose string, userID *uint, now time.Time) error
+	// PurgeExpired deletes the challenges expired at now, used or not
+	PurgeExpired(now time.Time) error
+}
+
+// CustodialWalletRepository reads and creates the wallets the relayer holds the keys of
+type CustodialWalletRepository interface {
+	// GetByUser returns the custodial wallet of the user
+	GetByUser(userID uint) (*models.CustodialWallet, error)
+	// Create stores the wallet and binds its address to its user. ErrConflict if the user already has
+	// a wallet bound or a custodial one, ErrNotFound if the user does not exist.
+	Create(wallet *models.CustodialWallet) error
+}
+
+// RelayedTransactionRepository reads and writes the transactions signed by the relayer
+type RelayedTransactionRepository interface {
+	Get(id uint) (*models.RelayedTransaction, error)
+	// ListByRequester returns a page of the transactions relayed for a user, newest first
+	ListByRequester(userID uint, page Page) ([]models.RelayedTransaction, int64, error)
+	// ListPending returns the pending transactions ordered by signer and nonce
+	ListPending() ([]models.RelayedTransaction, error)
+	// HighestPendingNonce returns the highest nonce among the pending transactions of the signer,
+	// false when it has none
+	HighestPendingNonce(signer string) (uint64, bool, error)
+	Create(transaction *models.RelayedTransaction) error
+	Save(transaction *models.RelayedTransaction) error
+}
+
+// AuditEventRepository appends and reads audit events, there is no way to change a recorded event
+type AuditEventRepository interface {
+	// Append stores event after the last one. seal is called with the hash of the last event while
+	// other appends wait, so every event links to the one recorded before it.
+	Append(event *models.AuditEvent, seal func(event *models.AuditEvent, prevHash string)) error
+	// Head returns the last event
+	Head() (*models.AuditEvent, error)
+	// List returns a page of events, newest first
+	List(filter AuditEventFilter, page Page) ([]models.AuditEvent, int64, error)
+	// Each calls fn for every event matching filter, oldest first, stopping at the first error
+	Each(filter AuditEventFilter, fn func(event models.AuditEvent) error) error
+}
@@ -0,0 +1,355 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Brondont/trust-api/models"
+)
+
+// storeFactory returns an empty store holding a single sector
+type storeFactory func(t *testing.T) Store
+
+// testRepositories runs the contracts every Store implementation honours against the stores
+// newStore returns
+func testRepositories(t *testing.T, newStore storeFactory) {
+	t.Run("DeclareWinner", func(t *testing.T) { testDeclareWinner(t, newStore) })
+	t.Run("Close", func(t *testing.T) { testClose(t, newStore) })
+	t.Run("AdoptProposal", func(t *testing.T) { testAdoptProposal(t, newStore) })
+	t.Run("CreateWithTracking", func(t *testing.T) { testCreateWithTracking(t, newStore) })
+}
+
+var (
+	submittedAt = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	proposalTx = "0x" + strings.Repeat("a1", 32)
+	rivalTx    = "0x" + strings.Repeat("b2", 32)
+	reviewTx   = "0x" + strings.Repeat("c3", 32)
+	winnerTx   = "0x" + strings.Repeat("d4", 32)
+	closeTx    = "0x" + strings.Repeat("e5", 32)
+)
+
+// contractFixture is an offer of tender with the users taking part in it, and another offer the
+// entrepreneur bid on that no change to the first one may touch
+type contractFixture struct {
+	store Store
+	offer models.Offer
+	other models.Offer
+
+	tender       models.User
+	entrepreneur models.User
+	competitor   models.User
+	expert       models.User
+}
+
+func newContractFixture(t *testing.T, newStore storeFactory) *contractFixture {
+	t.Helper()
+
+	f := &contractFixture{store: newStore(t)}
+	sectors, err := f.store.Sectors().List()
+	if err != nil || len(sectors) == 0 {
+		t.Fatalf("listing sectors: %v (%d found)", err, len(sectors))
+	}
+
+	for i, user := range []*models.User{&f.tender, &f.entrepreneur, &f.competitor, &f.expert} {
+		name := []string{"tender", "entrepreneur", "competitor", "expert"}[i]
+		// Wallets are stored checksummed, with upper case digits
+		*user = models.User{Email: name + "@example.com", PublicWalletAddress: fmt.Sprintf("0x%040X", 0xa0+i), IsActive: true}
+		if err := f.store.Users().Create(user); err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+
+	for i, offer := range []*models.Offer{&f.offer, &f.other} {
+		*offer = models.Offer{
+			TenderNumber:    fmt.Sprintf("T-%d", i+1),
+			Title:           "Road works",
+			ContractAddress: fmt.Sprintf("0x%040x", 0xf0+i),
+			ProposalStart:   submittedAt,
+			ProposalEnd:     submittedAt.Add(24 * time.Hour),
+			ReviewStart:     submittedAt.Add(48 * time.Hour),
+			ReviewEnd:       submittedAt.Add(72 * time.Hour),
+			Status:          models.OfferStatusNotStarted,
+			CreatedBy:       f.tender.ID,
+			SectorID:        sectors[0].ID,
+		}
+		if err := f.store.Offers().Create(offer); err != nil {
+			t.Fatalf("creating offer: %v", err)
+		}
+	}
+	f.submit(t, f.other, f.entrepreneur, "0x"+strings.Repeat("f6", 32))
+
+	return f
+}
+
+// submit records the proposal of proposer on offer as the API does
+func (f *contractFixture) submit(t *testing.T, offer models.Offer, proposer models.User, txHash string) models.Proposal {
+	t.Helper()
+	proposal := models.Proposal{
+		ContractID:     offer.ID,
+		ProposerID:     proposer.ID,
+		Details:        "road works",
+		Price:          "125000",
+		Status:         models.ProposalStatusPending,
+		SubmittedAt:    submittedAt,
+		ProposalTxHash: txHash,
+	}
+	documents := []models.Document{{DocumentType: "technicalDocument", DocumentPath: "uploads/technical.pdf"}}
+	if err := f.store.Proposals().CreateWithDocuments(&proposal, documents); err != nil {
+		t.Fatalf("creating proposal of user %d: %v", proposer.ID, err)
+	}
+	return proposal
+}
+
+func (f *contractFixture) reload(t *testing.T, offer models.Offer) models.Offer {
+	t.Helper()
+	stored, err := f.store.Offers().Get(offer.ID)
+	if err != nil {
+		t.Fatalf("loading offer %d: %v", offer.ID, err)
+	}
+	return *stored
+}
+
+// statuses returns the status of the proposals of offer by proposer
+func (f *contractFixture) statuses(t *testing.T, offer models.Offer) map[uint]string {
+	t.Helper()
+	proposals, err := f.store.Proposals().ListByOffer(offer.ID)
+	if err != nil {
+		t.Fatalf("listing proposals of offer %d: %v", offer.ID, err)
+	}
+	statuses := map[uint]string{}
+	for _, proposal := range proposals {
+		statuses[proposal.ProposerID] = proposal.Status
+	}
+	return statuses
+}
+
+// checkOutcome checks the proposals of the test offer and that the other offer kept its proposal pending
+func (f *contractFixture) checkOutcome(t *testing.T, wantEntrepreneur, wantCompetitor string) {
+	t.Helper()
+	statuses := f.statuses(t, f.offer)
+	if statuses[f.entrepreneur.ID] != wantEntrepreneur || statuses[f.competitor.ID] != wantCompetitor {
+		t.Errorf("got proposal statuses %v, want %s and %s", statuses, wantEntrepreneur, wantCompetitor)
+	}
+	if other := f.statuses(t, f.other)[f.entrepreneur.ID]; other != models.ProposalStatusPending {
+		t.Errorf("proposal on another offer moved to %s", other)
+	}
+}
+
+func testDeclareWinner(t *testing.T, newStore storeFactory) {
+	tests := []struct {
+		name       string
+		winner     func(f *contractFixture) models.User
+		wantWinner bool
+	}{
+		{name: "winner with a proposal", winner: func(f *contractFixture) models.User { return f.entrepreneur }, wantWinner: true},
+		{name: "winner without a proposal", winner: func(f *contractFixture) models.User { return f.expert }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newContractFixture(t, newStore)
+			winning := f.submit(t, f.offer, f.entrepreneur, proposalTx)
+			f.submit(t, f.offer, f.competitor, rivalTx)
+
+			// The chain reports wallets in any case
+			wallet := strings.ToLower(tt.winner(f).PublicWalletAddress)
+			proposal, err := f.store.Offers().DeclareWinner(&f.offer, wallet, winnerTx)
+			if err != nil {
+				t.Fatalf("declaring winner: %v", err)
+			}
+
+			offer := f.reload(t, f.offer)
+			if offer.Status != models.OfferStatusWinnerDeclared || offer.WinnerTxHash != winnerTx {
+				t.Errorf("got offer %s with winner transaction %q, want %s with %s", offer.Status, offer.WinnerTxHash, models.OfferStatusWinnerDeclared, winnerTx)
+			}
+
+			if !tt.wantWinner {
+				if proposal != nil || offer.WinningProposalID != nil {
+					t.Errorf("got winning proposal %+v (recorded %v), want none", proposal, offer.WinningProposalID)
+				}
+				f.checkOutcome(t, models.ProposalStatusPending, models.ProposalStatusPending)
+				return
+			}
+
+			if proposal == nil || proposal.ID != winning.ID {
+				t.Fatalf("got winning proposal %+v, want %d", proposal, winning.ID)
+			}
+			if offer.WinningProposalID == nil || *offer.WinningProposalID != winning.ID {
+				t.Errorf("got winning proposal %v recorded, want %d", offer.WinningProposalID, winning.ID)
+			}
+			f.checkOutcome(t, models.ProposalStatusAccepted, models.ProposalStatusRejected)
+		})
+	}
+}
+
+func testClose(t *testing.T, newStore storeFactory) {
+	tests := []struct {
+		name         string
+		declared     bool // the winner was declared before the offer closed
+		winner       bool // the close transaction names the winner
+		wantStatuses [2]string
+		wantWinner   bool
+	}{
+		{name: "closed with the winner", winner: true, wantStatuses: [2]string{models.ProposalStatusAccepted, models.ProposalStatusRejected}, wantWinner: true},
+		{name: "closed without a winner", wantStatuses: [2]string{models.ProposalStatusPending, models.ProposalStatusPending}},
+		{name: "closed after the winner was declared", declared: true, wantStatuses: [2]string{models.ProposalStatusAccepted, models.ProposalStatusRejected}, wantWinner: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newContractFixture(t, newStore)
+			winning := f.submit(t, f.offer, f.entrepreneur, proposalTx)
+			f.submit(t, f.offer, f.competitor, rivalTx)
+
+			if tt.declared {
+				if _, err := f.store.Offers().DeclareWinner(&f.offer, f.entrepreneur.PublicWalletAddress, winnerTx); err != nil {
+					t.Fatalf("declaring winner: %v", err)
+				}
+			}
+			wallet := ""
+			if tt.winner {
+				wallet = f.entrepreneur.PublicWalletAddress
+			}
+			proposal, err := f.store.Offers().Close(&f.offer, wallet, closeTx)
+			if err != nil {
+				t.Fatalf("closing offer: %v", err)
+			}
+			if (proposal != nil) != tt.winner {
+				t.Errorf("got winning proposal %+v returned, want one: %t", proposal, tt.winner)
+			}
+
+			offer := f.reload(t, f.offer)
+			if offer.Status != models.OfferStatusClosed || offer.CloseTxHash != closeTx {
+				t.Errorf("got offer %s with close transaction %q, want %s with %s", offer.Status, offer.CloseTxHash, models.OfferStatusClosed, closeTx)
+			}
+			if recorded := offer.WinningProposalID != nil && *offer.WinningProposalID == winning.ID; recorded != tt.wantWinner {
+				t.Errorf("winning proposal %v recorded, want proposal %d recorded: %t", offer.WinningProposalID, winning.ID, tt.wantWinner)
+			}
+			f.checkOutcome(t, tt.wantStatuses[0], tt.wantStatuses[1])
+		})
+	}
+}
+
+func testAdoptProposal(t *testing.T, newStore storeFactory) {
+	f := newContractFixture(t, newStore)
+
+	// The indexer mirrors the submission, the winner is declared from it before the entrepreneur
+	// uploads the documents
+	indexed := models.Proposal{
+		ContractID:     f.offer.ID,
+		ProposerID:     f.entrepreneur.ID,
+		Details:        "road works",
+		Price:          "125000",
+		Status:         models.ProposalStatusPending,
+		SubmittedAt:    submittedAt,
+		ProposalTxHash: proposalTx,
+		BlockNumber:    12,
+		BlockHash:      "0x" + strings.Repeat("12", 32),
+	}
+	if err := f.store.Proposals().UpsertIndexed(&indexed); err != nil {
+		t.Fatalf("indexing proposal: %v", err)
+	}
+	if !indexed.IndexedOnly {
+		t.Error("indexed proposal is not marked indexed only")
+	}
+	if _, err := f.store.Offers().DeclareWinner(&f.offer, f.entrepreneur.PublicWalletAddress, winnerTx); err != nil {
+		t.Fatalf("declaring winner: %v", err)
+	}
+
+	upperTx := strings.ToUpper(proposalTx[2:])
+	if exists, err := f.store.Proposals().Exists(f.offer.ID, f.entrepreneur.ID, "0x"+upperTx); err != nil || exists {
+		t.Errorf("got indexed submission counted as a duplicate: %t (%v)", exists, err)
+	}
+
+	f.submit(t, f.offer, f.entrepreneur, "0x"+upperTx)
+
+	adopted, err := f.store.Proposals().GetByWallet(f.offer.ID, f.entrepreneur.PublicWalletAddress)
+	if err != nil {
+		t.Fatalf("loading proposal: %v", err)
+	}
+	if adopted.ID != indexed.ID || adopted.IndexedOnly {
+		t.Errorf("got proposal %d (indexed only %t), want the indexed proposal %d adopted", adopted.ID, adopted.IndexedOnly, indexed.ID)
+	}
+	// Adoption keeps the outcome and the submission time the chain recorded
+	if adopted.Status != models.ProposalStatusAccepted || !adopted.SubmittedAt.Equal(submittedAt) {
+		t.Errorf("got status %s submitted at %s, want accepted at %s", adopted.Status, adopted.SubmittedAt, submittedAt)
+	}
+	if proposals, err := f.store.Proposals().ListByOffer(f.offer.ID); err != nil || len(proposals) != 1 {
+		t.Errorf("got %d proposals (%v), want the adopted one", len(proposals), err)
+	}
+	if documents, err := f.store.Documents().ListFor("Proposal", indexed.ID); err != nil || len(documents) != 1 {
+		t.Errorf("got %d documents on the adopted proposal (%v), want 1", len(documents), err)
+	}
+	if exists, err := f.store.Proposals().Exists(f.offer.ID, f.entrepreneur.ID, proposalTx); err != nil || !exists {
+		t.Errorf("adopted proposal is not counted as submitted: %t (%v)", exists, err)
+	}
+}
+
+func testCreateWithTracking(t *testing.T, newStore storeFactory) {
+	tests := []struct {
+		name        string
+		indexed     bool // the indexer mirrored the review first
+		tracked     bool // the review transaction was tracked without its evaluation
+		wantAdopted bool
+	}{
+		{name: "new review"},
+		{name: "review mirrored by the indexer", indexed: true, wantAdopted: true},
+		{name: "review transaction already tracked", tracked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newContractFixture(t, newStore)
+			proposal := f.submit(t, f.offer, f.entrepreneur, proposalTx)
+
+			var indexed models.ExpertEvaluation
+			if tt.indexed {
+				indexed = models.ExpertEvaluation{ProposalID: proposal.ID, ExpertID: f.expert.ID, Score: 8, ReviewTxHash: reviewTx, BlockNumber: 40}
+				if err := f.store.Evaluations().UpsertIndexed(&indexed); err != nil {
+					t.Fatalf("indexing evaluation: %v", err)
+				}
+			}
+			if tt.tracked {
+				if err := f.store.ChainTransactions().Track(&models.ChainTransaction{Hash: reviewTx, Kind: models.ChainTxProposalReviewed}); err != nil {
+					t.Fatalf("tracking review: %v", err)
+				}
+			}
+
+			upperTx := "0x" + strings.ToUpper(reviewTx[2:])
+			evaluation := models.ExpertEvaluation{ProposalID: proposal.ID, ExpertID: f.expert.ID, Score: 8, Comment: "solid costing", ReviewTxHash: upperTx}
+			tracked := models.ChainTransaction{Hash: upperTx, Kind: models.ChainTxProposalReviewed, UserID: &f.expert.ID}
+			if err := f.store.Evaluations().CreateWithTracking(&evaluation, &tracked); err != nil {
+				t.Fatalf("creating evaluation: %v", err)
+			}
+
+			if adopted := evaluation.ID == indexed.ID; adopted != tt.wantAdopted {
+				t.Errorf("got evaluation %d, indexed evaluation %d adopted: %t, want %t", evaluation.ID, indexed.ID, adopted, tt.wantAdopted)
+			}
+			evaluations, count, err := f.store.Evaluations().ListByExpert(f.expert.ID, Page{Number: 1, Size: 10})
+			if err != nil {
+				t.Fatalf("listing evaluations: %v", err)
+			}
+			if count != 1 || evaluations[0].ID != evaluation.ID || evaluations[0].IndexedOnly || evaluations[0].Comment != "solid costing" {
+				t.Errorf("got evaluations %+v, want evaluation %d with its comment", evaluations, evaluation.ID)
+			}
+
+			transactions, count, err := f.store.ChainTransactions().List(ChainTransactionFilter{Kind: models.ChainTxProposalReviewed}, Page{Number: 1, Size: 10})
+			if err != nil {
+				t.Fatalf("listing transactions: %v", err)
+			}
+			if count != 1 {
+				t.Fatalf("got %d review transactions tracked, want 1", count)
+			}
+			transaction := transactions[0]
+			if transaction.Hash != reviewTx || transaction.Status != models.ChainTxPending {
+				t.Errorf("got transaction %s %s, want %s pending", transaction.Hash, transaction.Status, reviewTx)
+			}
+			if transaction.EntityType != "Evaluation" || transaction.EntityID == nil || *transaction.EntityID != evaluation.ID {
+				t.Errorf("got transaction on %s %v, want evaluation %d", transaction.EntityType, transaction.EntityID, evaluation.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Errors returned by repositories
+var (
+	// ErrNotFound is returned when the requested record does not exist
+	ErrNotFound = errors.New("record not found")
+	// ErrConflict is returned when a record changed while an update of it was being decided
+	ErrConflict = errors.New("record changed concurrently")
+)
+
+// Store gives the API access to persistence without reaching for the db package global
+type Store interface {
+	Users() UserRepository
+	Roles() RoleRepository
+	Permissions() PermissionRepository
+	Offers() OfferRepository
+	Proposals() ProposalRepository
+	Evaluations() EvaluationRepository
+	Sectors() SectorRepository
+	Documents() DocumentRepository
+	ChainTransactions() ChainTransactionRepository
+	ChainCursors() ChainCursorRepository
+	WalletChangeRequests() WalletChangeRequestRepository
+	RoleAuditLogs() RoleAuditLogRepository
+	AuthSessions() AuthSessionRepository
+	WalletChallenges() WalletChallengeRepository
+	CustodialWallets() CustodialWalletRepository
+	RelayedTransactions() RelayedTransactionRepository
+	AuditEvents() AuditEventRepository
+}
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+// New returns a Store backed by a GORM connection
+func New(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Users() UserRepository             { return &gormUsers{db: s.db} }
+func (s *gormStore) Roles() RoleRepository             { return &gormRoles{db: s.db} }
+func (s *gormStore) Permissions() PermissionRepository { return &gormPermissions{db: s.db} }
+func (s *gormStore) Offers() OfferRepository           { return &gormOffers{db: s.db} }
+func (s *gormStore) Proposals() ProposalRepository     { return &gormProposals{db: s.db} }
+func (s *gormStore) Evaluations() EvaluationRepository { return &gormEvaluations{db: s.db} }
+func (s *gormStore) Sectors() SectorRepository         { return &gormSectors{db: s.db} }
+func (s *gormStore) Documents() DocumentRepository     { return &gormDocuments{db: s.db} }
+func (s *gormStore) ChainTransactions() ChainTransactionRepository {
+	return &gormChainTransactions{db: s.db}
+}
+func (s *gormStore) ChainCursors() ChainCursorRepository { return &gormChainCursors{db: s.db} }
+func (s *gormStore) WalletChangeRequests() WalletChangeRequestRepository {
+	return &gormWalletChangeRequests{db: s.db}
+}
+func (s *gormStore) RoleAuditLogs() RoleAuditLogRepository { return &gormRoleAuditLogs{db: s.db} }
+func (s *gormStore) AuthSessions() AuthSessionRepository   { return &gormAuthSessions{db: s.db} }
+func (s *gormStore) WalletChallenges() WalletChallengeRepository {
+	return &gormWalletChallenges{db: s.db}
+}
+func (s *gormStore) CustodialWallets() CustodialWalletRepository {
+	return &gormCustodialWallets{db: s.db}
+}
+func (s *gormStore) RelayedTransactions() RelayedTransactionRepository {
+	return &gormRelayedTransactions{db: s.db}
+}
+func (s *gormStore) AuditEvents() AuditEventRepository { return &gormAuditEvents{db: s.db} }
+
+// notFound maps the GORM not found error to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,15 @@
+package utils
+
+import "time"
+
+// Clock tells the current time, handlers use it so deadlines can be tested
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
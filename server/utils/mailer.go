@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"github.com/Brondont/trust-api/config"
+)
+
+// Mailer sends HTML emails
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	From     string
+	Password string
+	Host     string
+	Port     string
+}
+
+// NewSMTPMailer returns a mailer using the SMTP settings of cfg
+func NewSMTPMailer(cfg config.Config) *SMTPMailer {
+	return &SMTPMailer{
+		From:     cfg.EmailSender,
+		Password: cfg.EmailPassword,
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+	}
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	// Set up authentication
+	auth := smtp.PlainAuth("", m.From, m.Password, m.Host)
+
+	// Construct email headers
+	headers := make(map[string]string)
+	headers["From"] = m.From
+	headers["To"] = to
+	headers["Subject"] = subject
+	headers["MIME-Version"] = "1.0"
+	headers["Content-Type"] = "text/html; charset=UTF-8"
+
+	// Construct message
+	var message strings.Builder
+	for key, value := range headers {
+		message.WriteString(fmt.Sprintf("%s: %s\r\n", key, value))
+	}
+	message.WriteString("\r\n")
+	message.WriteString(body)
+
+	// Send email
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{to}, []byte(message.String()))
+}
@@ -5,34 +5,40 @@ import (
 	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"math/rand"
 	"mime/multipart"
+	"net"
 	"net/http"
-	"net/smtp"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
-	"github.com/Brondont/trust-api/config"
-
 	"github.com/Brondont/trust-api/middleware"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type ErrorResponse struct {
 	Error struct {
-		Msg string `json:"msg"`
+		Msg  string `json:"msg"`
+		Code string `json:"code,omitempty"`
 	} `json:"error"`
 }
 
+// codedError is an error with a stable code clients can match on, e.g. a decoded contract revert
+type codedError interface {
+	ErrorCode() string
+}
+
 type MultiPartFormData struct {
-	Fields map[string][]string
-	File   *multipart.FileHeader
-	Files  []*multipart.FileHeader
-	Model  *multipart.FileHeader
+	Fields     map[string][]string
+	FileFields map[string][]*multipart.FileHeader
+	File       *multipart.FileHeader
+	Files      []*multipart.FileHeader
+	Model      *multipart.FileHeader
 }
 
 func ParseJson(r *http.Request, payload any) error {
@@ -51,6 +57,12 @@ func WriteJson(w http.ResponseWriter, status int, v any) error {
 func WriteError(w http.ResponseWriter, status int, err error) {
 	response := ErrorResponse{}
 	response.Error.Msg = err.Error()
+
+	var coded codedError
+	if errors.As(err, &coded) {
+		response.Error.Code = coded.ErrorCode()
+	}
+
 	WriteJson(w, status, response)
 }
 
@@ -70,6 +82,15 @@ func WriteInputValidationError(w http.ResponseWriter, status int, err interface{
 	WriteJson(w, status, response)
 }
 
+// ClientIP returns the address of the caller, without the port
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 	return string(bytes), err
@@ -125,7 +146,8 @@ func ParseMultipartForm(r *http.Request, maxMemory int64) (*MultiPartFormData, e
 	}
 
 	data := &MultiPartFormData{
-		Fields: make(map[string][]string),
+		Fields:     make(map[string][]string),
+		FileFields: make(map[string][]*multipart.FileHeader),
 	}
 
 	// Get form fields - handles both Form and PostForm
@@ -135,6 +157,13 @@ func ParseMultipartForm(r *http.Request, maxMemory int64) (*MultiPartFormData, e
 		}
 	}
 
+	// Keep every file field so callers can handle typed document bundles
+	for key, files := range r.MultipartForm.File {
+		if len(files) > 0 {
+			data.FileFields[key] = files
+		}
+	}
+
 	// Get the first file if it exists
 
 	if fileHeader, ok := r.MultipartForm.File["image"]; ok && len(fileHeader) > 0 {
@@ -201,8 +230,9 @@ func SaveUploadedFile(file *multipart.FileHeader, destDir string) (string, error
 	}
 	defer dst.Close()
 
-	// Copy the content from the source to the destination file
+	// Copy the content from the source to the destination file, a partial copy is not kept
 	if _, err = io.Copy(dst, src); err != nil {
+		os.Remove(filePath)
 		return "", fmt.Errorf("copy file: %w", err)
 	}
 
@@ -210,38 +240,35 @@ func SaveUploadedFile(file *multipart.FileHeader, destDir string) (string, error
 	return destDir + "/" + filename, nil
 }
 
-func SendEmail(to, subject, body string) error {
-	from := config.Envs.EmailSender
-	password := config.Envs.EmailPassword
-	smtpHost := config.Envs.SMTPHost
-	smtpPort := config.Envs.SMTPPort
-
-	// Set up authentication
-	auth := smtp.PlainAuth("", from, password, smtpHost)
-
-	// Construct email headers
-	headers := make(map[string]string)
-	headers["From"] = from
-	headers["To"] = to
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/html; charset=UTF-8"
-
-	// Construct message
-	var message strings.Builder
-	for key, value := range headers {
-		message.WriteString(fmt.Sprintf("%s: %s\r\n", key, value))
+// SaveUploadedFiles saves every file to destDir and returns their paths in order. If one of them
+// cannot be saved the ones already written are removed again.
+func SaveUploadedFiles(files []*multipart.FileHeader, destDir string) ([]string, error) {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		path, err := SaveUploadedFile(file, destDir)
+		if err != nil {
+			RemoveUploadedFiles(paths)
+			return nil, err
+		}
+		paths = append(paths, path)
 	}
-	message.WriteString("\r\n")
-	message.WriteString(body)
+	return paths, nil
+}
 
-	// Send email
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, from, []string{to}, []byte(message.String()))
+// RemoveUploadedFiles deletes files written by SaveUploadedFile, e.g. when the record they belong to
+// could not be stored. Failures are only logged, the caller is already handling an error.
+func RemoveUploadedFiles(paths []string) {
+	cwd, err := os.Getwd()
 	if err != nil {
-		return err
+		log.Printf("Failed to remove %d uploaded file(s): %v", len(paths), err)
+		return
 	}
 
-	return nil
+	for _, path := range paths {
+		if err := os.Remove(filepath.Join(cwd, path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to remove uploaded file %s: %v", path, err)
+		}
+	}
 }
 
 // HashSHA256: hashes a string using SHA-256 and returns the hex-encoded result.